@@ -0,0 +1,499 @@
+package sql
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// An ApplyFunc is invoked by Apply for each node, before and/or after the
+// node's children, using a Cursor describing the current node and providing
+// operations on it.
+//
+// The return value of ApplyFunc controls the syntax tree traversal.
+// See Apply for details.
+type ApplyFunc func(*Cursor) bool
+
+// Apply traverses a syntax tree recursively, starting with root, and calling
+// pre and post for each non-nil node. Children are traversed in the order in
+// which they appear in the node's struct definition.
+//
+// If pre is not nil, it is called for each node before the node's children
+// are traversed (pre-order). If pre returns false, no children are traversed,
+// and post is not called for that node.
+//
+// If post is not nil, and a prior call of pre didn't return false, post is
+// called for each node after its children are traversed (post-order). If post
+// returns false, traversal is terminated and Apply returns immediately.
+//
+// Only fields that refer to AST nodes are considered children. Comments are
+// not traversed. The cursor exposes the ancestors of the current node, so pre
+// and post can also be used as enter and leave hooks of a read-only traversal.
+//
+// Apply returns the syntax tree, possibly modified. If the root node was
+// replaced by the cursor, the new root is returned.
+func Apply(root Node, pre, post ApplyFunc) (result Node) {
+	parent := &struct{ Node }{root}
+	defer func() {
+		if r := recover(); r != nil && r != errAbort {
+			panic(r)
+		}
+		result = parent.Node
+	}()
+	a := &application{pre: pre, post: post}
+	a.apply(parent, "Node", nil, root)
+	return
+}
+
+var errAbort = new(int) // singleton, to signal termination of Apply
+
+// A Cursor describes a node encountered during Apply.
+// Information about the node and its parent is available
+// from the Node, Parent, Name, and Index methods.
+//
+// If p is a variable of type and value of the current parent node
+// c.Parent(), and f is the field identifier with name c.Name(),
+// the following invariants hold:
+//
+//	p.f            == c.Node()  if c.Index() <  0
+//	p.f[c.Index()] == c.Node()  if c.Index() >= 0
+//
+// The methods Replace, Delete, InsertBefore, and InsertAfter
+// can be used to change the AST without disrupting Apply.
+type Cursor struct {
+	parent Node
+	name   string
+	iter   *iterator // valid if non-nil
+	node   Node
+	stack  []Node // ancestors of node, root first
+}
+
+// Node returns the current Node.
+func (c *Cursor) Node() Node { return c.node }
+
+// Parent returns the parent of the current Node.
+func (c *Cursor) Parent() Node { return c.parent }
+
+// Name returns the name of the parent Node field that contains the current
+// Node. If the parent is a *SelectStatement and the current Node is its
+// WHERE expression, Name returns "WhereExpr".
+func (c *Cursor) Name() string { return c.name }
+
+// Stack returns the ancestors of the current Node, starting with the root
+// passed to Apply and ending with its parent. The slice is only valid during
+// the current call of pre or post and must not be modified.
+func (c *Cursor) Stack() []Node { return c.stack }
+
+// Depth returns the number of ancestors of the current Node. The root passed
+// to Apply has depth 0.
+func (c *Cursor) Depth() int { return len(c.stack) }
+
+// Index reports the index >= 0 of the current Node in the slice of Nodes that
+// contains it, or a value < 0 if the current Node is not part of a slice.
+// The index of the current node changes if InsertBefore is called while
+// processing the current node.
+func (c *Cursor) Index() int {
+	if c.iter != nil {
+		return c.iter.index
+	}
+	return -1
+}
+
+// field returns the current node's parent field value.
+func (c *Cursor) field() reflect.Value {
+	return reflect.Indirect(reflect.ValueOf(c.parent)).FieldByName(c.name)
+}
+
+// Replace replaces the current Node with n. If n is nil, the parent field
+// is cleared. If Replace is called from pre, the children of n are traversed
+// instead of the children of the original node.
+// Replace panics if n is not assignable to the parent field.
+func (c *Cursor) Replace(n Node) {
+	v := c.field()
+	if i := c.Index(); i >= 0 {
+		v = v.Index(i)
+	}
+	if n == nil {
+		v.Set(reflect.Zero(v.Type()))
+	} else {
+		v.Set(reflect.ValueOf(n))
+	}
+	c.node = n
+}
+
+// Delete deletes the current Node from its containing slice.
+// If the current Node is not part of a slice, Delete panics.
+func (c *Cursor) Delete() {
+	i := c.Index()
+	if i < 0 {
+		panic("Delete node not contained in slice")
+	}
+	v := c.field()
+	l := v.Len()
+	reflect.Copy(v.Slice(i, l), v.Slice(i+1, l))
+	v.Index(l - 1).Set(reflect.Zero(v.Type().Elem()))
+	v.SetLen(l - 1)
+	c.iter.step--
+}
+
+// InsertAfter inserts n after the current Node in its containing slice.
+// If the current Node is not part of a slice, InsertAfter panics.
+// Apply does not walk n.
+func (c *Cursor) InsertAfter(n Node) {
+	i := c.Index()
+	if i < 0 {
+		panic("InsertAfter node not contained in slice")
+	}
+	v := c.field()
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	l := v.Len()
+	reflect.Copy(v.Slice(i+2, l), v.Slice(i+1, l))
+	v.Index(i + 1).Set(reflect.ValueOf(n))
+	c.iter.step++
+}
+
+// InsertBefore inserts n before the current Node in its containing slice.
+// If the current Node is not part of a slice, InsertBefore panics.
+// Apply does not walk n.
+func (c *Cursor) InsertBefore(n Node) {
+	i := c.Index()
+	if i < 0 {
+		panic("InsertBefore node not contained in slice")
+	}
+	v := c.field()
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	l := v.Len()
+	reflect.Copy(v.Slice(i+1, l), v.Slice(i, l))
+	v.Index(i).Set(reflect.ValueOf(n))
+	c.iter.index++
+}
+
+// application carries all the shared data so we can pass it around cheaply.
+type application struct {
+	pre, post ApplyFunc
+	cursor    Cursor
+	iter      iterator
+	stack     []Node
+}
+
+func (a *application) apply(parent Node, name string, iter *iterator, n Node) {
+	if n == nil || !n.node() {
+		return
+	}
+
+	// avoid heap-allocating a new cursor for each apply call; reuse a.cursor instead
+	saved := a.cursor
+	a.cursor.parent = parent
+	a.cursor.name = name
+	a.cursor.iter = iter
+	a.cursor.node = n
+	a.cursor.stack = a.stack
+
+	if a.pre != nil && !a.pre(&a.cursor) {
+		a.cursor = saved
+		return
+	}
+
+	// walk children
+	a.stack = append(a.stack, a.cursor.node)
+	switch n := a.cursor.node.(type) {
+	case nil:
+		// nothing to do
+	case *BeginStatement, *CommitStatement, *ForeignKeyArg, *Ident, *StringLit, *TimestampLit, *BlobLit, *NumberLit, *NullLit, *BoolLit, *BindExpr, *JoinOperator, *ConflictClause:
+		// nothing to do
+	case *ExplainStatement:
+		a.apply(n, "Stmt", nil, n.Stmt)
+	case *RollbackStatement:
+		a.apply(n, "SavepointName", nil, n.SavepointName)
+	case *SavepointStatement:
+		a.apply(n, "Name", nil, n.Name)
+	case *ReleaseStatement:
+		a.apply(n, "Name", nil, n.Name)
+	case *CreateTableStatement:
+		a.apply(n, "Name", nil, n.Name)
+		a.applyList(n, "Columns")
+		a.applyList(n, "Constraints")
+		a.apply(n, "Select", nil, n.Select)
+	case *ColumnDefinition:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Type", nil, n.Type)
+		a.applyList(n, "Constraints")
+	case *PrimaryKeyConstraint:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Conflict", nil, n.Conflict)
+		a.applyList(n, "Columns")
+	case *NotNullConstraint:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Conflict", nil, n.Conflict)
+	case *UniqueConstraint:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Conflict", nil, n.Conflict)
+		a.applyList(n, "Columns")
+	case *CheckConstraint:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Expr", nil, n.Expr)
+	case *DefaultConstraint:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Expr", nil, n.Expr)
+	case *GeneratedConstraint:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Expr", nil, n.Expr)
+	case *CollateConstraint:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Collation", nil, n.Collation)
+	case *ForeignKeyConstraint:
+		a.apply(n, "Name", nil, n.Name)
+		a.applyList(n, "Columns")
+		a.apply(n, "ForeignTable", nil, n.ForeignTable)
+		a.applyList(n, "ForeignColumns")
+		a.applyList(n, "Args")
+	case *CreateVirtualTableStatement:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "ModuleName", nil, n.ModuleName)
+		a.applyList(n, "Arguments")
+	case *ModuleArgument:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Literal", nil, n.Literal)
+		a.apply(n, "Type", nil, n.Type)
+	case *AnalyzeStatement:
+		a.apply(n, "Name", nil, n.Name)
+	case *ReindexStatement:
+		a.apply(n, "Name", nil, n.Name)
+	case *AlterTableStatement:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "NewName", nil, n.NewName)
+		a.apply(n, "ColumnName", nil, n.ColumnName)
+		a.apply(n, "NewColumnName", nil, n.NewColumnName)
+		a.apply(n, "ColumnDef", nil, n.ColumnDef)
+		a.apply(n, "DropColumnName", nil, n.DropColumnName)
+	case *Type:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Precision", nil, n.Precision)
+		a.apply(n, "Scale", nil, n.Scale)
+	case *UnaryExpr:
+		a.apply(n, "X", nil, n.X)
+	case *BinaryExpr:
+		a.apply(n, "X", nil, n.X)
+		a.apply(n, "Y", nil, n.Y)
+	case *CastExpr:
+		a.apply(n, "X", nil, n.X)
+		a.apply(n, "Type", nil, n.Type)
+	case *CaseExpr:
+		a.apply(n, "Operand", nil, n.Operand)
+		a.applyList(n, "Blocks")
+		a.apply(n, "ElseExpr", nil, n.ElseExpr)
+	case *CaseBlock:
+		a.apply(n, "Condition", nil, n.Condition)
+		a.apply(n, "Body", nil, n.Body)
+	case *Raise:
+		a.apply(n, "Error", nil, n.Error)
+	case *Exists:
+		a.apply(n, "Select", nil, n.Select)
+	case *Null:
+		a.apply(n, "X", nil, n.X)
+	case *ExprList:
+		a.applyList(n, "Exprs")
+	case *QualifiedRef:
+		a.apply(n, "Table", nil, n.Table)
+		a.apply(n, "Column", nil, n.Column)
+	case *Call:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Filter", nil, n.Filter)
+		a.apply(n, "OverName", nil, n.OverName)
+		a.apply(n, "OverWindow", nil, n.OverWindow)
+	case *OrderingTerm:
+		a.apply(n, "X", nil, n.X)
+	case *FrameSpec:
+		a.apply(n, "X", nil, n.X)
+		a.apply(n, "Y", nil, n.Y)
+	case *DropTableStatement:
+		a.apply(n, "Name", nil, n.Name)
+	case *CreateViewStatement:
+		a.apply(n, "Name", nil, n.Name)
+		a.applyList(n, "Columns")
+		a.apply(n, "Select", nil, n.Select)
+	case *DropViewStatement:
+		a.apply(n, "Name", nil, n.Name)
+	case *CreateIndexStatement:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Table", nil, n.Table)
+		a.applyList(n, "Columns")
+		a.apply(n, "WhereExpr", nil, n.WhereExpr)
+	case *DropIndexStatement:
+		a.apply(n, "Name", nil, n.Name)
+	case *CreateTriggerStatement:
+		a.apply(n, "Name", nil, n.Name)
+		a.applyList(n, "UpdateOfColumns")
+		a.apply(n, "Table", nil, n.Table)
+		a.apply(n, "WhenExpr", nil, n.WhenExpr)
+		a.applyList(n, "Body")
+	case *DropTriggerStatement:
+		a.apply(n, "Name", nil, n.Name)
+	case *InsertStatement:
+		a.apply(n, "WithClause", nil, n.WithClause)
+		a.apply(n, "Table", nil, n.Table)
+		a.applyList(n, "Columns")
+		a.applyList(n, "ValueLists")
+		a.apply(n, "Select", nil, n.Select)
+		a.apply(n, "UpsertClause", nil, n.UpsertClause)
+		a.applyList(n, "ReturningColumns")
+	case *UpsertClause:
+		a.applyList(n, "Columns")
+		a.apply(n, "WhereExpr", nil, n.WhereExpr)
+		a.applyList(n, "Assignments")
+		a.apply(n, "UpdateWhereExpr", nil, n.UpdateWhereExpr)
+	case *UpdateStatement:
+		a.apply(n, "WithClause", nil, n.WithClause)
+		a.apply(n, "Table", nil, n.Table)
+		a.applyList(n, "Assignments")
+		a.apply(n, "Source", nil, n.Source)
+		a.apply(n, "WhereExpr", nil, n.WhereExpr)
+		a.applyList(n, "ReturningColumns")
+		a.applyList(n, "OrderingTerms")
+		a.apply(n, "LimitExpr", nil, n.LimitExpr)
+		a.apply(n, "OffsetExpr", nil, n.OffsetExpr)
+	case *DeleteStatement:
+		a.apply(n, "WithClause", nil, n.WithClause)
+		a.apply(n, "Table", nil, n.Table)
+		a.apply(n, "WhereExpr", nil, n.WhereExpr)
+		a.applyList(n, "ReturningColumns")
+		a.applyList(n, "OrderingTerms")
+		a.apply(n, "LimitExpr", nil, n.LimitExpr)
+		a.apply(n, "OffsetExpr", nil, n.OffsetExpr)
+	case *Assignment:
+		a.applyList(n, "Columns")
+		a.apply(n, "Expr", nil, n.Expr)
+	case *IndexedColumn:
+		a.apply(n, "X", nil, n.X)
+	case *SelectStatement:
+		a.apply(n, "WithClause", nil, n.WithClause)
+		a.applyList(n, "ValueLists")
+		a.applyList(n, "Columns")
+		a.apply(n, "Source", nil, n.Source)
+		a.apply(n, "WhereExpr", nil, n.WhereExpr)
+		a.applyList(n, "GroupByExprs")
+		a.apply(n, "HavingExpr", nil, n.HavingExpr)
+		a.applyList(n, "Windows")
+		a.apply(n, "Compound", nil, n.Compound)
+		a.applyList(n, "OrderingTerms")
+		a.apply(n, "LimitExpr", nil, n.LimitExpr)
+		a.apply(n, "OffsetExpr", nil, n.OffsetExpr)
+	case *ResultColumn:
+		a.apply(n, "Expr", nil, n.Expr)
+		a.apply(n, "Alias", nil, n.Alias)
+	case *QualifiedName:
+		a.apply(n, "Schema", nil, n.Schema)
+		a.apply(n, "Name", nil, n.Name)
+		a.applyList(n, "FunctionArgs")
+		a.apply(n, "Alias", nil, n.Alias)
+		a.apply(n, "Index", nil, n.Index)
+	case *ParenSource:
+		a.apply(n, "X", nil, n.X)
+		a.apply(n, "Alias", nil, n.Alias)
+	case *JoinClause:
+		a.apply(n, "X", nil, n.X)
+		a.apply(n, "Operator", nil, n.Operator)
+		a.apply(n, "Y", nil, n.Y)
+		a.apply(n, "Constraint", nil, n.Constraint)
+	case *OnConstraint:
+		a.apply(n, "X", nil, n.X)
+	case *UsingConstraint:
+		a.applyList(n, "Columns")
+	case *WithClause:
+		a.applyList(n, "CTEs")
+	case *CTE:
+		a.apply(n, "TableName", nil, n.TableName)
+		a.applyList(n, "Columns")
+		a.apply(n, "Select", nil, n.Select)
+	case *Window:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Definition", nil, n.Definition)
+	case *WindowDefinition:
+		a.apply(n, "Base", nil, n.Base)
+		a.applyList(n, "Partitions")
+		a.applyList(n, "OrderingTerms")
+		a.apply(n, "Frame", nil, n.Frame)
+	case *PragmaStatement:
+		a.apply(n, "Schema", nil, n.Schema)
+		a.apply(n, "Expr", nil, n.Expr)
+	case *AttachStatement:
+		a.apply(n, "Expr", nil, n.Expr)
+		a.apply(n, "Schema", nil, n.Schema)
+	case *DetachStatement:
+		a.apply(n, "Schema", nil, n.Schema)
+	case *VacuumStatement:
+		a.apply(n, "Schema", nil, n.Schema)
+		a.apply(n, "Expr", nil, n.Expr)
+	case *FunctionArg:
+		a.apply(n, "Expr", nil, n.Expr)
+		a.applyList(n, "OrderingTerms")
+	case *InExpr:
+		a.apply(n, "X", nil, n.X)
+		a.apply(n, "Select", nil, n.Select)
+		a.apply(n, "Values", nil, n.Values)
+		a.apply(n, "TableOrFunction", nil, n.TableOrFunction)
+	case *ParenExpr:
+		a.apply(n, "Expr", nil, n.Expr)
+	default:
+		panic(fmt.Sprintf("Apply: unexpected node type %T", n))
+	}
+	a.stack = a.stack[:len(a.stack)-1]
+
+	if a.post != nil && !a.post(&a.cursor) {
+		panic(errAbort)
+	}
+
+	a.cursor = saved
+}
+
+// An iterator controls iteration over a slice of nodes.
+type iterator struct {
+	index, step int
+}
+
+func (a *application) applyList(parent Node, name string) {
+	// avoid heap-allocating a new iterator for each applyList call; reuse a.iter instead
+	saved := a.iter
+	a.iter.index = 0
+	for {
+		// must reload parent.name each time, since cursor modifications might change it
+		v := reflect.Indirect(reflect.ValueOf(parent)).FieldByName(name)
+		if a.iter.index >= v.Len() {
+			break
+		}
+
+		// element x may be nil in a bad AST - be cautious
+		var x Node
+		if e := v.Index(a.iter.index); e.IsValid() && e.CanInterface() && !e.IsNil() {
+			x = e.Interface().(Node)
+		}
+
+		a.iter.step = 1
+		a.apply(parent, name, &a.iter, x)
+		a.iter.index += a.iter.step
+	}
+	a.iter = saved
+}
+
+// Traverse returns an iterator over the nodes of the syntax tree rooted at
+// root. Each node is yielded twice: once with true before its children are
+// traversed and once with false after them. The cursor can be used to inspect
+// the ancestors of the node or to modify the tree as with Apply.
+//
+//	for c, enter := range sql.Traverse(stmt) {
+//		if enter && c.Name() == "WhereExpr" {
+//			fmt.Println(c.Depth(), c.Node())
+//		}
+//	}
+func Traverse(root Node) iter.Seq2[*Cursor, bool] {
+	return func(yield func(*Cursor, bool) bool) {
+		Apply(root, func(c *Cursor) bool {
+			if !yield(c, true) {
+				panic(errAbort)
+			}
+			return true
+		}, func(c *Cursor) bool {
+			return yield(c, false)
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package sql_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TcMits/sql"
+	"github.com/go-test/deep"
+)
+
+func Test_Apply(t *testing.T) {
+	parse := func(s string) sql.Statement {
+		t.Helper()
+		stmt, err := sql.ParseStmtString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stmt
+	}
+
+	t.Run("VisitsAllNodes", func(t *testing.T) {
+		stmt := parse(`WITH x AS (SELECT a FROM t) SELECT x.a, (SELECT 1) FROM x JOIN y ON x.a = y.a WHERE a IN (1, 2) ORDER BY 1 LIMIT 10`)
+
+		var walked, applied int
+		sql.Walk(stmt, func(sql.Node) bool {
+			walked++
+			return true
+		})
+		sql.Apply(stmt, func(*sql.Cursor) bool {
+			applied++
+			return true
+		}, nil)
+		if walked != applied {
+			t.Fatalf("walked %d nodes, applied %d nodes", walked, applied)
+		}
+	})
+
+	t.Run("ReplaceBind", func(t *testing.T) {
+		stmt := parse(`SELECT * FROM t WHERE a = ? AND b = ?`)
+		var n int
+		sql.Apply(stmt, func(c *sql.Cursor) bool {
+			if _, ok := c.Node().(*sql.BindExpr); ok {
+				n++
+				c.Replace(&sql.NumberLit{Value: "42"})
+			}
+			return true
+		}, nil)
+		if n != 2 {
+			t.Fatalf("expected 2 binds, got %d", n)
+		}
+		AssertStatementStringer(t, stmt, `SELECT * FROM "t" WHERE "a" = 42 AND "b" = 42`)
+	})
+
+	t.Run("WrapWhere", func(t *testing.T) {
+		stmt := parse(`SELECT * FROM t WHERE a = 1 OR b = 2`)
+		sql.Apply(stmt, func(c *sql.Cursor) bool {
+			if c.Name() == "WhereExpr" {
+				c.Replace(&sql.BinaryExpr{
+					X:  &sql.ParenExpr{Expr: c.Node().(sql.Expr)},
+					Op: sql.OP_AND,
+					Y:  &sql.BinaryExpr{X: &sql.Ident{Name: "tenant_id"}, Op: sql.OP_EQ, Y: &sql.NumberLit{Value: "1"}},
+				})
+			}
+			return true
+		}, nil)
+		AssertStatementStringer(t, stmt, `SELECT * FROM "t" WHERE ("a" = 1 OR "b" = 2) AND "tenant_id" = 1`)
+	})
+
+	t.Run("ClearWhere", func(t *testing.T) {
+		stmt := parse(`DELETE FROM t WHERE a = 1`)
+		sql.Apply(stmt, func(c *sql.Cursor) bool {
+			if c.Name() == "WhereExpr" {
+				c.Replace(nil)
+			}
+			return true
+		}, nil)
+		AssertStatementStringer(t, stmt, `DELETE FROM "t"`)
+	})
+
+	t.Run("EditColumns", func(t *testing.T) {
+		stmt := parse(`SELECT a, b, c FROM t`)
+		sql.Apply(stmt, func(c *sql.Cursor) bool {
+			col, ok := c.Node().(*sql.ResultColumn)
+			if !ok {
+				return true
+			}
+			switch col.Expr.(*sql.Ident).Name {
+			case "a":
+				c.InsertBefore(&sql.ResultColumn{Expr: &sql.Ident{Name: "z"}})
+			case "b":
+				c.Delete()
+			case "c":
+				c.InsertAfter(&sql.ResultColumn{Expr: &sql.Ident{Name: "d"}})
+			}
+			return false
+		}, nil)
+		AssertStatementStringer(t, stmt, `SELECT "z", "a", "c", "d" FROM "t"`)
+	})
+
+	t.Run("EditValueLists", func(t *testing.T) {
+		stmt := parse(`INSERT INTO t VALUES (1), (2), (3)`)
+		sql.Apply(stmt, func(c *sql.Cursor) bool {
+			if list, ok := c.Node().(*sql.ExprList); ok && list.Exprs[0].String() == "2" {
+				c.Delete()
+			}
+			return true
+		}, nil)
+		AssertStatementStringer(t, stmt, `INSERT INTO "t" VALUES (1), (3)`)
+	})
+
+	t.Run("EditTriggerBody", func(t *testing.T) {
+		stmt := parse(`CREATE TRIGGER tr AFTER INSERT ON t BEGIN DELETE FROM a; END`)
+		sql.Apply(stmt, func(c *sql.Cursor) bool {
+			if c.Name() == "Body" {
+				c.InsertAfter(parse(`DELETE FROM b`))
+			}
+			return true
+		}, nil)
+		AssertStatementStringer(t, stmt, `CREATE TRIGGER "tr" AFTER INSERT ON "t" BEGIN DELETE FROM "a"; DELETE FROM "b"; END`)
+	})
+
+	t.Run("ReplaceRoot", func(t *testing.T) {
+		stmt := parse(`SELECT 1`)
+		result := sql.Apply(stmt, nil, func(c *sql.Cursor) bool {
+			if c.Parent() != nil && c.Node() == stmt {
+				c.Replace(parse(`SELECT 2`))
+			}
+			return true
+		})
+		AssertStatementStringer(t, result.(sql.Statement), `SELECT 2`)
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		stmt := parse(`SELECT a, b, c FROM t`)
+		var names []string
+		sql.Apply(stmt, nil, func(c *sql.Cursor) bool {
+			if ident, ok := c.Node().(*sql.Ident); ok {
+				names = append(names, ident.Name)
+				return ident.Name != "b"
+			}
+			return true
+		})
+		if len(names) != 2 {
+			t.Fatalf("expected traversal to stop after b, got %v", names)
+		}
+	})
+
+	t.Run("DeleteOutsideSlice", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic")
+			}
+		}()
+		sql.Apply(parse(`DELETE FROM t WHERE a`), func(c *sql.Cursor) bool {
+			if c.Name() == "WhereExpr" {
+				c.Delete()
+			}
+			return true
+		}, nil)
+	})
+}
+
+func Test_Apply_Stack(t *testing.T) {
+	stmt, err := sql.ParseStmtString(`SELECT a FROM t WHERE b = (SELECT c FROM u) GROUP BY d HAVING e`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	sql.Apply(stmt, func(c *sql.Cursor) bool {
+		if c.Depth() > 0 {
+			if stack := c.Stack(); stack[0] != stmt || stack[len(stack)-1] != c.Parent() {
+				t.Fatalf("unexpected stack for %s", c.Node())
+			}
+		}
+		if ident, ok := c.Node().(*sql.Ident); ok {
+			got = append(got, fmt.Sprintf("%s %d %T.%s", ident.Name, c.Depth(), c.Parent(), c.Name()))
+		}
+		return true
+	}, nil)
+
+	want := []string{
+		"a 2 *sql.ResultColumn.Expr",
+		"t 2 *sql.QualifiedName.Name",
+		"b 2 *sql.BinaryExpr.X",
+		"c 5 *sql.ResultColumn.Expr",
+		"u 5 *sql.QualifiedName.Name",
+		"d 1 *sql.SelectStatement.GroupByExprs",
+		"e 1 *sql.SelectStatement.HavingExpr",
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Fatal(diff)
+	}
+}
+
+func Test_Traverse(t *testing.T) {
+	stmt, err := sql.ParseStmtString(`SELECT a FROM t WHERE b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for c, enter := range sql.Traverse(stmt) {
+		if enter {
+			got = append(got, fmt.Sprintf("enter %T", c.Node()))
+		} else {
+			got = append(got, fmt.Sprintf("leave %T", c.Node()))
+		}
+	}
+
+	want := []string{
+		"enter *sql.SelectStatement",
+		"enter *sql.ResultColumn",
+		"enter *sql.Ident",
+		"leave *sql.Ident",
+		"leave *sql.ResultColumn",
+		"enter *sql.QualifiedName",
+		"enter *sql.Ident",
+		"leave *sql.Ident",
+		"leave *sql.QualifiedName",
+		"enter *sql.Ident",
+		"leave *sql.Ident",
+		"leave *sql.SelectStatement",
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Fatal(diff)
+	}
+
+	var n int
+	for c, enter := range sql.Traverse(stmt) {
+		if enter && c.Name() == "WhereExpr" {
+			break
+		}
+		n++
+	}
+	if n != 9 {
+		t.Fatalf("expected loop to stop at WhereExpr, got %d iterations", n)
+	}
+}
@@ -9,9 +9,31 @@ type Node interface {
 	node() bool                          // return true if valid node
 	subnodes(yield func(Node) bool) bool // yields all subnodes of the node
 
+	Pos() Pos // position of first character belonging to the node
+	End() Pos // position of first character immediately after the node
+
+	LeadingComments() *CommentGroup  // comments placed before the node, or nil
+	TrailingComments() *CommentGroup // comments placed after the node, or nil
+
 	fmt.Stringer
 }
 
+// span records the source range of a node. It is embedded in every node and
+// is left zero for nodes that are not produced by the parser.
+type span struct {
+	pos Pos // position of first character
+	end Pos // position immediately after the last character
+}
+
+// Pos returns the position of the first character belonging to the node.
+func (s span) Pos() Pos { return s.pos }
+
+// End returns the position of the first character immediately after the node.
+func (s span) End() Pos { return s.end }
+
+// setSpan sets the source range of the node.
+func (s *span) setSpan(pos, end Pos) { s.pos, s.end = pos, end }
+
 // statement nodes
 func (s *AlterTableStatement) node() bool         { return s != nil }
 func (s *AnalyzeStatement) node() bool            { return s != nil }
@@ -191,6 +213,9 @@ func (*CollateConstraint) constraint()    {}
 func (*ForeignKeyConstraint) constraint() {}
 
 type ExplainStatement struct {
+	span
+	comments
+
 	Explain   bool
 	QueryPlan bool
 	Stmt      Statement // target statement
@@ -208,10 +233,13 @@ func (s *ExplainStatement) String() string {
 		buf.WriteString(" QUERY PLAN")
 	}
 	fmt.Fprintf(&buf, " %s", s.Stmt.String())
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type BeginStatement struct {
+	span
+	comments
+
 	Deferred  bool
 	Immediate bool
 	Exclusive bool
@@ -233,10 +261,15 @@ func (s *BeginStatement) String() string {
 		buf.WriteString(" EXCLUSIVE")
 	}
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
-type CommitStatement struct{}
+type CommitStatement struct {
+	span
+	comments
+
+	EndKeyword bool // true if written as END instead of COMMIT
+}
 
 func (s *CommitStatement) subnodes(yield func(Node) bool) bool {
 	return true
@@ -245,11 +278,18 @@ func (s *CommitStatement) subnodes(yield func(Node) bool) bool {
 // String returns the string representation of the statement.
 func (s *CommitStatement) String() string {
 	var buf strings.Builder
-	buf.WriteString("COMMIT")
-	return buf.String()
+	if s.EndKeyword {
+		buf.WriteString("END")
+	} else {
+		buf.WriteString("COMMIT")
+	}
+	return commented(s, buf.String())
 }
 
 type RollbackStatement struct {
+	span
+	comments
+
 	SavepointName *Ident // name of savepoint
 }
 
@@ -266,10 +306,13 @@ func (s *RollbackStatement) String() string {
 		buf.WriteString(" TO ")
 		buf.WriteString(s.SavepointName.String())
 	}
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type SavepointStatement struct {
+	span
+	comments
+
 	Name *Ident // name of savepoint
 }
 
@@ -279,10 +322,13 @@ func (s *SavepointStatement) subnodes(yield func(Node) bool) bool {
 
 // String returns the string representation of the statement.
 func (s *SavepointStatement) String() string {
-	return fmt.Sprintf("SAVEPOINT %s", s.Name.String())
+	return commented(s, fmt.Sprintf("SAVEPOINT %s", s.Name.String()))
 }
 
 type ReleaseStatement struct {
+	span
+	comments
+
 	Name *Ident // name of savepoint
 }
 
@@ -295,10 +341,13 @@ func (s *ReleaseStatement) String() string {
 	var buf strings.Builder
 	buf.WriteString("RELEASE ")
 	buf.WriteString(s.Name.String())
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type CreateTableStatement struct {
+	span
+	comments
+
 	Temp         bool
 	IfNotExists  bool
 	Name         *QualifiedName      // table name
@@ -364,10 +413,13 @@ func (s *CreateTableStatement) String() string {
 		buf.WriteString(")")
 	}
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type ColumnDefinition struct {
+	span
+	comments
+
 	Name        *Ident       // column name
 	Type        *Type        // data type
 	Constraints []Constraint // column constraints
@@ -403,10 +455,13 @@ func (c *ColumnDefinition) String() string {
 		buf.WriteString(" ")
 		buf.WriteString(c.Constraints[i].String())
 	}
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type PrimaryKeyConstraint struct {
+	span
+	comments
+
 	Name          *Ident // constraint name (optional)
 	Asc           bool
 	Desc          bool
@@ -450,11 +505,6 @@ func (c *PrimaryKeyConstraint) String() string {
 		buf.WriteString(" DESC")
 	}
 
-	if c.Conflict != nil {
-		buf.WriteString(" ")
-		buf.WriteString(c.Conflict.String())
-	}
-
 	if len(c.Columns) > 0 {
 		buf.WriteString(" (")
 		for i := range c.Columns {
@@ -466,13 +516,21 @@ func (c *PrimaryKeyConstraint) String() string {
 		buf.WriteString(")")
 	}
 
+	if c.Conflict != nil {
+		buf.WriteString(" ")
+		buf.WriteString(c.Conflict.String())
+	}
+
 	if c.Autoincrement {
 		buf.WriteString(" AUTOINCREMENT")
 	}
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type NotNullConstraint struct {
+	span
+	comments
+
 	Name     *Ident          // constraint name (optional)
 	Conflict *ConflictClause // conflict clause (optional)
 }
@@ -505,10 +563,13 @@ func (c *NotNullConstraint) String() string {
 		buf.WriteString(c.Conflict.String())
 	}
 
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type UniqueConstraint struct {
+	span
+	comments
+
 	Name     *Ident           // constraint name (optional)
 	Conflict *ConflictClause  // conflict clause (optional)
 	Columns  []*IndexedColumn // indexed columns (table only)
@@ -543,11 +604,6 @@ func (c *UniqueConstraint) String() string {
 
 	buf.WriteString("UNIQUE")
 
-	if c.Conflict != nil {
-		buf.WriteString(" ")
-		buf.WriteString(c.Conflict.String())
-	}
-
 	if len(c.Columns) > 0 {
 		buf.WriteString(" (")
 		for i := range c.Columns {
@@ -559,10 +615,18 @@ func (c *UniqueConstraint) String() string {
 		buf.WriteString(")")
 	}
 
-	return buf.String()
+	if c.Conflict != nil {
+		buf.WriteString(" ")
+		buf.WriteString(c.Conflict.String())
+	}
+
+	return commented(c, buf.String())
 }
 
 type CheckConstraint struct {
+	span
+	comments
+
 	Name *Ident // constraint name
 	Expr Expr   // check expression
 }
@@ -583,10 +647,13 @@ func (c *CheckConstraint) String() string {
 	buf.WriteString("CHECK (")
 	buf.WriteString(c.Expr.String())
 	buf.WriteString(")")
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type DefaultConstraint struct {
+	span
+	comments
+
 	Name *Ident // constraint name
 	Expr Expr   // default expression
 }
@@ -605,11 +672,34 @@ func (c *DefaultConstraint) String() string {
 	}
 
 	buf.WriteString("DEFAULT ")
-	buf.WriteString(c.Expr.String())
-	return buf.String()
+	if isDefaultLiteral(c.Expr) {
+		buf.WriteString(c.Expr.String())
+	} else {
+		buf.WriteString("(")
+		buf.WriteString(c.Expr.String())
+		buf.WriteString(")")
+	}
+	return commented(c, buf.String())
+}
+
+// isDefaultLiteral returns true if expr can be written after DEFAULT without
+// parentheses.
+func isDefaultLiteral(expr Expr) bool {
+	switch expr := expr.(type) {
+	case *StringLit, *NumberLit, *BlobLit, *NullLit, *BoolLit, *TimestampLit:
+		return true
+	case *UnaryExpr:
+		_, ok := expr.X.(*NumberLit)
+		return ok && (expr.Op == OP_PLUS || expr.Op == OP_MINUS)
+	default:
+		return false
+	}
 }
 
 type GeneratedConstraint struct {
+	span
+	comments
+
 	Name    *Ident // constraint name
 	Expr    Expr   // default expression
 	Stored  bool
@@ -639,10 +729,13 @@ func (c *GeneratedConstraint) String() string {
 		buf.WriteString(" VIRTUAL")
 	}
 
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type CollateConstraint struct {
+	span
+	comments
+
 	Name      *Ident // constraint name
 	Collation *Ident // collation name
 }
@@ -662,10 +755,13 @@ func (c *CollateConstraint) String() string {
 
 	buf.WriteString("COLLATE ")
 	buf.WriteString(c.Collation.String())
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type ForeignKeyConstraint struct {
+	span
+	comments
+
 	Name               *Ident           // constraint name
 	Columns            []*Ident         // indexed columns (table only)
 	ForeignTable       *Ident           // foreign table name
@@ -757,10 +853,13 @@ func (c *ForeignKeyConstraint) String() string {
 		buf.WriteString(" INITIALLY IMMEDIATE")
 	}
 
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type ForeignKeyArg struct {
+	span
+	comments
+
 	OnUpdate   bool
 	OnDelete   bool
 	SetNull    bool
@@ -795,10 +894,13 @@ func (c *ForeignKeyArg) String() string {
 	} else if c.NoAction {
 		buf.WriteString(" NO ACTION")
 	}
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type CreateVirtualTableStatement struct {
+	span
+	comments
+
 	IfNotExists bool
 	Name        *QualifiedName    // table name
 	ModuleName  *Ident            // name of an object that implements the virtual table
@@ -831,18 +933,23 @@ func (s *CreateVirtualTableStatement) String() string {
 
 	buf.WriteString(" USING ")
 	buf.WriteString(s.ModuleName.String())
-	buf.WriteString(" (")
-	for i := range s.Arguments {
-		if i != 0 {
-			buf.WriteString(",")
+	if len(s.Arguments) > 0 {
+		buf.WriteString(" (")
+		for i := range s.Arguments {
+			if i != 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(s.Arguments[i].String())
 		}
-		buf.WriteString(s.Arguments[i].String())
+		buf.WriteString(")")
 	}
-	buf.WriteString(")")
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type ModuleArgument struct {
+	span
+	comments
+
 	Name    *Ident // argument name
 	Literal Expr   // literal that is assigned to name (optional)
 	Type    *Type  // type of Name, if Assign is set then Type cant be (optional)
@@ -868,10 +975,13 @@ func (a *ModuleArgument) String() string {
 		buf.WriteString(a.Type.String())
 	}
 
-	return buf.String()
+	return commented(a, buf.String())
 }
 
 type AnalyzeStatement struct {
+	span
+	comments
+
 	Name *QualifiedName // table or index name (or schema.table, schema.index) (optional)
 }
 
@@ -882,13 +992,16 @@ func (s *AnalyzeStatement) subnodes(yield func(Node) bool) bool {
 // String returns the string representation of the statement.
 func (s *AnalyzeStatement) String() string {
 	if s.Name == nil {
-		return "ANALYZE"
+		return commented(s, "ANALYZE")
 	}
 
-	return fmt.Sprintf("ANALYZE %s", s.Name.String())
+	return commented(s, fmt.Sprintf("ANALYZE %s", s.Name.String()))
 }
 
 type ReindexStatement struct {
+	span
+	comments
+
 	Name *QualifiedName // collation, index or table name (or schema.table, schema.index)
 }
 
@@ -899,17 +1012,21 @@ func (s *ReindexStatement) subnodes(yield func(Node) bool) bool {
 // String returns the string representation of the statement.
 func (s *ReindexStatement) String() string {
 	if s.Name == nil {
-		return "REINDEX"
+		return commented(s, "REINDEX")
 	}
-	return fmt.Sprintf("REINDEX %s", s.Name.String())
+	return commented(s, fmt.Sprintf("REINDEX %s", s.Name.String()))
 }
 
 type AlterTableStatement struct {
-	Name          *QualifiedName    // table name
-	NewName       *Ident            // new table name
-	ColumnName    *Ident            // new column name
-	NewColumnName *Ident            // new column name
-	ColumnDef     *ColumnDefinition // new column definition
+	span
+	comments
+
+	Name           *QualifiedName    // table name
+	NewName        *Ident            // new table name
+	ColumnName     *Ident            // new column name
+	NewColumnName  *Ident            // new column name
+	ColumnDef      *ColumnDefinition // new column definition
+	DropColumnName *Ident            // dropped column name
 }
 
 func (s *AlterTableStatement) subnodes(yield func(Node) bool) bool {
@@ -917,7 +1034,7 @@ func (s *AlterTableStatement) subnodes(yield func(Node) bool) bool {
 		return false
 	}
 
-	return yieldNodes(yield, s.ColumnDef)
+	return yieldNodes(yield, s.ColumnDef, s.DropColumnName)
 }
 
 // String returns the string representation of the statement.
@@ -938,13 +1055,20 @@ func (s *AlterTableStatement) String() string {
 	} else if s.ColumnDef != nil {
 		buf.WriteString(" ADD COLUMN ")
 		buf.WriteString(s.ColumnDef.String())
+	} else if s.DropColumnName != nil {
+		buf.WriteString(" DROP COLUMN ")
+		buf.WriteString(s.DropColumnName.String())
 	}
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type Ident struct {
-	Quoted bool   // true if double quoted
+	span
+	comments
+
+	Quoted bool   // true if quoted
+	Quote  string // quote character if not a double quote: "`", "[" or "'"
 	Name   string // identifier name
 }
 
@@ -954,10 +1078,21 @@ func (i *Ident) subnodes(yield func(Node) bool) bool {
 
 // String returns the string representation of the expression.
 func (i *Ident) String() string {
-	return `"` + strings.Replace(i.Name, `"`, `""`, -1) + `"`
+	switch {
+	case i.Quote == "`":
+		return commented(i, "`"+strings.Replace(i.Name, "`", "``", -1)+"`")
+	case i.Quote == "[" && !strings.Contains(i.Name, "]"):
+		return commented(i, "["+i.Name+"]")
+	case i.Quote == "'":
+		return commented(i, "'"+strings.Replace(i.Name, "'", "''", -1)+"'")
+	}
+	return commented(i, `"`+strings.Replace(i.Name, `"`, `""`, -1)+`"`)
 }
 
 type Type struct {
+	span
+	comments
+
 	Name      *Ident     // type name
 	Precision *NumberLit // precision (optional)
 	Scale     *NumberLit // scale (optional)
@@ -970,14 +1105,17 @@ func (t *Type) subnodes(yield func(Node) bool) bool {
 // String returns the string representation of the type.
 func (t *Type) String() string {
 	if t.Precision != nil && t.Scale != nil {
-		return fmt.Sprintf("%s(%s,%s)", t.Name.Name, t.Precision.String(), t.Scale.String())
+		return commented(t, fmt.Sprintf("%s(%s,%s)", t.Name.Name, t.Precision.String(), t.Scale.String()))
 	} else if t.Precision != nil {
-		return fmt.Sprintf("%s(%s)", t.Name.Name, t.Precision.String())
+		return commented(t, fmt.Sprintf("%s(%s)", t.Name.Name, t.Precision.String()))
 	}
-	return t.Name.Name
+	return commented(t, t.Name.Name)
 }
 
 type StringLit struct {
+	span
+	comments
+
 	Value string // literal value (without quotes)
 }
 
@@ -987,10 +1125,13 @@ func (lit *StringLit) subnodes(yield func(Node) bool) bool {
 
 // String returns the string representation of the expression.
 func (lit *StringLit) String() string {
-	return `'` + strings.Replace(lit.Value, `'`, `''`, -1) + `'`
+	return commented(lit, `'`+strings.Replace(lit.Value, `'`, `''`, -1)+`'`)
 }
 
 type TimestampLit struct {
+	span
+	comments
+
 	Value string // literal value
 }
 
@@ -1000,10 +1141,13 @@ func (lit *TimestampLit) subnodes(yield func(Node) bool) bool {
 
 // String returns the string representation of the expression.
 func (lit *TimestampLit) String() string {
-	return lit.Value
+	return commented(lit, lit.Value)
 }
 
 type BlobLit struct {
+	span
+	comments
+
 	Value string // literal value
 }
 
@@ -1013,10 +1157,13 @@ func (lit *BlobLit) subnodes(yield func(Node) bool) bool {
 
 // String returns the string representation of the expression.
 func (lit *BlobLit) String() string {
-	return `x'` + lit.Value + `'`
+	return commented(lit, `x'`+lit.Value+`'`)
 }
 
 type NumberLit struct {
+	span
+	comments
+
 	Value string // literal value
 }
 
@@ -1026,10 +1173,13 @@ func (lit *NumberLit) subnodes(yield func(Node) bool) bool {
 
 // String returns the string representation of the expression.
 func (lit *NumberLit) String() string {
-	return lit.Value
+	return commented(lit, lit.Value)
 }
 
-type NullLit struct{}
+type NullLit struct {
+	span
+	comments
+}
 
 func (lit *NullLit) subnodes(yield func(Node) bool) bool {
 	return true
@@ -1037,10 +1187,13 @@ func (lit *NullLit) subnodes(yield func(Node) bool) bool {
 
 // String returns the string representation of the expression.
 func (lit *NullLit) String() string {
-	return "NULL"
+	return commented(lit, "NULL")
 }
 
 type BoolLit struct {
+	span
+	comments
+
 	Value bool // literal value
 }
 
@@ -1051,13 +1204,16 @@ func (lit *BoolLit) subnodes(yield func(Node) bool) bool {
 // String returns the string representation of the expression.
 func (lit *BoolLit) String() string {
 	if lit.Value {
-		return "TRUE"
+		return commented(lit, "TRUE")
 	}
-	return "FALSE"
+	return commented(lit, "FALSE")
 }
 
 type BindExpr struct {
-	Name string // binding name
+	span
+	comments
+
+	Name string // binding name, including the bind character: "?", "?1", ":foo", "@foo" or "$foo"
 }
 
 func (expr *BindExpr) subnodes(yield func(Node) bool) bool {
@@ -1066,11 +1222,28 @@ func (expr *BindExpr) subnodes(yield func(Node) bool) bool {
 
 // String returns the string representation of the expression.
 func (expr *BindExpr) String() string {
-	// TODO(BBJ): Support all bind characters.
-	return expr.Name
+	return commented(expr, expr.name())
+}
+
+// name returns the name of the parameter with its bind character. An empty
+// name is an anonymous parameter, a number is a numbered parameter and any
+// other name without a bind character is a named parameter.
+func (expr *BindExpr) name() string {
+	switch {
+	case expr.Name == "":
+		return "?"
+	case strings.IndexByte("?:@$", expr.Name[0]) >= 0:
+		return expr.Name
+	case strings.Trim(expr.Name, "0123456789") == "":
+		return "?" + expr.Name
+	}
+	return ":" + expr.Name
 }
 
 type UnaryExpr struct {
+	span
+	comments
+
 	Op OpType // PLUS / MINUS / NOT / BITNOT
 	X  Expr   // target expression
 }
@@ -1083,22 +1256,29 @@ func (expr *UnaryExpr) subnodes(yield func(Node) bool) bool {
 func (expr *UnaryExpr) String() string {
 	switch expr.Op {
 	case OP_PLUS:
-		return "+" + expr.X.String()
+		return commented(expr, "+"+expr.X.String())
 	case OP_MINUS:
-		return "-" + expr.X.String()
+		if x := expr.X.String(); strings.HasPrefix(x, "-") {
+			return commented(expr, "- "+x)
+		}
+		return commented(expr, "-"+expr.X.String())
 	case OP_NOT:
-		return "NOT " + expr.X.String()
+		return commented(expr, "NOT "+expr.X.String())
 	case OP_BITNOT:
-		return "~" + expr.X.String()
+		return commented(expr, "~"+expr.X.String())
 	default:
 		panic("invalid op")
 	}
 }
 
 type BinaryExpr struct {
-	X  Expr   // lhs
-	Op OpType // operator
-	Y  Expr   // rhs
+	span
+	comments
+
+	X     Expr   // lhs
+	Op    OpType // operator
+	AltOp bool   // true if Op is spelled "==" instead of "=", or "<>" instead of "!="
+	Y     Expr   // rhs
 }
 
 func (expr *BinaryExpr) subnodes(yield func(Node) bool) bool {
@@ -1109,83 +1289,92 @@ func (expr *BinaryExpr) subnodes(yield func(Node) bool) bool {
 func (expr *BinaryExpr) String() string {
 	switch expr.Op {
 	case OP_PLUS:
-		return expr.X.String() + " + " + expr.Y.String()
+		return commented(expr, expr.X.String()+" + "+expr.Y.String())
 	case OP_MINUS:
-		return expr.X.String() + " - " + expr.Y.String()
+		return commented(expr, expr.X.String()+" - "+expr.Y.String())
 	case OP_MULTIPLY:
-		return expr.X.String() + " * " + expr.Y.String()
+		return commented(expr, expr.X.String()+" * "+expr.Y.String())
 	case OP_DIVIDE:
-		return expr.X.String() + " / " + expr.Y.String()
+		return commented(expr, expr.X.String()+" / "+expr.Y.String())
 	case OP_MODULO:
-		return expr.X.String() + " % " + expr.Y.String()
+		return commented(expr, expr.X.String()+" % "+expr.Y.String())
 	case OP_CONCAT:
-		return expr.X.String() + " || " + expr.Y.String()
+		return commented(expr, expr.X.String()+" || "+expr.Y.String())
 	case OP_BETWEEN:
-		return expr.X.String() + " BETWEEN " + expr.Y.String()
+		return commented(expr, expr.X.String()+" BETWEEN "+expr.Y.String())
 	case OP_NOT_BETWEEN:
-		return expr.X.String() + " NOT BETWEEN " + expr.Y.String()
+		return commented(expr, expr.X.String()+" NOT BETWEEN "+expr.Y.String())
 	case OP_LSHIFT:
-		return expr.X.String() + " << " + expr.Y.String()
+		return commented(expr, expr.X.String()+" << "+expr.Y.String())
 	case OP_RSHIFT:
-		return expr.X.String() + " >> " + expr.Y.String()
+		return commented(expr, expr.X.String()+" >> "+expr.Y.String())
 	case OP_BITAND:
-		return expr.X.String() + " & " + expr.Y.String()
+		return commented(expr, expr.X.String()+" & "+expr.Y.String())
 	case OP_BITOR:
-		return expr.X.String() + " | " + expr.Y.String()
+		return commented(expr, expr.X.String()+" | "+expr.Y.String())
 	case OP_LT:
-		return expr.X.String() + " < " + expr.Y.String()
+		return commented(expr, expr.X.String()+" < "+expr.Y.String())
 	case OP_LE:
-		return expr.X.String() + " <= " + expr.Y.String()
+		return commented(expr, expr.X.String()+" <= "+expr.Y.String())
 	case OP_GT:
-		return expr.X.String() + " > " + expr.Y.String()
+		return commented(expr, expr.X.String()+" > "+expr.Y.String())
 	case OP_GE:
-		return expr.X.String() + " >= " + expr.Y.String()
+		return commented(expr, expr.X.String()+" >= "+expr.Y.String())
 	case OP_EQ:
-		return expr.X.String() + " = " + expr.Y.String()
+		if expr.AltOp {
+			return commented(expr, expr.X.String()+" == "+expr.Y.String())
+		}
+		return commented(expr, expr.X.String()+" = "+expr.Y.String())
 	case OP_NE:
-		return expr.X.String() + " != " + expr.Y.String()
+		if expr.AltOp {
+			return commented(expr, expr.X.String()+" <> "+expr.Y.String())
+		}
+		return commented(expr, expr.X.String()+" != "+expr.Y.String())
 	case OP_JSON_EXTRACT_JSON:
-		return expr.X.String() + " -> " + expr.Y.String()
+		return commented(expr, expr.X.String()+" -> "+expr.Y.String())
 	case OP_JSON_EXTRACT_SQL:
-		return expr.X.String() + " ->> " + expr.Y.String()
+		return commented(expr, expr.X.String()+" ->> "+expr.Y.String())
 	case OP_IS:
-		return expr.X.String() + " IS " + expr.Y.String()
+		return commented(expr, expr.X.String()+" IS "+expr.Y.String())
 	case OP_IS_NOT:
-		return expr.X.String() + " IS NOT " + expr.Y.String()
+		return commented(expr, expr.X.String()+" IS NOT "+expr.Y.String())
 	case OP_LIKE:
-		return expr.X.String() + " LIKE " + expr.Y.String()
+		return commented(expr, expr.X.String()+" LIKE "+expr.Y.String())
 	case OP_NOT_LIKE:
-		return expr.X.String() + " NOT LIKE " + expr.Y.String()
+		return commented(expr, expr.X.String()+" NOT LIKE "+expr.Y.String())
 	case OP_GLOB:
-		return expr.X.String() + " GLOB " + expr.Y.String()
+		return commented(expr, expr.X.String()+" GLOB "+expr.Y.String())
 	case OP_NOT_GLOB:
-		return expr.X.String() + " NOT GLOB " + expr.Y.String()
+		return commented(expr, expr.X.String()+" NOT GLOB "+expr.Y.String())
 	case OP_MATCH:
-		return expr.X.String() + " MATCH " + expr.Y.String()
+		return commented(expr, expr.X.String()+" MATCH "+expr.Y.String())
 	case OP_NOT_MATCH:
-		return expr.X.String() + " NOT MATCH " + expr.Y.String()
+		return commented(expr, expr.X.String()+" NOT MATCH "+expr.Y.String())
 	case OP_REGEXP:
-		return expr.X.String() + " REGEXP " + expr.Y.String()
+		return commented(expr, expr.X.String()+" REGEXP "+expr.Y.String())
 	case OP_NOT_REGEXP:
-		return expr.X.String() + " NOT REGEXP " + expr.Y.String()
+		return commented(expr, expr.X.String()+" NOT REGEXP "+expr.Y.String())
 	case OP_AND:
-		return expr.X.String() + " AND " + expr.Y.String()
+		return commented(expr, expr.X.String()+" AND "+expr.Y.String())
 	case OP_OR:
-		return expr.X.String() + " OR " + expr.Y.String()
+		return commented(expr, expr.X.String()+" OR "+expr.Y.String())
 	case OP_IS_DISTINCT_FROM:
-		return expr.X.String() + " IS DISTINCT FROM " + expr.Y.String()
+		return commented(expr, expr.X.String()+" IS DISTINCT FROM "+expr.Y.String())
 	case OP_IS_NOT_DISTINCT_FROM:
-		return expr.X.String() + " IS NOT DISTINCT FROM " + expr.Y.String()
+		return commented(expr, expr.X.String()+" IS NOT DISTINCT FROM "+expr.Y.String())
 	case OP_ESCAPE:
-		return expr.X.String() + " ESCAPE " + expr.Y.String()
+		return commented(expr, expr.X.String()+" ESCAPE "+expr.Y.String())
 	case OP_COLLATE:
-		return expr.X.String() + " COLLATE " + expr.Y.String()
+		return commented(expr, expr.X.String()+" COLLATE "+expr.Y.String())
 	default:
 		panic("invalid op")
 	}
 }
 
 type CastExpr struct {
+	span
+	comments
+
 	X    Expr  // target expression
 	Type *Type // cast type
 }
@@ -1200,10 +1389,13 @@ func (expr *CastExpr) subnodes(yield func(Node) bool) bool {
 
 // String returns the string representation of the expression.
 func (expr *CastExpr) String() string {
-	return fmt.Sprintf("CAST(%s AS %s)", expr.X.String(), expr.Type.String())
+	return commented(expr, fmt.Sprintf("CAST(%s AS %s)", expr.X.String(), expr.Type.String()))
 }
 
 type CaseExpr struct {
+	span
+	comments
+
 	Operand  Expr         // optional condition after the CASE keyword
 	Blocks   []*CaseBlock // list of WHEN/THEN pairs
 	ElseExpr Expr         // expression used by default case
@@ -1239,10 +1431,13 @@ func (expr *CaseExpr) String() string {
 		buf.WriteString(expr.ElseExpr.String())
 	}
 	buf.WriteString(" END")
-	return buf.String()
+	return commented(expr, buf.String())
 }
 
 type CaseBlock struct {
+	span
+	comments
+
 	Condition Expr // block condition
 	Body      Expr // result expression
 }
@@ -1253,10 +1448,13 @@ func (b *CaseBlock) subnodes(yield func(Node) bool) bool {
 
 // String returns the string representation of the block.
 func (b *CaseBlock) String() string {
-	return fmt.Sprintf("WHEN %s THEN %s", b.Condition.String(), b.Body.String())
+	return commented(b, fmt.Sprintf("WHEN %s THEN %s", b.Condition.String(), b.Body.String()))
 }
 
 type Raise struct {
+	span
+	comments
+
 	Ignore   bool
 	Rollback bool
 	Abort    bool
@@ -1282,10 +1480,13 @@ func (r *Raise) String() string {
 		buf.WriteString("IGNORE")
 	}
 	buf.WriteString(")")
-	return buf.String()
+	return commented(r, buf.String())
 }
 
 type Exists struct {
+	span
+	comments
+
 	Not    bool
 	Select *SelectStatement // select statement
 }
@@ -1297,12 +1498,15 @@ func (expr *Exists) subnodes(yield func(Node) bool) bool {
 // String returns the string representation of the expression.
 func (expr *Exists) String() string {
 	if expr.Not {
-		return fmt.Sprintf("NOT EXISTS (%s)", expr.Select.String())
+		return commented(expr, fmt.Sprintf("NOT EXISTS (%s)", expr.Select.String()))
 	}
-	return fmt.Sprintf("EXISTS (%s)", expr.Select.String())
+	return commented(expr, fmt.Sprintf("EXISTS (%s)", expr.Select.String()))
 }
 
 type Null struct {
+	span
+	comments
+
 	X  Expr   // expression being checked for null
 	Op OpType // NOTNULl / ISNULL
 }
@@ -1325,10 +1529,13 @@ func (expr *Null) String() string {
 		panic("invalid op")
 	}
 
-	return buf.String()
+	return commented(expr, buf.String())
 }
 
 type ExprList struct {
+	span
+	comments
+
 	Exprs []Expr // list of expressions
 }
 
@@ -1352,10 +1559,13 @@ func (l *ExprList) String() string {
 		buf.WriteString(expr.String())
 	}
 	buf.WriteString(")")
-	return buf.String()
+	return commented(l, buf.String())
 }
 
 type QualifiedRef struct {
+	span
+	comments
+
 	Table  *QualifiedName // table name
 	Star   bool
 	Column *Ident // column name (optional, if star)
@@ -1368,12 +1578,15 @@ func (r *QualifiedRef) subnodes(yield func(Node) bool) bool {
 // String returns the string representation of the expression.
 func (r *QualifiedRef) String() string {
 	if r.Star {
-		return fmt.Sprintf("%s.*", r.Table.String())
+		return commented(r, fmt.Sprintf("%s.*", r.Table.String()))
 	}
-	return fmt.Sprintf("%s.%s", r.Table.String(), r.Column.String())
+	return commented(r, fmt.Sprintf("%s.%s", r.Table.String(), r.Column.String()))
 }
 
 type Call struct {
+	span
+	comments
+
 	Name       *QualifiedName    // function name
 	Filter     Expr              // filter clause (optional)
 	OverName   *Ident            // over name (optional)
@@ -1407,10 +1620,13 @@ func (c *Call) String() string {
 		buf.WriteString(c.OverWindow.String())
 	}
 
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type OrderingTerm struct {
+	span
+	comments
+
 	X Expr // ordering expression
 
 	Asc        bool
@@ -1440,10 +1656,13 @@ func (t *OrderingTerm) String() string {
 		buf.WriteString(" NULLS LAST")
 	}
 
-	return buf.String()
+	return commented(t, buf.String())
 }
 
 type FrameSpec struct {
+	span
+	comments
+
 	Range   bool
 	Rows    bool
 	Groups  bool
@@ -1525,10 +1744,13 @@ func (s *FrameSpec) String() string {
 		buf.WriteString(" EXCLUDE TIES")
 	}
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type DropTableStatement struct {
+	span
+	comments
+
 	IfExists bool
 	Name     *QualifiedName // table name
 }
@@ -1546,10 +1768,13 @@ func (s *DropTableStatement) String() string {
 	}
 
 	buf.WriteString(s.Name.String())
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type CreateViewStatement struct {
+	span
+	comments
+
 	Temp        bool
 	IfNotExists bool
 	Name        *QualifiedName   // view name
@@ -1601,10 +1826,13 @@ func (s *CreateViewStatement) String() string {
 
 	fmt.Fprintf(&buf, " AS %s", s.Select.String())
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type DropViewStatement struct {
+	span
+	comments
+
 	IfExists bool
 	Name     *QualifiedName // view name
 }
@@ -1622,10 +1850,13 @@ func (s *DropViewStatement) String() string {
 	}
 
 	buf.WriteString(s.Name.String())
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type CreateIndexStatement struct {
+	span
+	comments
+
 	Unique      bool
 	IfNotExists bool
 	Name        *QualifiedName   // index name
@@ -1675,10 +1906,13 @@ func (s *CreateIndexStatement) String() string {
 		fmt.Fprintf(&buf, " WHERE %s", s.WhereExpr.String())
 	}
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type DropIndexStatement struct {
+	span
+	comments
+
 	IfExists bool
 	Name     *QualifiedName // index name
 }
@@ -1696,10 +1930,13 @@ func (s *DropIndexStatement) String() string {
 	}
 
 	buf.WriteString(s.Name.String())
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type CreateTriggerStatement struct {
+	span
+	comments
+
 	Temp        bool
 	IfNotExists bool
 	Name        *QualifiedName // trigger name
@@ -1802,10 +2039,13 @@ func (s *CreateTriggerStatement) String() string {
 	}
 	buf.WriteString(" END")
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type DropTriggerStatement struct {
+	span
+	comments
+
 	IfExists bool
 	Name     *QualifiedName // trigger name
 }
@@ -1823,10 +2063,13 @@ func (s *DropTriggerStatement) String() string {
 	}
 
 	buf.WriteString(s.Name.String())
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type InsertStatement struct {
+	span
+	comments
+
 	WithClause *WithClause // clause containing CTEs
 
 	Replace          bool
@@ -1957,10 +2200,13 @@ func (s *InsertStatement) String() string {
 		}
 	}
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type UpsertClause struct {
+	span
+	comments
+
 	Columns         []*IndexedColumn // optional indexed column list
 	WhereExpr       Expr             // optional conditional expression
 	DoNothing       bool
@@ -2026,10 +2272,13 @@ func (c *UpsertClause) String() string {
 		}
 	}
 
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type UpdateStatement struct {
+	span
+	comments
+
 	WithClause       *WithClause // clause containing CTEs
 	UpdateOrReplace  bool
 	UpdateOrRollback bool
@@ -2038,11 +2287,13 @@ type UpdateStatement struct {
 	UpdateOrIgnore   bool
 	Table            *QualifiedName  // table name
 	Assignments      []*Assignment   // list of column assignments
+	Source           Source          // chain of tables & subqueries in FROM clause
 	WhereExpr        Expr            // conditional expression
 	ReturningColumns []*ResultColumn // list of result columns
 	OrderingTerms    []*OrderingTerm // terms of ORDER BY clause
 	LimitExpr        Expr            // limit expression
 	OffsetExpr       Expr            // offset expression
+	LimitComma       bool            // true if written as "LIMIT offset, limit"
 }
 
 func (s *UpdateStatement) subnodes(yield func(Node) bool) bool {
@@ -2059,7 +2310,7 @@ func (s *UpdateStatement) subnodes(yield func(Node) bool) bool {
 			return false
 		}
 	}
-	if !yieldNodes(yield, s.WhereExpr) {
+	if !yieldNodes(yield, s.Source, s.WhereExpr) {
 		return false
 	}
 
@@ -2108,6 +2359,10 @@ func (s *UpdateStatement) String() string {
 		buf.WriteString(s.Assignments[i].String())
 	}
 
+	if s.Source != nil {
+		fmt.Fprintf(&buf, " FROM %s", s.Source.String())
+	}
+
 	if s.WhereExpr != nil {
 		fmt.Fprintf(&buf, " WHERE %s", s.WhereExpr.String())
 	}
@@ -2135,16 +2390,23 @@ func (s *UpdateStatement) String() string {
 
 	// Write LIMIT/OFFSET.
 	if s.LimitExpr != nil {
-		fmt.Fprintf(&buf, " LIMIT %s", s.LimitExpr.String())
-		if s.OffsetExpr != nil {
-			fmt.Fprintf(&buf, " OFFSET %s", s.OffsetExpr.String())
+		if s.LimitComma && s.OffsetExpr != nil {
+			fmt.Fprintf(&buf, " LIMIT %s, %s", s.OffsetExpr.String(), s.LimitExpr.String())
+		} else {
+			fmt.Fprintf(&buf, " LIMIT %s", s.LimitExpr.String())
+			if s.OffsetExpr != nil {
+				fmt.Fprintf(&buf, " OFFSET %s", s.OffsetExpr.String())
+			}
 		}
 	}
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type DeleteStatement struct {
+	span
+	comments
+
 	WithClause       *WithClause     // clause containing CTEs
 	Table            *QualifiedName  // table name
 	WhereExpr        Expr            // conditional expression
@@ -2152,6 +2414,7 @@ type DeleteStatement struct {
 	OrderingTerms    []*OrderingTerm // terms of ORDER BY clause
 	LimitExpr        Expr            // limit expression
 	OffsetExpr       Expr            // offset expression
+	LimitComma       bool            // true if written as "LIMIT offset, limit"
 }
 
 func (s *DeleteStatement) subnodes(yield func(Node) bool) bool {
@@ -2213,18 +2476,25 @@ func (s *DeleteStatement) String() string {
 
 	// Write LIMIT/OFFSET.
 	if s.LimitExpr != nil {
-		fmt.Fprintf(&buf, " LIMIT %s", s.LimitExpr.String())
-		if s.OffsetExpr != nil {
-			fmt.Fprintf(&buf, " OFFSET %s", s.OffsetExpr.String())
+		if s.LimitComma && s.OffsetExpr != nil {
+			fmt.Fprintf(&buf, " LIMIT %s, %s", s.OffsetExpr.String(), s.LimitExpr.String())
+		} else {
+			fmt.Fprintf(&buf, " LIMIT %s", s.LimitExpr.String())
+			if s.OffsetExpr != nil {
+				fmt.Fprintf(&buf, " OFFSET %s", s.OffsetExpr.String())
+			}
 		}
 	}
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 // Assignment is used within the UPDATE statement & upsert clause.
 // It is similiar to an expression except that it must be an equality.
 type Assignment struct {
+	span
+	comments
+
 	Columns []*Ident // column list
 	Expr    Expr     // assigned expression
 }
@@ -2255,10 +2525,13 @@ func (a *Assignment) String() string {
 	}
 
 	fmt.Fprintf(&buf, " = %s", a.Expr.String())
-	return buf.String()
+	return commented(a, buf.String())
 }
 
 type IndexedColumn struct {
+	span
+	comments
+
 	X    Expr // column expression
 	Asc  bool
 	Desc bool
@@ -2279,10 +2552,13 @@ func (c *IndexedColumn) String() string {
 		buf.WriteString(" DESC")
 	}
 
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type SelectStatement struct {
+	span
+	comments
+
 	WithClause    *WithClause // clause containing CTEs
 	ValueLists    []*ExprList // lists of lists of values
 	Distinct      bool
@@ -2300,6 +2576,7 @@ type SelectStatement struct {
 	OrderingTerms []*OrderingTerm  // terms of ORDER BY clause
 	LimitExpr     Expr             // limit expression
 	OffsetExpr    Expr             // offset expression
+	LimitComma    bool             // true if written as "LIMIT offset, limit"
 }
 
 func (s *SelectStatement) subnodes(yield func(Node) bool) bool {
@@ -2453,16 +2730,23 @@ func (s *SelectStatement) String() string {
 
 	// Write LIMIT/OFFSET.
 	if s.LimitExpr != nil {
-		fmt.Fprintf(&buf, " LIMIT %s", s.LimitExpr.String())
-		if s.OffsetExpr != nil {
-			fmt.Fprintf(&buf, " OFFSET %s", s.OffsetExpr.String())
+		if s.LimitComma && s.OffsetExpr != nil {
+			fmt.Fprintf(&buf, " LIMIT %s, %s", s.OffsetExpr.String(), s.LimitExpr.String())
+		} else {
+			fmt.Fprintf(&buf, " LIMIT %s", s.LimitExpr.String())
+			if s.OffsetExpr != nil {
+				fmt.Fprintf(&buf, " OFFSET %s", s.OffsetExpr.String())
+			}
 		}
 	}
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type ResultColumn struct {
+	span
+	comments
+
 	Star  bool
 	Expr  Expr   // column expression (may be "tbl.*")
 	Alias *Ident // alias name
@@ -2475,14 +2759,17 @@ func (c *ResultColumn) subnodes(yield func(Node) bool) bool {
 // String returns the string representation of the column.
 func (c *ResultColumn) String() string {
 	if c.Star {
-		return "*"
+		return commented(c, "*")
 	} else if c.Alias != nil {
-		return fmt.Sprintf("%s AS %s", c.Expr.String(), c.Alias.String())
+		return commented(c, fmt.Sprintf("%s AS %s", c.Expr.String(), c.Alias.String()))
 	}
-	return c.Expr.String()
+	return commented(c, c.Expr.String())
 }
 
 type QualifiedName struct {
+	span
+	comments
+
 	Schema           *Ident         // schema name (optional)
 	Name             *Ident         // name
 	FunctionCall     bool           // true if this is a function call
@@ -2550,10 +2837,13 @@ func (n *QualifiedName) String() string {
 	} else if n.NotIndexed {
 		buf.WriteString(" NOT INDEXED")
 	}
-	return buf.String()
+	return commented(n, buf.String())
 }
 
 type ParenSource struct {
+	span
+	comments
+
 	X     Source // nested source
 	Alias *Ident // optional table alias (select source only)
 }
@@ -2565,12 +2855,15 @@ func (s *ParenSource) subnodes(yield func(Node) bool) bool {
 // String returns the string representation of the source.
 func (s *ParenSource) String() string {
 	if s.Alias != nil {
-		return fmt.Sprintf("(%s) AS %s", s.X.String(), s.Alias.String())
+		return commented(s, fmt.Sprintf("(%s) AS %s", s.X.String(), s.Alias.String()))
 	}
-	return fmt.Sprintf("(%s)", s.X.String())
+	return commented(s, fmt.Sprintf("(%s)", s.X.String()))
 }
 
 type JoinClause struct {
+	span
+	comments
+
 	X          Source         // lhs source
 	Operator   *JoinOperator  // join operator
 	Y          Source         // rhs source
@@ -2592,86 +2885,36 @@ func (c *JoinClause) subnodes(yield func(Node) bool) bool {
 // String returns the string representation of the clause.
 func (c *JoinClause) String() string {
 	var buf strings.Builder
-
-	// Print the left side
-	buf.WriteString(c.X.String())
-
-	// Print the operator
+	writeJoinSource(&buf, c.X, nil)
 	buf.WriteString(c.Operator.String())
+	writeJoinSource(&buf, c.Y, c.Constraint)
+	return commented(c, buf.String())
+}
 
-	// Handle the right side
-	if y, ok := c.Y.(*JoinClause); ok {
-		// Special case: right side is a JoinClause
-
-		// Check if the X of the nested JoinClause is also a JoinClause
-		if yx, ok := y.X.(*JoinClause); ok {
-			// Handle the double-nested case
-
-			// Print the first table of the inner JoinClause
-			buf.WriteString(yx.X.String())
-
-			// Add the constraint for the first join
-			if c.Constraint != nil {
-				fmt.Fprintf(&buf, " %s", c.Constraint.String())
-			}
-
-			// Print the operator of the inner JoinClause
-			buf.WriteString(yx.Operator.String())
-
-			// Print the second table of the inner JoinClause
-			buf.WriteString(yx.Y.String())
-
-			// Add the constraint for the inner JoinClause
-			if yx.Constraint != nil {
-				fmt.Fprintf(&buf, " %s", yx.Constraint.String())
-			}
-
-			// Print the operator of the outer JoinClause
-			buf.WriteString(y.Operator.String())
-
-			// Print the right side of the outer JoinClause
-			buf.WriteString(y.Y.String())
-
-			// Add the constraint for the outer JoinClause
-			if y.Constraint != nil {
-				fmt.Fprintf(&buf, " %s", y.Constraint.String())
-			}
-		} else {
-			// Handle the singly-nested case
-
-			// Print the left side of the nested JoinClause
-			buf.WriteString(y.X.String())
-
-			// Add the constraint for the first join
-			if c.Constraint != nil {
-				fmt.Fprintf(&buf, " %s", c.Constraint.String())
-			}
-
-			// Print the operator of the nested JoinClause
-			buf.WriteString(y.Operator.String())
-
-			// Print the right side of the nested JoinClause
-			buf.WriteString(y.Y.String())
-
-			// Add the constraint for the nested JoinClause
-			if y.Constraint != nil {
-				fmt.Fprintf(&buf, " %s", y.Constraint.String())
-			}
-		}
-	} else {
-		// Normal case: right side is not a JoinClause
-		buf.WriteString(c.Y.String())
-
-		// Add the constraint
-		if c.Constraint != nil {
-			fmt.Fprintf(&buf, " %s", c.Constraint.String())
-		}
+// writeJoinSource writes src followed by constraint. The parser nests each
+// subsequent join on the right side of the previous one, so the constraint of
+// a join belongs after the leftmost source of a nested right-hand JoinClause.
+func writeJoinSource(buf *strings.Builder, src Source, constraint JoinConstraint) {
+	if c, ok := src.(*JoinClause); ok {
+		var inner strings.Builder
+		writeJoinSource(&inner, c.X, constraint)
+		inner.WriteString(c.Operator.String())
+		writeJoinSource(&inner, c.Y, c.Constraint)
+		buf.WriteString(commented(c, inner.String()))
+		return
 	}
 
-	return buf.String()
+	buf.WriteString(src.String())
+	if constraint != nil {
+		buf.WriteString(" ")
+		buf.WriteString(constraint.String())
+	}
 }
 
 type JoinOperator struct {
+	span
+	comments
+
 	Natural bool
 	Left    bool
 	Right   bool
@@ -2688,7 +2931,7 @@ func (op *JoinOperator) subnodes(yield func(Node) bool) bool {
 // String returns the string representation of the operator.
 func (op *JoinOperator) String() string {
 	if !op.Natural && !op.Left && !op.Right && !op.Full && !op.Outer && !op.Inner && !op.Cross {
-		return ", "
+		return commented(op, ", ")
 	}
 
 	var buf strings.Builder
@@ -2718,10 +2961,13 @@ func (op *JoinOperator) String() string {
 	}
 	buf.WriteString(" JOIN ")
 
-	return buf.String()
+	return commented(op, buf.String())
 }
 
 type OnConstraint struct {
+	span
+	comments
+
 	X Expr // constraint expression
 }
 
@@ -2731,10 +2977,13 @@ func (c *OnConstraint) subnodes(yield func(Node) bool) bool {
 
 // String returns the string representation of the constraint.
 func (c *OnConstraint) String() string {
-	return "ON " + c.X.String()
+	return commented(c, "ON "+c.X.String())
 }
 
 type UsingConstraint struct {
+	span
+	comments
+
 	Columns []*Ident // column list
 }
 
@@ -2758,10 +3007,13 @@ func (c *UsingConstraint) String() string {
 		buf.WriteString(col.String())
 	}
 	buf.WriteString(")")
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type WithClause struct {
+	span
+	comments
+
 	Recursive bool
 	CTEs      []*CTE // common table expressions
 }
@@ -2790,14 +3042,19 @@ func (c *WithClause) String() string {
 		buf.WriteString(cte.String())
 	}
 
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 // CTE represents an AST node for a common table expression.
 type CTE struct {
-	TableName *Ident           // table name
-	Columns   []*Ident         // optional column list
-	Select    *SelectStatement // select statement
+	span
+	comments
+
+	TableName       *Ident   // table name
+	Columns         []*Ident // optional column list
+	Materialized    bool
+	NotMaterialized bool
+	Select          *SelectStatement // select statement
 }
 
 func (cte *CTE) subnodes(yield func(Node) bool) bool {
@@ -2830,12 +3087,22 @@ func (cte *CTE) String() string {
 		buf.WriteString(")")
 	}
 
-	fmt.Fprintf(&buf, " AS (%s)", cte.Select.String())
+	buf.WriteString(" AS ")
+	if cte.Materialized {
+		buf.WriteString("MATERIALIZED ")
+	} else if cte.NotMaterialized {
+		buf.WriteString("NOT MATERIALIZED ")
+	}
+
+	fmt.Fprintf(&buf, "(%s)", cte.Select.String())
 
-	return buf.String()
+	return commented(cte, buf.String())
 }
 
 type Window struct {
+	span
+	comments
+
 	Name       *Ident            // name of window
 	Definition *WindowDefinition // window definition
 }
@@ -2850,10 +3117,13 @@ func (w *Window) subnodes(yield func(Node) bool) bool {
 
 // String returns the string representation of the window.
 func (w *Window) String() string {
-	return fmt.Sprintf("%s AS %s", w.Name.String(), w.Definition.String())
+	return commented(w, fmt.Sprintf("%s AS %s", w.Name.String(), w.Definition.String()))
 }
 
 type WindowDefinition struct {
+	span
+	comments
+
 	Base          *Ident          // base window name (optional)
 	Partitions    []Expr          // partition expressions
 	OrderingTerms []*OrderingTerm // ordering terms
@@ -2925,10 +3195,13 @@ func (d *WindowDefinition) String() string {
 
 	buf.WriteString(")")
 
-	return buf.String()
+	return commented(d, buf.String())
 }
 
 type PragmaStatement struct {
+	span
+	comments
+
 	Schema *Ident // name of schema (optional)
 	Expr   Expr   // can be Ident, Call or BinaryExpr
 }
@@ -2948,11 +3221,14 @@ func (s *PragmaStatement) String() string {
 	}
 	buf.WriteString(s.Expr.String())
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type AttachStatement struct {
-	Expr   *Ident // database expression (can be a string literal or identifier)
+	span
+	comments
+
+	Expr   Expr   // database file name expression
 	Schema *Ident // optional schema name
 }
 
@@ -2970,10 +3246,13 @@ func (s *AttachStatement) String() string {
 		buf.WriteString(s.Schema.String())
 	}
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type DetachStatement struct {
+	span
+	comments
+
 	Schema *Ident // schema name to detach
 }
 
@@ -2987,12 +3266,15 @@ func (s *DetachStatement) String() string {
 	if s.Schema != nil {
 		buf.WriteString(s.Schema.String())
 	}
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type VacuumStatement struct {
+	span
+	comments
+
 	Schema *Ident // schema name (optional)
-	Expr   *Ident // optional expression (can be a string literal or identifier)
+	Expr   Expr   // file name expression of INTO clause (optional)
 }
 
 func (s *VacuumStatement) subnodes(yield func(Node) bool) bool {
@@ -3013,10 +3295,13 @@ func (s *VacuumStatement) String() string {
 		buf.WriteString(s.Expr.String())
 	}
 
-	return buf.String()
+	return commented(s, buf.String())
 }
 
 type ConflictClause struct {
+	span
+	comments
+
 	Rollback bool
 	Abort    bool
 	Fail     bool
@@ -3044,10 +3329,13 @@ func (c *ConflictClause) String() string {
 	} else {
 		panic("ConflictClause must have one of ROLLBACK, ABORT, FAIL, IGNORE or REPLACE set")
 	}
-	return buf.String()
+	return commented(c, buf.String())
 }
 
 type FunctionArg struct {
+	span
+	comments
+
 	Expr          Expr            // expression for the argument
 	OrderingTerms []*OrderingTerm // ordering terms (optional)
 }
@@ -3082,10 +3370,13 @@ func (a *FunctionArg) String() string {
 		}
 	}
 
-	return buf.String()
+	return commented(a, buf.String())
 }
 
 type InExpr struct {
+	span
+	comments
+
 	X               Expr             // left-hand side expression
 	Op              OpType           // operator type (IN, NOT IN)
 	Select          *SelectStatement // optional SELECT statement (if IN is a subquery)
@@ -3110,20 +3401,24 @@ func (e *InExpr) String() string {
 	}
 
 	if e.TableOrFunction != nil {
-		buf.WriteString(" ")
 		buf.WriteString(e.TableOrFunction.String())
 	} else if e.Select != nil {
+		buf.WriteString("(")
 		buf.WriteString(e.Select.String())
+		buf.WriteString(")")
 	} else if e.Values != nil {
 		buf.WriteString(e.Values.String())
 	} else {
 		panic("InExpr must have either Select, Values or TableOrFunction set")
 	}
 
-	return buf.String()
+	return commented(e, buf.String())
 }
 
 type ParenExpr struct {
+	span
+	comments
+
 	Expr Expr
 }
 
@@ -3132,5 +3427,5 @@ func (e *ParenExpr) subnodes(yield func(Node) bool) bool {
 }
 
 func (e *ParenExpr) String() string {
-	return fmt.Sprintf("(%s)", e.Expr.String())
+	return commented(e, fmt.Sprintf("(%s)", e.Expr.String()))
 }
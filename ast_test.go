@@ -25,12 +25,28 @@ func TestAlterTableStatement_String(t *testing.T) {
 			Type: &sql.Type{Name: &sql.Ident{Name: "INTEGER"}},
 		},
 	}, `ALTER TABLE "foo" ADD COLUMN "bar" INTEGER`)
+
+	AssertStatementStringer(t, &sql.AlterTableStatement{
+		Name:           &sql.QualifiedName{Name: &sql.Ident{Name: "foo"}},
+		DropColumnName: &sql.Ident{Name: "bar"},
+	}, `ALTER TABLE "foo" DROP COLUMN "bar"`)
 }
 
 func TestAnalyzeStatement_String(t *testing.T) {
 	AssertStatementStringer(t, &sql.AnalyzeStatement{Name: &sql.QualifiedName{Name: &sql.Ident{Name: "foo"}}}, `ANALYZE "foo"`)
 }
 
+func TestAttachStatement_String(t *testing.T) {
+	AssertStatementStringer(t, &sql.AttachStatement{
+		Expr: &sql.BinaryExpr{
+			X:  &sql.StringLit{Value: "file:"},
+			Op: sql.OP_CONCAT,
+			Y:  &sql.BindExpr{Name: ":name"},
+		},
+		Schema: &sql.Ident{Name: "aux"},
+	}, `ATTACH 'file:' || :name AS "aux"`)
+}
+
 func TestBeginStatement_String(t *testing.T) {
 	AssertStatementStringer(t, &sql.BeginStatement{}, `BEGIN`)
 	AssertStatementStringer(t, &sql.BeginStatement{Deferred: pos(0)}, `BEGIN DEFERRED`)
@@ -39,8 +55,28 @@ func TestBeginStatement_String(t *testing.T) {
 	AssertStatementStringer(t, &sql.BeginStatement{Immediate: pos(0)}, `BEGIN IMMEDIATE`)
 }
 
+func TestStatement_String_SurfaceSyntax(t *testing.T) {
+	for _, s := range []string{
+		"SELECT `a`, [b], \"c\" FROM `t` WHERE `a` <> 1 AND [b] == 2 LIMIT 10, 20",
+		`UPDATE "t" SET "a" = 1 LIMIT 5, 1`,
+		`DELETE FROM "t" WHERE "a" != 1 AND "b" = 2 LIMIT 1 OFFSET 5`,
+		`END`,
+		`CREATE TABLE 'x' ("a")`,
+		`SELECT 'x'."a" FROM "x"`,
+	} {
+		stmt, err := sql.ParseStmtString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := stmt.String(); got != s {
+			t.Errorf("String()=%s, want %s", got, s)
+		}
+	}
+}
+
 func TestCommitStatement_String(t *testing.T) {
 	AssertStatementStringer(t, &sql.CommitStatement{}, `COMMIT`)
+	AssertStatementStringer(t, &sql.CommitStatement{EndKeyword: true}, `END`)
 }
 
 func TestCreateIndexStatement_String(t *testing.T) {
@@ -97,6 +133,27 @@ func TestCreateTableStatement_String(t *testing.T) {
 		}},
 	}, `CREATE TABLE "foo" ("bar" INTEGER PRIMARY KEY AUTOINCREMENT CONSTRAINT "nn" NOT NULL CONSTRAINT "def" DEFAULT 123 DEFAULT 456 UNIQUE)`)
 
+	AssertStatementStringer(t, &sql.CreateTableStatement{
+		Name: &sql.QualifiedName{Name: &sql.Ident{Name: "foo"}},
+		Columns: []*sql.ColumnDefinition{{
+			Name: &sql.Ident{Name: "bar"},
+			Constraints: []sql.Constraint{
+				&sql.DefaultConstraint{Expr: &sql.UnaryExpr{Op: sql.OP_MINUS, X: &sql.NumberLit{Value: "1"}}},
+				&sql.DefaultConstraint{Expr: &sql.BinaryExpr{X: &sql.NumberLit{Value: "1"}, Op: sql.OP_PLUS, Y: &sql.NumberLit{Value: "2"}}},
+			},
+		}},
+		Constraints: []sql.Constraint{
+			&sql.UniqueConstraint{
+				Columns:  []*sql.IndexedColumn{{X: &sql.Ident{Name: "bar"}}},
+				Conflict: &sql.ConflictClause{Ignore: pos(0)},
+			},
+			&sql.PrimaryKeyConstraint{
+				Columns:  []*sql.Ident{{Name: "bar"}},
+				Conflict: &sql.ConflictClause{Replace: pos(0)},
+			},
+		},
+	}, `CREATE TABLE "foo" ("bar" DEFAULT -1 DEFAULT (1 + 2), UNIQUE ("bar") ON CONFLICT IGNORE, PRIMARY KEY ("bar") ON CONFLICT REPLACE)`)
+
 	AssertStatementStringer(t, &sql.CreateTableStatement{
 		Name: &sql.QualifiedName{Name: &sql.Ident{Name: "foo"}},
 		Columns: []*sql.ColumnDefinition{{
@@ -247,6 +304,13 @@ func TestCreateTriggerStatement_String(t *testing.T) {
 	}, `CREATE TRIGGER "trig" INSTEAD OF UPDATE OF "x", "y" ON "tbl" BEGIN DELETE FROM "x"; END`)
 }
 
+func TestCreateVirtualTableStatement_String(t *testing.T) {
+	AssertStatementStringer(t, &sql.CreateVirtualTableStatement{
+		Name:       &sql.QualifiedName{Name: &sql.Ident{Name: "vt"}},
+		ModuleName: &sql.Ident{Name: "mod"},
+	}, `CREATE VIRTUAL TABLE "vt" USING "mod"`)
+}
+
 func TestCreateViewStatement_String(t *testing.T) {
 	AssertStatementStringer(t, &sql.CreateViewStatement{
 		Name: &sql.QualifiedName{Name: &sql.Ident{Name: "vw"}},
@@ -528,6 +592,28 @@ func TestSelectStatement_String(t *testing.T) {
 		},
 	}, `SELECT ALL "x"`)
 
+	AssertStatementStringer(t, &sql.SelectStatement{
+		WithClause: &sql.WithClause{
+			CTEs: []*sql.CTE{
+				{
+					TableName:    &sql.Ident{Name: "a"},
+					Materialized: pos(0),
+					Select: &sql.SelectStatement{
+						Columns: []*sql.ResultColumn{{Star: pos(0)}},
+					},
+				},
+				{
+					TableName:       &sql.Ident{Name: "b"},
+					NotMaterialized: pos(0),
+					Select: &sql.SelectStatement{
+						Columns: []*sql.ResultColumn{{Star: pos(0)}},
+					},
+				},
+			},
+		},
+		Columns: []*sql.ResultColumn{{Star: pos(0)}},
+	}, `WITH "a" AS MATERIALIZED (SELECT *), "b" AS NOT MATERIALIZED (SELECT *) SELECT *`)
+
 	AssertStatementStringer(t, &sql.SelectStatement{
 		Columns:      []*sql.ResultColumn{{Star: pos(0)}},
 		Source:       &sql.QualifiedName{Name: &sql.Ident{Name: "tbl"}},
@@ -669,6 +755,13 @@ func TestSelectStatement_String(t *testing.T) {
 		OffsetExpr: &sql.NumberLit{Value: "2"},
 	}, `SELECT * LIMIT 1 OFFSET 2`)
 
+	AssertStatementStringer(t, &sql.SelectStatement{
+		Columns:    []*sql.ResultColumn{{Star: pos(0)}},
+		LimitExpr:  &sql.NumberLit{Value: "1"},
+		OffsetExpr: &sql.NumberLit{Value: "2"},
+		LimitComma: true,
+	}, `SELECT * LIMIT 2, 1`)
+
 	AssertStatementStringer(t, &sql.SelectStatement{
 		Columns: []*sql.ResultColumn{{Star: pos(0)}},
 		Source: &sql.JoinClause{
@@ -788,6 +881,21 @@ func TestUpdateStatement_String(t *testing.T) {
 		WhereExpr: &sql.BoolLit{Value: true},
 	}, `UPDATE "tbl" SET "x" = 100, "y" = 200 WHERE TRUE`)
 
+	AssertStatementStringer(t, &sql.UpdateStatement{
+		Table: &sql.QualifiedName{Name: &sql.Ident{Name: "tbl"}},
+		Assignments: []*sql.Assignment{
+			{Columns: []*sql.Ident{{Name: "x"}}, Expr: &sql.QualifiedRef{
+				Table:  &sql.QualifiedName{Name: &sql.Ident{Name: "d"}},
+				Column: &sql.Ident{Name: "x"},
+			}},
+		},
+		Source: &sql.QualifiedName{
+			Name:  &sql.Ident{Name: "daily"},
+			Alias: &sql.Ident{Name: "d"},
+		},
+		WhereExpr: &sql.BoolLit{Value: true},
+	}, `UPDATE "tbl" SET "x" = "d"."x" FROM "daily" AS "d" WHERE TRUE`)
+
 	AssertStatementStringer(t, &sql.UpdateStatement{
 		UpdateOrRollback: pos(0),
 		Table:            &sql.QualifiedName{Name: &sql.Ident{Name: "tbl"}},
@@ -844,9 +952,21 @@ func TestUpdateStatement_String(t *testing.T) {
 	}, `WITH "cte" AS (SELECT *) UPDATE "tbl" SET "x" = 100`)
 }
 
+func TestVacuumStatement_String(t *testing.T) {
+	AssertStatementStringer(t, &sql.VacuumStatement{}, `VACUUM`)
+	AssertStatementStringer(t, &sql.VacuumStatement{
+		Schema: &sql.Ident{Name: "main"},
+		Expr:   &sql.BindExpr{Name: "?"},
+	}, `VACUUM "main" INTO ?`)
+}
+
 func TestIdent_String(t *testing.T) {
 	AssertExprStringer(t, &sql.Ident{Name: "foo"}, `"foo"`)
 	AssertExprStringer(t, &sql.Ident{Name: "foo \" bar"}, `"foo "" bar"`)
+	AssertExprStringer(t, &sql.Ident{Name: "foo ` bar", Quoted: true, Quote: "`"}, "`foo `` bar`")
+	AssertExprStringer(t, &sql.Ident{Name: "foo bar", Quoted: true, Quote: "["}, `[foo bar]`)
+	AssertExprStringer(t, &sql.Ident{Name: "foo ] bar", Quoted: true, Quote: "["}, `"foo ] bar"`)
+	AssertExprStringer(t, &sql.Ident{Name: "foo ' bar", Quoted: true, Quote: "'"}, `'foo '' bar'`)
 }
 
 func TestStringLit_String(t *testing.T) {
@@ -872,7 +992,14 @@ func TestNullLit_String(t *testing.T) {
 }
 
 func TestBindExpr_String(t *testing.T) {
-	AssertExprStringer(t, &sql.BindExpr{Name: "foo"}, `foo`)
+	AssertExprStringer(t, &sql.BindExpr{Name: "?"}, `?`)
+	AssertExprStringer(t, &sql.BindExpr{Name: "?1"}, `?1`)
+	AssertExprStringer(t, &sql.BindExpr{Name: ":foo"}, `:foo`)
+	AssertExprStringer(t, &sql.BindExpr{Name: "@foo"}, `@foo`)
+	AssertExprStringer(t, &sql.BindExpr{Name: "$foo"}, `$foo`)
+	AssertExprStringer(t, &sql.BindExpr{Name: ""}, `?`)
+	AssertExprStringer(t, &sql.BindExpr{Name: "2"}, `?2`)
+	AssertExprStringer(t, &sql.BindExpr{Name: "foo"}, `:foo`)
 }
 
 func TestParenExpr_String(t *testing.T) {
@@ -884,6 +1011,7 @@ func TestUnaryExpr_String(t *testing.T) {
 	AssertExprStringer(t, &sql.UnaryExpr{Op: sql.OP_MINUS, X: &sql.NumberLit{Value: "100"}}, `-100`)
 	AssertExprStringer(t, &sql.UnaryExpr{Op: sql.OP_NOT, X: &sql.NumberLit{Value: "100"}}, `NOT 100`)
 	AssertExprStringer(t, &sql.UnaryExpr{Op: sql.OP_BITNOT, X: &sql.NumberLit{Value: "100"}}, `~100`)
+	AssertExprStringer(t, &sql.UnaryExpr{Op: sql.OP_MINUS, X: &sql.UnaryExpr{Op: sql.OP_MINUS, X: &sql.NumberLit{Value: "100"}}}, `- -100`)
 	AssertNodeStringerPanic(t, &sql.UnaryExpr{X: &sql.NumberLit{Value: "100"}}, `invalid op`)
 }
 
@@ -906,10 +1034,14 @@ func TestBinaryExpr_String(t *testing.T) {
 	AssertExprStringer(t, &sql.BinaryExpr{Op: sql.OP_GE, X: &sql.NumberLit{Value: "1"}, Y: &sql.NumberLit{Value: "2"}}, `1 >= 2`)
 	AssertExprStringer(t, &sql.BinaryExpr{Op: sql.OP_EQ, X: &sql.NumberLit{Value: "1"}, Y: &sql.NumberLit{Value: "2"}}, `1 = 2`)
 	AssertExprStringer(t, &sql.BinaryExpr{Op: sql.OP_NE, X: &sql.NumberLit{Value: "1"}, Y: &sql.NumberLit{Value: "2"}}, `1 != 2`)
+	AssertExprStringer(t, &sql.BinaryExpr{Op: sql.OP_EQ, AltOp: true, X: &sql.NumberLit{Value: "1"}, Y: &sql.NumberLit{Value: "2"}}, `1 == 2`)
+	AssertExprStringer(t, &sql.BinaryExpr{Op: sql.OP_NE, AltOp: true, X: &sql.NumberLit{Value: "1"}, Y: &sql.NumberLit{Value: "2"}}, `1 <> 2`)
 	AssertExprStringer(t, &sql.BinaryExpr{Op: sql.OP_IS, X: &sql.NumberLit{Value: "1"}, Y: &sql.NumberLit{Value: "2"}}, `1 IS 2`)
 	AssertExprStringer(t, &sql.BinaryExpr{Op: sql.OP_IS_NOT, X: &sql.NumberLit{Value: "1"}, Y: &sql.NumberLit{Value: "2"}}, `1 IS NOT 2`)
 	AssertExprStringer(t, &sql.InExpr{Op: sql.OP_IN, X: &sql.NumberLit{Value: "1"}, Values: &sql.ExprList{Exprs: []sql.Expr{&sql.NumberLit{Value: "2"}}}}, `1 IN (2)`)
 	AssertExprStringer(t, &sql.InExpr{Op: sql.OP_NOT_IN, X: &sql.NumberLit{Value: "1"}, Values: &sql.ExprList{Exprs: []sql.Expr{&sql.NumberLit{Value: "2"}}}}, `1 NOT IN (2)`)
+	AssertExprStringer(t, &sql.InExpr{Op: sql.OP_IN, X: &sql.NumberLit{Value: "1"}, Select: &sql.SelectStatement{Columns: []*sql.ResultColumn{{Star: pos(0)}}}}, `1 IN (SELECT *)`)
+	AssertExprStringer(t, &sql.InExpr{Op: sql.OP_IN, X: &sql.NumberLit{Value: "1"}, TableOrFunction: &sql.QualifiedName{Name: &sql.Ident{Name: "tbl"}}}, `1 IN "tbl"`)
 	AssertExprStringer(t, &sql.BinaryExpr{Op: sql.OP_LIKE, X: &sql.NumberLit{Value: "1"}, Y: &sql.NumberLit{Value: "2"}}, `1 LIKE 2`)
 	AssertExprStringer(t, &sql.BinaryExpr{Op: sql.OP_NOT_LIKE, X: &sql.NumberLit{Value: "1"}, Y: &sql.NumberLit{Value: "2"}}, `1 NOT LIKE 2`)
 	AssertExprStringer(t, &sql.BinaryExpr{Op: sql.OP_GLOB, X: &sql.NumberLit{Value: "1"}, Y: &sql.NumberLit{Value: "2"}}, `1 GLOB 2`)
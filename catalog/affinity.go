@@ -0,0 +1,59 @@
+package catalog
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Affinity is the type affinity of a column.
+//
+// See: https://www.sqlite.org/datatype3.html#type_affinity
+type Affinity int
+
+const (
+	BLOB    Affinity = iota // no conversion, also known as NONE
+	TEXT                    // stored as text
+	NUMERIC                 // stored as integer or real if lossless
+	INTEGER                 // like NUMERIC, differs only in CAST
+	REAL                    // stored as floating point
+)
+
+var affinities = [...]string{
+	BLOB:    "BLOB",
+	TEXT:    "TEXT",
+	NUMERIC: "NUMERIC",
+	INTEGER: "INTEGER",
+	REAL:    "REAL",
+}
+
+// String returns the name of the affinity.
+func (a Affinity) String() string {
+	if a >= 0 && int(a) < len(affinities) {
+		return affinities[a]
+	}
+	return "Affinity(" + strconv.Itoa(int(a)) + ")"
+}
+
+// TypeAffinity returns the affinity of a column with the declared type typ,
+// following the rules of SQLite in order:
+//
+//  1. INTEGER if typ contains "INT".
+//  2. TEXT if typ contains "CHAR", "CLOB" or "TEXT".
+//  3. BLOB if typ contains "BLOB" or is empty.
+//  4. REAL if typ contains "REAL", "FLOA" or "DOUB".
+//  5. NUMERIC otherwise.
+func TypeAffinity(typ string) Affinity {
+	typ = strings.ToUpper(typ)
+	switch {
+	case strings.Contains(typ, "INT"):
+		return INTEGER
+	case strings.Contains(typ, "CHAR"), strings.Contains(typ, "CLOB"), strings.Contains(typ, "TEXT"):
+		return TEXT
+	case typ == "", strings.Contains(typ, "BLOB"):
+		return BLOB
+	case strings.Contains(typ, "REAL"), strings.Contains(typ, "FLOA"), strings.Contains(typ, "DOUB"):
+		return REAL
+	default:
+		return NUMERIC
+	}
+}
@@ -0,0 +1,1224 @@
+// Package catalog provides an in-memory model of a database schema.
+//
+// A Catalog is built by executing DDL statements in order. Statements are
+// applied with the semantics of SQLite: names are case-insensitive, tables,
+// views & indexes share a namespace, dropping a table drops its indexes &
+// triggers and renaming a table or column updates the objects referring to
+// it. Statements that conflict with the current schema return an *Error and
+// leave the catalog unchanged.
+package catalog
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/TcMits/sql"
+)
+
+// Error is returned when a statement cannot be applied to a catalog.
+type Error struct {
+	Stmt sql.Statement // statement that failed
+	Msg  string        // error message, as reported by SQLite
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+// Catalog represents the schemas of a database connection.
+type Catalog struct {
+	schemas []*Schema // main, temp, then attached schemas in order
+	src     string    // source text passed to ExecString, while executing it
+}
+
+// New returns a new catalog with empty "main" and "temp" schemas.
+func New() *Catalog {
+	return &Catalog{schemas: []*Schema{{Name: "main"}, {Name: "temp"}}}
+}
+
+// Schema returns the schema named name, or nil if it does not exist.
+func (c *Catalog) Schema(name string) *Schema {
+	for _, s := range c.schemas {
+		if equalName(s.Name, name) {
+			return s
+		}
+	}
+	return nil
+}
+
+// Schemas returns all schemas: "main", "temp", then attached schemas.
+func (c *Catalog) Schemas() []*Schema {
+	return slices.Clone(c.schemas)
+}
+
+// searchOrder returns the schemas in the order SQLite searches them for an
+// unqualified name: "temp", "main", then attached schemas.
+func (c *Catalog) searchOrder() []*Schema {
+	order := []*Schema{c.schemas[1], c.schemas[0]}
+	return append(order, c.schemas[2:]...)
+}
+
+// Table returns the table named name, searching all schemas in order, or nil.
+func (c *Catalog) Table(name string) *Table {
+	for _, s := range c.searchOrder() {
+		if t := s.Table(name); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// View returns the view named name, searching all schemas in order, or nil.
+func (c *Catalog) View(name string) *View {
+	for _, s := range c.searchOrder() {
+		if v := s.View(name); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// Index returns the index named name, searching all schemas in order, or nil.
+func (c *Catalog) Index(name string) *Index {
+	for _, s := range c.searchOrder() {
+		if idx := s.Index(name); idx != nil {
+			return idx
+		}
+	}
+	return nil
+}
+
+// Trigger returns the trigger named name, searching all schemas in order, or
+// nil.
+func (c *Catalog) Trigger(name string) *Trigger {
+	for _, s := range c.searchOrder() {
+		if trig := s.Trigger(name); trig != nil {
+			return trig
+		}
+	}
+	return nil
+}
+
+// ExecString parses s and executes each statement in order. It stops at the
+// first syntax error or conflict.
+func (c *Catalog) ExecString(s string) error {
+	c.src = s
+	defer func() { c.src = "" }()
+	return sql.ParseMultiStmtString(s, c.Exec)
+}
+
+// Exec applies the schema changes of stmt to the catalog. Statements that do
+// not change the schema, such as SELECT or INSERT, are ignored. The nodes of
+// stmt are copied and not retained.
+func (c *Catalog) Exec(stmt sql.Statement) error {
+	var err error
+	switch stmt := stmt.(type) {
+	case *sql.CreateTableStatement:
+		err = c.createTable(stmt)
+	case *sql.CreateVirtualTableStatement:
+		err = c.createVirtualTable(stmt)
+	case *sql.CreateIndexStatement:
+		err = c.createIndex(stmt)
+	case *sql.CreateViewStatement:
+		err = c.createView(stmt)
+	case *sql.CreateTriggerStatement:
+		err = c.createTrigger(stmt)
+	case *sql.AlterTableStatement:
+		err = c.alterTable(stmt)
+	case *sql.DropTableStatement:
+		err = c.dropTable(stmt)
+	case *sql.DropIndexStatement:
+		err = c.dropIndex(stmt)
+	case *sql.DropViewStatement:
+		err = c.dropView(stmt)
+	case *sql.DropTriggerStatement:
+		err = c.dropTrigger(stmt)
+	case *sql.AttachStatement:
+		err = c.attach(stmt)
+	case *sql.DetachStatement:
+		err = c.detach(stmt)
+	}
+	if err != nil {
+		return &Error{Stmt: stmt, Msg: err.Error()}
+	}
+	return nil
+}
+
+// Schema represents a single database of a catalog, such as "main".
+type Schema struct {
+	Name string // schema name
+
+	tables   []*Table
+	views    []*View
+	indexes  []*Index
+	triggers []*Trigger
+}
+
+// Table returns the table named name, or nil.
+func (s *Schema) Table(name string) *Table {
+	return find(s.tables, func(t *Table) string { return t.Name }, name)
+}
+
+// Tables returns the tables of the schema in order of creation.
+func (s *Schema) Tables() []*Table {
+	return slices.Clone(s.tables)
+}
+
+// View returns the view named name, or nil.
+func (s *Schema) View(name string) *View {
+	return find(s.views, func(v *View) string { return v.Name }, name)
+}
+
+// Views returns the views of the schema in order of creation.
+func (s *Schema) Views() []*View {
+	return slices.Clone(s.views)
+}
+
+// Index returns the index named name, or nil.
+func (s *Schema) Index(name string) *Index {
+	return find(s.indexes, func(idx *Index) string { return idx.Name }, name)
+}
+
+// Indexes returns the indexes of the schema in order of creation.
+func (s *Schema) Indexes() []*Index {
+	return slices.Clone(s.indexes)
+}
+
+// Trigger returns the trigger named name, or nil.
+func (s *Schema) Trigger(name string) *Trigger {
+	return find(s.triggers, func(trig *Trigger) string { return trig.Name }, name)
+}
+
+// Triggers returns the triggers of the schema in order of creation.
+func (s *Schema) Triggers() []*Trigger {
+	return slices.Clone(s.triggers)
+}
+
+// checkTableName returns an error if a table or view named name cannot be
+// created in s because the name is taken by a table, view or index.
+func (s *Schema) checkTableName(name string) error {
+	if t := s.Table(name); t != nil {
+		return fmt.Errorf("table %s already exists", t.Name)
+	} else if v := s.View(name); v != nil {
+		return fmt.Errorf("view %s already exists", v.Name)
+	} else if idx := s.Index(name); idx != nil {
+		return fmt.Errorf("there is already an index named %s", idx.Name)
+	}
+	return nil
+}
+
+// Table represents a table, including virtual tables.
+type Table struct {
+	Name         string
+	Columns      []*Column        // columns in declaration order
+	Constraints  []sql.Constraint // table constraints
+	WithoutRowID bool
+	Strict       bool
+
+	Virtual bool     // true if created with CREATE VIRTUAL TABLE
+	Module  string   // module name of a virtual table
+	Args    []string // module arguments of a virtual table
+}
+
+// Column returns the column named name, or nil.
+func (t *Table) Column(name string) *Column {
+	return find(t.Columns, func(col *Column) string { return col.Name }, name)
+}
+
+// PrimaryKey returns the names of the primary key columns, or nil if the
+// table has no explicit primary key.
+func (t *Table) PrimaryKey() []string {
+	for _, cons := range t.Constraints {
+		if cons, ok := cons.(*sql.PrimaryKeyConstraint); ok {
+			names := make([]string, len(cons.Columns))
+			for i, col := range cons.Columns {
+				names[i] = t.Column(col.Name).Name
+			}
+			return names
+		}
+	}
+	for _, col := range t.Columns {
+		if col.PrimaryKey {
+			return []string{col.Name}
+		}
+	}
+	return nil
+}
+
+// Column represents a column of a table.
+type Column struct {
+	Name          string
+	Type          string // declared type, empty if none
+	NotNull       bool
+	PrimaryKey    bool // true if part of the primary key
+	Autoincrement bool
+	Unique        bool     // true if unique on its own
+	Default       sql.Expr // default value, nil if none
+	Collation     string   // collation name, empty if none
+	Generated     sql.Expr // generated column expression, nil if none
+	Stored        bool     // true if a stored generated column
+
+	Constraints []sql.Constraint // column constraints
+}
+
+// Affinity returns the type affinity of the column.
+func (col *Column) Affinity() Affinity {
+	return TypeAffinity(col.Type)
+}
+
+// Index represents an index.
+type Index struct {
+	Name      string
+	Table     string // name of the indexed table
+	Unique    bool
+	Columns   []*sql.IndexedColumn
+	WhereExpr sql.Expr // condition of a partial index, nil if none
+}
+
+// View represents a view.
+type View struct {
+	Name    string
+	Columns []string // explicit column names, nil if none
+	Select  *sql.SelectStatement
+}
+
+// Trigger represents a trigger.
+type Trigger struct {
+	Name  string
+	Table string // name of the table or view the trigger is attached to
+	Stmt  *sql.CreateTriggerStatement
+}
+
+// targetSchema returns the schema an object named name is created in.
+func (c *Catalog) targetSchema(name *sql.QualifiedName, temp bool, kind string) (*Schema, error) {
+	if name.Schema == nil {
+		if temp {
+			return c.schemas[1], nil
+		}
+		return c.schemas[0], nil
+	}
+
+	s := c.Schema(name.Schema.Name)
+	if s == nil {
+		return nil, fmt.Errorf("unknown database %s", name.Schema.Name)
+	} else if temp && s != c.schemas[1] {
+		return nil, fmt.Errorf("temporary %s name must be unqualified", kind)
+	}
+	return s, nil
+}
+
+// onTempTable returns true if the index or trigger name is unqualified and
+// its table is a table or view of the temp schema, in which case SQLite
+// creates it in the temp schema.
+func (c *Catalog) onTempTable(name *sql.QualifiedName, table string) bool {
+	temp := c.schemas[1]
+	return name.Schema == nil && (temp.Table(table) != nil || temp.View(table) != nil)
+}
+
+// lookupSchemas returns the schemas searched for an existing object named name.
+func (c *Catalog) lookupSchemas(name *sql.QualifiedName) ([]*Schema, error) {
+	if name.Schema == nil {
+		return c.searchOrder(), nil
+	}
+	s := c.Schema(name.Schema.Name)
+	if s == nil {
+		return nil, fmt.Errorf("no such database: %s", name.Schema.Name)
+	}
+	return []*Schema{s}, nil
+}
+
+// displayName returns name as written, including the schema if any.
+func displayName(name *sql.QualifiedName) string {
+	if name.Schema != nil {
+		return name.Schema.Name + "." + name.Name.Name
+	}
+	return name.Name.Name
+}
+
+func checkReserved(name string) error {
+	if len(name) >= 7 && equalName(name[:7], "sqlite_") {
+		return fmt.Errorf("object name reserved for internal use: %s", name)
+	}
+	return nil
+}
+
+func (c *Catalog) createTable(stmt *sql.CreateTableStatement) error {
+	s, err := c.targetSchema(stmt.Name, stmt.Temp, "table")
+	if err != nil {
+		return err
+	}
+
+	name := stmt.Name.Name.Name
+	if err := checkReserved(name); err != nil {
+		return err
+	}
+	if err := s.checkTableName(name); err != nil {
+		if stmt.IfNotExists && s.Index(name) == nil {
+			return nil
+		}
+		return err
+	}
+
+	t := &Table{Name: name, WithoutRowID: stmt.WithoutRowID, Strict: stmt.Strict}
+	if stmt.Select != nil {
+		if err := c.tableFromSelect(t, stmt.Select); err != nil {
+			return err
+		}
+	} else if err := t.define(stmt); err != nil {
+		return err
+	}
+	s.tables = append(s.tables, t)
+	return nil
+}
+
+// define sets the columns & constraints of t from stmt.
+func (t *Table) define(stmt *sql.CreateTableStatement) error {
+	stmt = sql.Clone(stmt).(*sql.CreateTableStatement)
+
+	var pks int
+	for _, def := range stmt.Columns {
+		if t.Column(def.Name.Name) != nil {
+			return fmt.Errorf("duplicate column name: %s", def.Name.Name)
+		}
+		col := newColumn(def)
+		if col.PrimaryKey {
+			pks++
+		}
+		t.Columns = append(t.Columns, col)
+	}
+
+	for _, cons := range stmt.Constraints {
+		switch cons := cons.(type) {
+		case *sql.PrimaryKeyConstraint:
+			pks++
+			for _, ident := range cons.Columns {
+				col := t.Column(ident.Name)
+				if col == nil {
+					return fmt.Errorf("no such column: %s", ident.Name)
+				}
+				col.PrimaryKey = true
+				col.Autoincrement = cons.Autoincrement
+			}
+		case *sql.UniqueConstraint:
+			for _, ic := range cons.Columns {
+				ident, ok := ic.X.(*sql.Ident)
+				if !ok {
+					return fmt.Errorf("expressions prohibited in PRIMARY KEY and UNIQUE constraints")
+				} else if t.Column(ident.Name) == nil {
+					return fmt.Errorf("no such column: %s", ident.Name)
+				}
+			}
+			if len(cons.Columns) == 1 {
+				t.Column(cons.Columns[0].X.(*sql.Ident).Name).Unique = true
+			}
+		case *sql.ForeignKeyConstraint:
+			for _, ident := range cons.Columns {
+				if t.Column(ident.Name) == nil {
+					return fmt.Errorf("unknown column %q in foreign key definition", ident.Name)
+				}
+			}
+		}
+	}
+	t.Constraints = stmt.Constraints
+
+	if pks > 1 {
+		return fmt.Errorf("table %q has more than one primary key", t.Name)
+	}
+	if t.WithoutRowID && pks == 0 {
+		return fmt.Errorf("PRIMARY KEY missing on table %s", t.Name)
+	}
+	for _, col := range t.Columns {
+		if col.Autoincrement && (t.WithoutRowID || !equalName(col.Type, "INTEGER") || len(t.PrimaryKey()) > 1) {
+			return fmt.Errorf("AUTOINCREMENT is only allowed on an INTEGER PRIMARY KEY")
+		}
+	}
+	if t.Strict {
+		for _, col := range t.Columns {
+			if err := t.checkStrictType(col); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkStrictType returns an error if col has a type not allowed in a STRICT table.
+func (t *Table) checkStrictType(col *Column) error {
+	switch strings.ToUpper(col.Type) {
+	case "INT", "INTEGER", "REAL", "TEXT", "BLOB", "ANY":
+		return nil
+	case "":
+		return fmt.Errorf("missing datatype for %s.%s", t.Name, col.Name)
+	default:
+		return fmt.Errorf("unknown datatype for %s.%s: %q", t.Name, col.Name, col.Type)
+	}
+}
+
+// newColumn returns a column defined by def.
+func newColumn(def *sql.ColumnDefinition) *Column {
+	col := &Column{Name: def.Name.Name, Constraints: def.Constraints}
+	if def.Type != nil {
+		col.Type = def.Type.String()
+	}
+
+	for _, cons := range def.Constraints {
+		switch cons := cons.(type) {
+		case *sql.PrimaryKeyConstraint:
+			col.PrimaryKey = true
+			col.Autoincrement = cons.Autoincrement
+		case *sql.NotNullConstraint:
+			col.NotNull = true
+		case *sql.UniqueConstraint:
+			col.Unique = true
+		case *sql.DefaultConstraint:
+			col.Default = cons.Expr
+		case *sql.CollateConstraint:
+			col.Collation = cons.Collation.Name
+		case *sql.GeneratedConstraint:
+			col.Generated = cons.Expr
+			col.Stored = cons.Stored
+		}
+	}
+	return col
+}
+
+// tableFromSelect sets the columns of t from the result columns of sel, like
+// CREATE TABLE ... AS SELECT. Columns have no constraints and their declared
+// type is derived from the affinity of the expression.
+func (c *Catalog) tableFromSelect(t *Table, sel *sql.SelectStatement) error {
+	cols, err := c.resultColumns(sel, nil)
+	if err != nil {
+		return err
+	}
+	for _, col := range cols {
+		name := c.columnName(col)
+		for i := 1; t.Column(name) != nil; i++ {
+			name = col.Name + ":" + strconv.Itoa(i)
+		}
+		t.Columns = append(t.Columns, &Column{Name: name, Type: col.Type})
+	}
+	return nil
+}
+
+// columnName returns the name of a column created from col. Like SQLite, an
+// expression other than a column reference is named after its source text,
+// which is only known for statements executed by ExecString.
+func (c *Catalog) columnName(col resultColumn) string {
+	switch col.Expr.(type) {
+	case nil, *sql.Ident, *sql.QualifiedRef:
+		return col.Name
+	}
+	pos, end := col.Expr.Pos().GetOffset(), col.Expr.End().GetOffset()
+	if pos >= end || end > len(c.src) {
+		return col.Name
+	}
+	return strings.TrimSpace(c.src[pos:end])
+}
+
+func (c *Catalog) createVirtualTable(stmt *sql.CreateVirtualTableStatement) error {
+	s, err := c.targetSchema(stmt.Name, false, "table")
+	if err != nil {
+		return err
+	}
+
+	name := stmt.Name.Name.Name
+	if err := checkReserved(name); err != nil {
+		return err
+	}
+	if err := s.checkTableName(name); err != nil {
+		if stmt.IfNotExists && s.Index(name) == nil {
+			return nil
+		}
+		return err
+	}
+
+	t := &Table{Name: name, Virtual: true, Module: stmt.ModuleName.Name}
+	for _, arg := range stmt.Arguments {
+		t.Args = append(t.Args, arg.String())
+	}
+	s.tables = append(s.tables, t)
+	return nil
+}
+
+func (c *Catalog) createIndex(stmt *sql.CreateIndexStatement) error {
+	s, err := c.targetSchema(stmt.Name, c.onTempTable(stmt.Name, stmt.Table.Name), "index")
+	if err != nil {
+		return err
+	}
+
+	name := stmt.Name.Name.Name
+	if err := checkReserved(name); err != nil {
+		return err
+	}
+
+	t := s.Table(stmt.Table.Name)
+	if t == nil {
+		if s.View(stmt.Table.Name) != nil {
+			return fmt.Errorf("views may not be indexed")
+		}
+		return fmt.Errorf("no such table: %s.%s", s.Name, stmt.Table.Name)
+	} else if t.Virtual {
+		return fmt.Errorf("virtual tables may not be indexed")
+	}
+
+	if idx := s.Index(name); idx != nil {
+		if stmt.IfNotExists {
+			return nil
+		}
+		return fmt.Errorf("index %s already exists", idx.Name)
+	} else if other := s.Table(name); other != nil {
+		return fmt.Errorf("there is already a table named %s", other.Name)
+	} else if v := s.View(name); v != nil {
+		return fmt.Errorf("there is already a table named %s", v.Name)
+	}
+
+	stmt = sql.Clone(stmt).(*sql.CreateIndexStatement)
+	for _, ic := range stmt.Columns {
+		if err := t.checkColumnRefs(ic.X); err != nil {
+			return err
+		}
+	}
+	if err := t.checkColumnRefs(stmt.WhereExpr); err != nil {
+		return err
+	}
+
+	s.indexes = append(s.indexes, &Index{
+		Name:      name,
+		Table:     t.Name,
+		Unique:    stmt.Unique,
+		Columns:   stmt.Columns,
+		WhereExpr: stmt.WhereExpr,
+	})
+	return nil
+}
+
+// checkColumnRefs returns an error if expr refers to a column not in t.
+func (t *Table) checkColumnRefs(expr sql.Expr) error {
+	var err error
+	columnRefs(expr, func(ident *sql.Ident) {
+		if err == nil && t.Column(ident.Name) == nil {
+			err = fmt.Errorf("no such column: %s", ident.Name)
+		}
+	})
+	return err
+}
+
+func (c *Catalog) createView(stmt *sql.CreateViewStatement) error {
+	s, err := c.targetSchema(stmt.Name, stmt.Temp, "view")
+	if err != nil {
+		return err
+	}
+
+	name := stmt.Name.Name.Name
+	if err := checkReserved(name); err != nil {
+		return err
+	}
+	if err := s.checkTableName(name); err != nil {
+		if stmt.IfNotExists && s.Index(name) == nil {
+			return nil
+		}
+		return err
+	}
+
+	v := &View{Name: name, Select: sql.Clone(stmt.Select).(*sql.SelectStatement)}
+	for _, col := range stmt.Columns {
+		v.Columns = append(v.Columns, col.Name)
+	}
+	s.views = append(s.views, v)
+	return nil
+}
+
+func (c *Catalog) createTrigger(stmt *sql.CreateTriggerStatement) error {
+	s, err := c.targetSchema(stmt.Name, stmt.Temp || c.onTempTable(stmt.Name, stmt.Table.Name), "trigger")
+	if err != nil {
+		return err
+	}
+
+	name := stmt.Name.Name.Name
+	if err := checkReserved(name); err != nil {
+		return err
+	}
+	if trig := s.Trigger(name); trig != nil {
+		if stmt.IfNotExists {
+			return nil
+		}
+		return fmt.Errorf("trigger %s already exists", trig.Name)
+	}
+
+	// A temporary trigger may be attached to a table of any schema.
+	schemas := []*Schema{s}
+	if s == c.schemas[1] {
+		schemas = c.searchOrder()
+	}
+
+	var table string
+	for _, s := range schemas {
+		if t := s.Table(stmt.Table.Name); t != nil {
+			if t.Virtual {
+				return fmt.Errorf("cannot create triggers on virtual tables")
+			} else if stmt.InsteadOf {
+				return fmt.Errorf("cannot create INSTEAD OF trigger on table: %s", t.Name)
+			}
+			table = t.Name
+			break
+		} else if v := s.View(stmt.Table.Name); v != nil {
+			if stmt.Before {
+				return fmt.Errorf("cannot create BEFORE trigger on view: %s", v.Name)
+			} else if stmt.After {
+				return fmt.Errorf("cannot create AFTER trigger on view: %s", v.Name)
+			}
+			table = v.Name
+			break
+		}
+	}
+	if table == "" {
+		return fmt.Errorf("no such table: %s.%s", s.Name, stmt.Table.Name)
+	}
+
+	s.triggers = append(s.triggers, &Trigger{
+		Name:  name,
+		Table: table,
+		Stmt:  sql.Clone(stmt).(*sql.CreateTriggerStatement),
+	})
+	return nil
+}
+
+func (c *Catalog) alterTable(stmt *sql.AlterTableStatement) error {
+	schemas, err := c.lookupSchemas(stmt.Name)
+	if err != nil {
+		return err
+	}
+
+	var s *Schema
+	var t *Table
+	for _, s = range schemas {
+		if t = s.Table(stmt.Name.Name.Name); t != nil {
+			break
+		} else if v := s.View(stmt.Name.Name.Name); v != nil {
+			return fmt.Errorf("Cannot alter view %s", v.Name)
+		}
+	}
+	if t == nil {
+		return fmt.Errorf("no such table: %s", displayName(stmt.Name))
+	} else if checkReserved(t.Name) != nil {
+		return fmt.Errorf("table %s may not be altered", t.Name)
+	} else if t.Virtual && stmt.NewName == nil {
+		return fmt.Errorf("virtual tables may not be altered")
+	}
+
+	switch {
+	case stmt.NewName != nil:
+		return c.renameTable(s, t, stmt.NewName.Name)
+	case stmt.ColumnName != nil:
+		return c.renameColumn(s, t, stmt.ColumnName.Name, stmt.NewColumnName.Name)
+	case stmt.ColumnDef != nil:
+		return t.addColumn(stmt.ColumnDef)
+	case stmt.DropColumnName != nil:
+		return c.dropColumn(s, t, stmt.DropColumnName.Name)
+	}
+	return nil
+}
+
+// resolveAll resolves the names within the views & trigger bodies of all
+// schemas, ignoring errors.
+func (c *Catalog) resolveAll() *Resolution {
+	r := newResolver(c)
+	for _, s := range c.schemas {
+		for _, v := range s.views {
+			r.selectStmt(v.Select, nil, nil, nil)
+		}
+		for _, trig := range s.triggers {
+			r.triggerStmt(s, trig)
+		}
+	}
+	return r.res
+}
+
+func (c *Catalog) renameTable(s *Schema, t *Table, name string) error {
+	if err := checkReserved(name); err != nil {
+		return err
+	}
+	if s.Table(name) != nil || s.View(name) != nil || s.Index(name) != nil {
+		return fmt.Errorf("there is already another table or index with this name: %s", name)
+	}
+
+	// Update references from views & trigger bodies. Columns are qualified
+	// by the table name unless the table is aliased.
+	res := c.resolveAll()
+	unaliased := func(src *Source) bool {
+		node, ok := src.Node.(*sql.QualifiedName)
+		return src.Table == t && ok && node.Alias == nil
+	}
+	for node, src := range res.Sources {
+		if node, ok := node.(*sql.QualifiedName); ok && src.Table == t {
+			node.Name.Name = name
+		}
+	}
+	renameRef := func(ref *sql.QualifiedRef, src *Source) {
+		if unaliased(src) && equalName(ref.Table.Name.Name, t.Name) {
+			ref.Table.Name.Name = name
+		}
+	}
+	for expr, b := range res.Refs {
+		if ref, ok := expr.(*sql.QualifiedRef); ok && b.Source != nil {
+			renameRef(ref, b.Source)
+		}
+	}
+	for node, bindings := range res.Stars {
+		if ref, ok := node.(*sql.QualifiedRef); ok && len(bindings) > 0 {
+			renameRef(ref, bindings[0].Source)
+		}
+	}
+
+	for _, idx := range s.indexes {
+		if equalName(idx.Table, t.Name) {
+			idx.Table = name
+		}
+	}
+	for _, trig := range s.triggers {
+		if equalName(trig.Table, t.Name) {
+			trig.Table = name
+			trig.Stmt.Table.Name = name
+		}
+	}
+	for _, other := range s.tables {
+		for _, fk := range other.foreignKeys() {
+			if equalName(fk.ForeignTable.Name, t.Name) {
+				fk.ForeignTable.Name = name
+			}
+		}
+	}
+	t.Name = name
+	return nil
+}
+
+func (c *Catalog) renameColumn(s *Schema, t *Table, name, newName string) error {
+	col := t.Column(name)
+	if col == nil {
+		return fmt.Errorf("no such column: %q", name)
+	} else if !equalName(name, newName) && t.Column(newName) != nil {
+		return fmt.Errorf("duplicate column name: %s", newName)
+	}
+
+	rename := func(ident *sql.Ident) {
+		if ident != nil && equalName(ident.Name, col.Name) {
+			ident.Name = newName
+		}
+	}
+
+	// Update references from views & trigger bodies, including the columns
+	// inserted or updated by the statements of trigger bodies.
+	res := c.resolveAll()
+	for expr, b := range res.Refs {
+		if b.Source == nil || b.Source.Table != t || !equalName(b.Column, col.Name) {
+			continue
+		}
+		switch expr := expr.(type) {
+		case *sql.Ident:
+			expr.Name = newName
+		case *sql.QualifiedRef:
+			expr.Column.Name = newName
+		}
+	}
+	for _, other := range c.schemas {
+		for _, trig := range other.triggers {
+			for _, stmt := range trig.Stmt.Body {
+				switch stmt := stmt.(type) {
+				case *sql.InsertStatement:
+					if res.Sources[stmt.Table].Table == t {
+						for _, ident := range stmt.Columns {
+							rename(ident)
+						}
+					}
+				case *sql.UpdateStatement:
+					if res.Sources[stmt.Table].Table == t {
+						for _, a := range stmt.Assignments {
+							for _, ident := range a.Columns {
+								rename(ident)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Update references within the table.
+	for _, col := range t.Columns {
+		for _, cons := range col.Constraints {
+			columnRefs(cons, rename)
+		}
+	}
+	for _, cons := range t.Constraints {
+		switch cons := cons.(type) {
+		case *sql.PrimaryKeyConstraint:
+			for _, ident := range cons.Columns {
+				rename(ident)
+			}
+		case *sql.ForeignKeyConstraint:
+			for _, ident := range cons.Columns {
+				rename(ident)
+			}
+		default:
+			columnRefs(cons, rename)
+		}
+	}
+
+	// Update references from indexes, triggers & foreign keys.
+	for _, idx := range s.indexes {
+		if equalName(idx.Table, t.Name) {
+			for _, ic := range idx.Columns {
+				columnRefs(ic.X, rename)
+			}
+			columnRefs(idx.WhereExpr, rename)
+		}
+	}
+	for _, trig := range s.triggers {
+		if equalName(trig.Table, t.Name) {
+			for _, ident := range trig.Stmt.UpdateOfColumns {
+				rename(ident)
+			}
+		}
+	}
+	for _, other := range s.tables {
+		for _, fk := range other.foreignKeys() {
+			if equalName(fk.ForeignTable.Name, t.Name) {
+				for _, ident := range fk.ForeignColumns {
+					rename(ident)
+				}
+			}
+		}
+	}
+
+	col.Name = newName
+	return nil
+}
+
+func (t *Table) addColumn(def *sql.ColumnDefinition) error {
+	if t.Column(def.Name.Name) != nil {
+		return fmt.Errorf("duplicate column name: %s", def.Name.Name)
+	}
+
+	col := newColumn(sql.Clone(def).(*sql.ColumnDefinition))
+	switch {
+	case col.PrimaryKey:
+		return fmt.Errorf("Cannot add a PRIMARY KEY column")
+	case col.Unique:
+		return fmt.Errorf("Cannot add a UNIQUE column")
+	case col.Generated != nil && col.Stored:
+		return fmt.Errorf("cannot add a STORED column")
+	case col.NotNull && col.Generated == nil && isNull(col.Default):
+		return fmt.Errorf("Cannot add a NOT NULL column with default value NULL")
+	}
+	if t.Strict {
+		if err := t.checkStrictType(col); err != nil {
+			return err
+		}
+	}
+	t.Columns = append(t.Columns, col)
+	return nil
+}
+
+func isNull(expr sql.Expr) bool {
+	switch expr := expr.(type) {
+	case nil, *sql.NullLit:
+		return true
+	case *sql.ParenExpr:
+		return isNull(expr.Expr)
+	}
+	return false
+}
+
+func (c *Catalog) dropColumn(s *Schema, t *Table, name string) error {
+	col := t.Column(name)
+	if col == nil {
+		return fmt.Errorf("no such column: %q", name)
+	} else if col.PrimaryKey {
+		return fmt.Errorf("cannot drop PRIMARY KEY column: %q", col.Name)
+	} else if col.Unique {
+		return fmt.Errorf("cannot drop UNIQUE column: %q", col.Name)
+	} else if len(t.Columns) == 1 {
+		return fmt.Errorf("cannot drop column %q: no other columns exist", col.Name)
+	}
+
+	refersTo := func(n sql.Node) (found bool) {
+		columnRefs(n, func(ident *sql.Ident) {
+			found = found || equalName(ident.Name, col.Name)
+		})
+		return found
+	}
+	for _, idx := range s.indexes {
+		if !equalName(idx.Table, t.Name) {
+			continue
+		}
+		for _, ic := range idx.Columns {
+			if refersTo(ic.X) {
+				return fmt.Errorf("error in index %s after drop column: no such column: %s", idx.Name, col.Name)
+			}
+		}
+		if refersTo(idx.WhereExpr) {
+			return fmt.Errorf("error in index %s after drop column: no such column: %s", idx.Name, col.Name)
+		}
+	}
+	for _, cons := range t.Constraints {
+		switch cons := cons.(type) {
+		case *sql.UniqueConstraint:
+			if slices.ContainsFunc(cons.Columns, func(ic *sql.IndexedColumn) bool { return refersTo(ic.X) }) {
+				return fmt.Errorf("cannot drop UNIQUE column: %q", col.Name)
+			}
+		case *sql.ForeignKeyConstraint:
+			if slices.ContainsFunc(cons.Columns, func(ident *sql.Ident) bool {
+				return equalName(ident.Name, col.Name)
+			}) {
+				return fmt.Errorf("cannot drop column %q: used in a foreign key", col.Name)
+			}
+		default:
+			if refersTo(cons) {
+				return fmt.Errorf("error in table %s after drop column: no such column: %s", t.Name, col.Name)
+			}
+		}
+	}
+	for _, other := range t.Columns {
+		if other != col && slices.ContainsFunc(other.Constraints, func(cons sql.Constraint) bool { return refersTo(cons) }) {
+			return fmt.Errorf("error in table %s after drop column: no such column: %s", t.Name, col.Name)
+		}
+	}
+
+	columns := t.Columns
+	t.Columns = slices.DeleteFunc(slices.Clone(t.Columns), func(other *Column) bool { return other == col })
+	if err := c.checkDependents("after drop column"); err != nil {
+		t.Columns = columns
+		return err
+	}
+	return nil
+}
+
+// checkDependents resolves the views & trigger bodies of all schemas and
+// returns the first error, reported by SQLite when a table is altered.
+func (c *Catalog) checkDependents(when string) error {
+	for _, s := range c.schemas {
+		for _, v := range s.views {
+			r := newResolver(c)
+			r.selectStmt(v.Select, nil, nil, nil)
+			if len(r.errs) > 0 {
+				return fmt.Errorf("error in view %s %s: %s", v.Name, when, r.errs[0].Msg)
+			}
+		}
+		for _, trig := range s.triggers {
+			r := newResolver(c)
+			r.triggerStmt(s, trig)
+			if len(r.errs) > 0 {
+				return fmt.Errorf("error in trigger %s %s: %s", trig.Name, when, r.errs[0].Msg)
+			}
+		}
+	}
+	return nil
+}
+
+// foreignKeys returns the foreign key constraints of t, both column and table
+// constraints.
+func (t *Table) foreignKeys() []*sql.ForeignKeyConstraint {
+	var fks []*sql.ForeignKeyConstraint
+	for _, col := range t.Columns {
+		for _, cons := range col.Constraints {
+			if fk, ok := cons.(*sql.ForeignKeyConstraint); ok {
+				fks = append(fks, fk)
+			}
+		}
+	}
+	for _, cons := range t.Constraints {
+		if fk, ok := cons.(*sql.ForeignKeyConstraint); ok {
+			fks = append(fks, fk)
+		}
+	}
+	return fks
+}
+
+func (c *Catalog) dropTable(stmt *sql.DropTableStatement) error {
+	schemas, err := c.lookupSchemas(stmt.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range schemas {
+		if t := s.Table(stmt.Name.Name.Name); t != nil {
+			if checkReserved(t.Name) != nil {
+				return fmt.Errorf("table %s may not be dropped", t.Name)
+			}
+			s.tables = slices.DeleteFunc(s.tables, func(other *Table) bool { return other == t })
+			s.indexes = slices.DeleteFunc(s.indexes, func(idx *Index) bool { return equalName(idx.Table, t.Name) })
+			c.dropTriggersOn(s, t.Name)
+			return nil
+		} else if v := s.View(stmt.Name.Name.Name); v != nil {
+			return fmt.Errorf("use DROP VIEW to delete view %s", v.Name)
+		}
+	}
+	if stmt.IfExists {
+		return nil
+	}
+	return fmt.Errorf("no such table: %s", displayName(stmt.Name))
+}
+
+func (c *Catalog) dropView(stmt *sql.DropViewStatement) error {
+	schemas, err := c.lookupSchemas(stmt.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range schemas {
+		if v := s.View(stmt.Name.Name.Name); v != nil {
+			s.views = slices.DeleteFunc(s.views, func(other *View) bool { return other == v })
+			c.dropTriggersOn(s, v.Name)
+			return nil
+		} else if t := s.Table(stmt.Name.Name.Name); t != nil {
+			return fmt.Errorf("use DROP TABLE to delete table %s", t.Name)
+		}
+	}
+	if stmt.IfExists {
+		return nil
+	}
+	return fmt.Errorf("no such view: %s", displayName(stmt.Name))
+}
+
+// dropTriggersOn drops the triggers attached to the dropped table or view
+// named name of s, including temporary triggers.
+func (c *Catalog) dropTriggersOn(s *Schema, name string) {
+	on := func(trig *Trigger) bool { return equalName(trig.Table, name) }
+	s.triggers = slices.DeleteFunc(s.triggers, on)
+
+	// Temporary triggers are attached to the first table or view of that
+	// name in search order.
+	for _, other := range c.searchOrder() {
+		if other == s {
+			c.schemas[1].triggers = slices.DeleteFunc(c.schemas[1].triggers, on)
+			return
+		} else if other.Table(name) != nil || other.View(name) != nil {
+			return
+		}
+	}
+}
+
+func (c *Catalog) dropIndex(stmt *sql.DropIndexStatement) error {
+	schemas, err := c.lookupSchemas(stmt.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range schemas {
+		if idx := s.Index(stmt.Name.Name.Name); idx != nil {
+			s.indexes = slices.DeleteFunc(s.indexes, func(other *Index) bool { return other == idx })
+			return nil
+		}
+	}
+	if stmt.IfExists {
+		return nil
+	}
+	return fmt.Errorf("no such index: %s", displayName(stmt.Name))
+}
+
+func (c *Catalog) dropTrigger(stmt *sql.DropTriggerStatement) error {
+	schemas, err := c.lookupSchemas(stmt.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range schemas {
+		if trig := s.Trigger(stmt.Name.Name.Name); trig != nil {
+			s.triggers = slices.DeleteFunc(s.triggers, func(other *Trigger) bool { return other == trig })
+			return nil
+		}
+	}
+	if stmt.IfExists {
+		return nil
+	}
+	return fmt.Errorf("no such trigger: %s", displayName(stmt.Name))
+}
+
+func (c *Catalog) attach(stmt *sql.AttachStatement) error {
+	if stmt.Schema == nil {
+		return nil
+	}
+	if s := c.Schema(stmt.Schema.Name); s != nil {
+		return fmt.Errorf("database %s is already in use", s.Name)
+	}
+	c.schemas = append(c.schemas, &Schema{Name: stmt.Schema.Name})
+	return nil
+}
+
+func (c *Catalog) detach(stmt *sql.DetachStatement) error {
+	s := c.Schema(stmt.Schema.Name)
+	if s == nil {
+		return fmt.Errorf("no such database: %s", stmt.Schema.Name)
+	} else if s == c.schemas[0] || s == c.schemas[1] {
+		return fmt.Errorf("cannot detach database %s", s.Name)
+	}
+	c.schemas = slices.DeleteFunc(c.schemas, func(other *Schema) bool { return other == s })
+	return nil
+}
+
+// exprInterface is the type of expression fields.
+var exprInterface = reflect.TypeFor[sql.Expr]()
+
+// columnRefs calls fn for each identifier within n used as an expression,
+// i.e. each unqualified column reference.
+func columnRefs(n sql.Node, fn func(*sql.Ident)) {
+	if n == nil || reflect.ValueOf(n).IsNil() {
+		return
+	}
+	if ident, ok := n.(*sql.Ident); ok {
+		fn(ident)
+		return
+	}
+
+	sql.Apply(n, func(c *sql.Cursor) bool {
+		if ident, ok := c.Node().(*sql.Ident); ok && c.Depth() > 0 && isExprField(c) {
+			fn(ident)
+		}
+		return true
+	}, nil)
+}
+
+// isExprField reports whether the node at c is held by an expression field of
+// its parent. The collation name of a COLLATE expression is not.
+func isExprField(c *sql.Cursor) bool {
+	if expr, ok := c.Parent().(*sql.BinaryExpr); ok && expr.Op == sql.OP_COLLATE && c.Name() == "Y" {
+		return false
+	}
+	typ := reflect.ValueOf(c.Parent()).Elem().FieldByName(c.Name()).Type()
+	return typ == exprInterface || typ == reflect.SliceOf(exprInterface)
+}
+
+// find returns the element of a whose name is equal to name, or nil.
+func find[T any](a []*T, nameOf func(*T) string, name string) *T {
+	for _, v := range a {
+		if equalName(nameOf(v), name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// equalName reports whether a and b are equal names. Like SQLite, only ASCII
+// letters are compared case-insensitively.
+func equalName(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if lower(a[i]) != lower(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func lower(ch byte) byte {
+	if ch >= 'A' && ch <= 'Z' {
+		return ch + 'a' - 'A'
+	}
+	return ch
+}
@@ -0,0 +1,448 @@
+package catalog_test
+
+import (
+	"testing"
+
+	"github.com/TcMits/sql"
+	"github.com/TcMits/sql/catalog"
+	"github.com/go-test/deep"
+)
+
+// MustExec returns a catalog built from the statements in s. Fail on error.
+func MustExec(tb testing.TB, s string) *catalog.Catalog {
+	tb.Helper()
+	c := catalog.New()
+	if err := c.ExecString(s); err != nil {
+		tb.Fatal(err)
+	}
+	return c
+}
+
+// AssertExecError asserts that executing s on a new catalog fails with want.
+func AssertExecError(tb testing.TB, s string, want string) {
+	tb.Helper()
+	err := catalog.New().ExecString(s)
+	if err == nil || err.Error() != want {
+		tb.Fatalf("ExecString(%q)=%v, want %q", s, err, want)
+	}
+}
+
+func columnNames(t *catalog.Table) []string {
+	var names []string
+	for _, col := range t.Columns {
+		names = append(names, col.Name)
+	}
+	return names
+}
+
+func TestCatalog_CreateTable(t *testing.T) {
+	c := MustExec(t, `
+		CREATE TABLE users (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name VARCHAR(50) NOT NULL COLLATE NOCASE,
+			email TEXT UNIQUE,
+			score REAL DEFAULT 0,
+			total AS (score * 2) STORED
+		);
+		CREATE TABLE IF NOT EXISTS Users (x);
+		CREATE TABLE pairs (a, b, PRIMARY KEY (a, b)) WITHOUT ROWID;
+		INSERT INTO users (name) VALUES ('x');
+	`)
+
+	users := c.Table("USERS")
+	if users == nil {
+		t.Fatal("expected table")
+	}
+	if diff := deep.Equal(columnNames(users), []string{"id", "name", "email", "score", "total"}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	id, name, email, score, total := users.Columns[0], users.Columns[1], users.Columns[2], users.Columns[3], users.Columns[4]
+	if !id.PrimaryKey || !id.Autoincrement || id.Affinity() != catalog.INTEGER {
+		t.Errorf("unexpected id column: %+v", id)
+	}
+	if name.Type != "VARCHAR(50)" || !name.NotNull || name.Collation != "NOCASE" || name.Affinity() != catalog.TEXT {
+		t.Errorf("unexpected name column: %+v", name)
+	}
+	if !email.Unique {
+		t.Errorf("unexpected email column: %+v", email)
+	}
+	if score.Default == nil || score.Default.String() != "0" || score.Affinity() != catalog.REAL {
+		t.Errorf("unexpected score column: %+v", score)
+	}
+	if total.Generated == nil || !total.Stored || total.Affinity() != catalog.BLOB {
+		t.Errorf("unexpected total column: %+v", total)
+	}
+	if diff := deep.Equal(users.PrimaryKey(), []string{"id"}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	pairs := c.Schema("main").Table("pairs")
+	if !pairs.WithoutRowID {
+		t.Fatal("expected WITHOUT ROWID")
+	}
+	if diff := deep.Equal(pairs.PrimaryKey(), []string{"a", "b"}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	t.Run("Temp", func(t *testing.T) {
+		c := MustExec(t, `CREATE TABLE t (a); CREATE TEMP TABLE t (b)`)
+		if diff := deep.Equal(columnNames(c.Table("t")), []string{"b"}); diff != nil {
+			t.Fatal(diff)
+		}
+		if diff := deep.Equal(columnNames(c.Schema("main").Table("t")), []string{"a"}); diff != nil {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("AsSelect", func(t *testing.T) {
+		c := MustExec(t, `
+			CREATE TABLE a (id INTEGER, name VARCHAR(10), price DECIMAL);
+			CREATE TABLE b AS SELECT a.*, name AS label, CAST(price AS REAL) AS p, id + 1 FROM a;
+			CREATE TABLE c AS WITH x (k) AS (SELECT id FROM a) SELECT * FROM x, (SELECT 1 AS id) AS y;
+			CREATE TABLE d AS SELECT id+1, upper( name ), "id" FROM a;
+			CREATE TABLE e AS SELECT * FROM (SELECT price*2 FROM a);
+		`)
+
+		var got []string
+		for _, col := range c.Table("b").Columns {
+			got = append(got, col.Name+" "+col.Type)
+		}
+		if diff := deep.Equal(got, []string{"id INT", "name TEXT", "price NUM", "label TEXT", "p REAL", "id + 1 "}); diff != nil {
+			t.Fatal(diff)
+		}
+		if diff := deep.Equal(columnNames(c.Table("c")), []string{"k", "id"}); diff != nil {
+			t.Fatal(diff)
+		}
+
+		// Like SQLite, expressions are named after their source text.
+		if diff := deep.Equal(columnNames(c.Table("d")), []string{"id+1", "upper( name )", "id"}); diff != nil {
+			t.Fatal(diff)
+		}
+		if diff := deep.Equal(columnNames(c.Table("e")), []string{"price*2"}); diff != nil {
+			t.Fatal(diff)
+		}
+
+		// Without the source text, Exec falls back to the expression string.
+		stmt, err := sql.ParseStmtString(`CREATE TABLE f AS SELECT id+1 FROM a`)
+		if err != nil {
+			t.Fatal(err)
+		} else if err := c.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+		if diff := deep.Equal(columnNames(c.Table("f")), []string{`"id" + 1`}); diff != nil {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		MustExec(t, `CREATE TABLE t (a INT, b ANY) STRICT`)
+		AssertExecError(t, `CREATE TABLE t (a INT, b) STRICT`, `missing datatype for t.b`)
+		AssertExecError(t, `CREATE TABLE t (a VARCHAR(10)) STRICT`, `unknown datatype for t.a: "VARCHAR(10)"`)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		AssertExecError(t, `CREATE TABLE t (a); CREATE TABLE T (b)`, `table t already exists`)
+		AssertExecError(t, `CREATE TABLE t (a); CREATE INDEX i ON t (a); CREATE TABLE i (b)`, `there is already an index named i`)
+		AssertExecError(t, `CREATE TABLE t (a); CREATE INDEX i ON t (a); CREATE TABLE IF NOT EXISTS i (b)`, `there is already an index named i`)
+		AssertExecError(t, `CREATE TABLE t (a, A)`, `duplicate column name: A`)
+		AssertExecError(t, `CREATE TABLE t (a PRIMARY KEY, b PRIMARY KEY)`, `table "t" has more than one primary key`)
+		AssertExecError(t, `CREATE TABLE t (a, PRIMARY KEY (b))`, `no such column: b`)
+		AssertExecError(t, `CREATE TABLE t (a) WITHOUT ROWID`, `PRIMARY KEY missing on table t`)
+		AssertExecError(t, `CREATE TABLE t (a INT PRIMARY KEY AUTOINCREMENT)`, `AUTOINCREMENT is only allowed on an INTEGER PRIMARY KEY`)
+		AssertExecError(t, `CREATE TABLE t (a, FOREIGN KEY (b) REFERENCES u)`, `unknown column "b" in foreign key definition`)
+		AssertExecError(t, `CREATE TABLE sqlite_foo (a)`, `object name reserved for internal use: sqlite_foo`)
+		AssertExecError(t, `CREATE TABLE other.t (a)`, `unknown database other`)
+		AssertExecError(t, `CREATE TABLE t AS SELECT * FROM u`, `no such table: u`)
+	})
+}
+
+func TestCatalog_CreateIndex(t *testing.T) {
+	c := MustExec(t, `
+		CREATE TABLE t (a, b);
+		CREATE UNIQUE INDEX i ON t (a, lower(b) DESC) WHERE b IS NOT NULL;
+		CREATE INDEX IF NOT EXISTS I ON t (b);
+	`)
+	idx := c.Index("i")
+	if idx == nil || idx.Table != "t" || !idx.Unique || len(idx.Columns) != 2 || idx.WhereExpr == nil {
+		t.Fatalf("unexpected index: %+v", idx)
+	}
+
+	AssertExecError(t, `CREATE TABLE t (a); CREATE INDEX i ON t (a); CREATE INDEX i ON t (a)`, `index i already exists`)
+	AssertExecError(t, `CREATE TABLE t (a); CREATE INDEX t ON t (a)`, `there is already a table named t`)
+	AssertExecError(t, `CREATE INDEX i ON t (a)`, `no such table: main.t`)
+	AssertExecError(t, `CREATE TABLE t (a); CREATE INDEX i ON t (b)`, `no such column: b`)
+	AssertExecError(t, `CREATE TABLE t (a); CREATE INDEX i ON t (a) WHERE b > 0`, `no such column: b`)
+	AssertExecError(t, `CREATE VIEW v AS SELECT 1; CREATE INDEX i ON v (a)`, `views may not be indexed`)
+	AssertExecError(t, `CREATE VIRTUAL TABLE v USING fts5(a); CREATE INDEX i ON v (a)`, `virtual tables may not be indexed`)
+}
+
+func TestCatalog_CreateViewAndTrigger(t *testing.T) {
+	c := MustExec(t, `
+		CREATE TABLE t (a, b);
+		CREATE VIEW v (x, y) AS SELECT a, b FROM t;
+		CREATE TRIGGER tr AFTER UPDATE OF a ON t BEGIN DELETE FROM t; END;
+		CREATE TRIGGER vtr INSTEAD OF INSERT ON v BEGIN SELECT 1; END;
+		CREATE VIRTUAL TABLE docs USING fts5(title, body);
+	`)
+
+	if v := c.View("V"); v == nil || v.Select == nil {
+		t.Fatal("expected view")
+	} else if diff := deep.Equal(v.Columns, []string{"x", "y"}); diff != nil {
+		t.Fatal(diff)
+	}
+	if trig := c.Trigger("tr"); trig == nil || trig.Table != "t" || trig.Stmt == nil {
+		t.Fatalf("unexpected trigger: %+v", trig)
+	}
+	if docs := c.Table("docs"); docs == nil || !docs.Virtual || docs.Module != "fts5" {
+		t.Fatalf("unexpected virtual table: %+v", docs)
+	} else if diff := deep.Equal(docs.Args, []string{`"title"`, `"body"`}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	AssertExecError(t, `CREATE TABLE t (a); CREATE VIEW t AS SELECT 1`, `table t already exists`)
+	AssertExecError(t, `CREATE VIEW v AS SELECT 1; CREATE VIEW v AS SELECT 2`, `view v already exists`)
+	AssertExecError(t, `CREATE TRIGGER tr AFTER INSERT ON t BEGIN SELECT 1; END`, `no such table: main.t`)
+	AssertExecError(t, `CREATE TABLE t (a); CREATE TRIGGER tr INSTEAD OF INSERT ON t BEGIN SELECT 1; END`, `cannot create INSTEAD OF trigger on table: t`)
+	AssertExecError(t, `CREATE VIEW v AS SELECT 1; CREATE TRIGGER tr BEFORE INSERT ON v BEGIN SELECT 1; END`, `cannot create BEFORE trigger on view: v`)
+	AssertExecError(t, `CREATE TABLE t (a); CREATE TRIGGER tr AFTER INSERT ON t BEGIN SELECT 1; END; CREATE TRIGGER tr AFTER DELETE ON t BEGIN SELECT 1; END`, `trigger tr already exists`)
+}
+
+// Ensure unqualified indexes & triggers on temporary tables are created in
+// the temp schema.
+func TestCatalog_TempTable(t *testing.T) {
+	c := MustExec(t, `
+		CREATE TEMP TABLE tt (a);
+		CREATE TABLE t (a);
+		CREATE INDEX i1 ON tt (a);
+		CREATE INDEX i2 ON t (a);
+		CREATE TRIGGER tr1 AFTER INSERT ON tt BEGIN SELECT 1; END;
+		CREATE TRIGGER tr2 AFTER INSERT ON t BEGIN SELECT 1; END;
+	`)
+	temp, main := c.Schema("temp"), c.Schema("main")
+	if temp.Index("i1") == nil || main.Index("i2") == nil {
+		t.Fatal("unexpected index schemas")
+	} else if temp.Trigger("tr1") == nil || main.Trigger("tr2") == nil {
+		t.Fatal("unexpected trigger schemas")
+	}
+
+	AssertExecError(t, `CREATE TEMP TABLE tt (a); CREATE INDEX main.i ON tt (a)`, `no such table: main.tt`)
+	AssertExecError(t, `CREATE TEMP TABLE tt (a); CREATE TRIGGER main.tr AFTER INSERT ON tt BEGIN SELECT 1; END`, `no such table: main.tt`)
+}
+
+func TestCatalog_AlterTable(t *testing.T) {
+	c := MustExec(t, `
+		CREATE TABLE p (id INTEGER PRIMARY KEY);
+		CREATE TABLE t (a, b REFERENCES p (id), CHECK (a > 0));
+		CREATE INDEX i ON t (a);
+		CREATE TRIGGER tr AFTER UPDATE OF a ON t BEGIN SELECT 1; END;
+		ALTER TABLE t RENAME COLUMN a TO x;
+		ALTER TABLE p RENAME COLUMN id TO pid;
+		ALTER TABLE p RENAME TO parent;
+		ALTER TABLE t ADD COLUMN c TEXT NOT NULL DEFAULT '';
+		ALTER TABLE t DROP COLUMN c;
+		ALTER TABLE t RENAME TO u;
+	`)
+
+	if c.Table("t") != nil || c.Table("p") != nil {
+		t.Fatal("expected tables to be renamed")
+	}
+	u := c.Table("u")
+	if diff := deep.Equal(columnNames(u), []string{"x", "b"}); diff != nil {
+		t.Fatal(diff)
+	}
+	if got, want := u.Constraints[0].String(), `CHECK ("x" > 0)`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := u.Column("b").Constraints[0].String(), `REFERENCES "parent" ("pid")`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if idx := c.Index("i"); idx.Table != "u" || idx.Columns[0].String() != `"x"` {
+		t.Fatalf("unexpected index: %s %s", idx.Table, idx.Columns[0])
+	}
+	if trig := c.Trigger("tr"); trig.Table != "u" || trig.Stmt.String() != `CREATE TRIGGER "tr" AFTER UPDATE OF "x" ON "u" BEGIN SELECT 1; END` {
+		t.Fatalf("unexpected trigger: %s", trig.Stmt)
+	}
+
+	AssertExecError(t, `ALTER TABLE t ADD COLUMN a`, `no such table: t`)
+	AssertExecError(t, `CREATE TABLE t (a); CREATE TABLE u (a); ALTER TABLE t RENAME TO U`, `there is already another table or index with this name: U`)
+	AssertExecError(t, `CREATE TABLE t (a, b); ALTER TABLE t RENAME COLUMN a TO b`, `duplicate column name: b`)
+	AssertExecError(t, `CREATE TABLE t (a); ALTER TABLE t RENAME COLUMN x TO y`, `no such column: "x"`)
+	AssertExecError(t, `CREATE TABLE t (a); ALTER TABLE t ADD COLUMN A`, `duplicate column name: A`)
+	AssertExecError(t, `CREATE TABLE t (a); ALTER TABLE t ADD COLUMN b PRIMARY KEY`, `Cannot add a PRIMARY KEY column`)
+	AssertExecError(t, `CREATE TABLE t (a); ALTER TABLE t ADD COLUMN b UNIQUE`, `Cannot add a UNIQUE column`)
+	AssertExecError(t, `CREATE TABLE t (a); ALTER TABLE t ADD COLUMN b NOT NULL`, `Cannot add a NOT NULL column with default value NULL`)
+	AssertExecError(t, `CREATE TABLE t (a, b); ALTER TABLE t DROP COLUMN c`, `no such column: "c"`)
+	AssertExecError(t, `CREATE TABLE t (a PRIMARY KEY, b); ALTER TABLE t DROP COLUMN a`, `cannot drop PRIMARY KEY column: "a"`)
+	AssertExecError(t, `CREATE TABLE t (a); ALTER TABLE t DROP COLUMN a`, `cannot drop column "a": no other columns exist`)
+	AssertExecError(t, `CREATE TABLE t (a, b); CREATE INDEX i ON t (b); ALTER TABLE t DROP COLUMN b`, `error in index i after drop column: no such column: b`)
+	if c := MustExec(t, `CREATE TABLE t (a, b CHECK (b > 0), UNIQUE (a)); CREATE VIEW v AS SELECT * FROM t; ALTER TABLE t DROP COLUMN b`); len(c.Table("t").Columns) != 1 {
+		t.Fatal("expected column to be dropped")
+	}
+	if c := MustExec(t, `CREATE TABLE t (a, b); CREATE VIEW v AS SELECT b FROM t`); c.ExecString(`ALTER TABLE t DROP COLUMN b`) == nil {
+		t.Fatal("expected error")
+	} else if diff := deep.Equal(columnNames(c.Table("t")), []string{"a", "b"}); diff != nil {
+		t.Fatal(diff)
+	}
+	AssertExecError(t, `CREATE TABLE t (a, b, c, UNIQUE (a, b)); ALTER TABLE t DROP COLUMN b`, `cannot drop UNIQUE column: "b"`)
+	AssertExecError(t, `CREATE TABLE t (a, b); CREATE VIEW v AS SELECT b FROM t; ALTER TABLE t DROP COLUMN b`, `error in view v after drop column: no such column: b`)
+	AssertExecError(t, `CREATE TABLE t (a, b); CREATE TRIGGER tr AFTER INSERT ON t BEGIN SELECT b FROM t; END; ALTER TABLE t DROP COLUMN b`, `error in trigger tr after drop column: no such column: b`)
+	AssertExecError(t, `CREATE TABLE t (a, b); CREATE TRIGGER tr AFTER INSERT ON t BEGIN SELECT new.b; END; ALTER TABLE t DROP COLUMN b`, `error in trigger tr after drop column: no such column: new.b`)
+	AssertExecError(t, `CREATE TABLE t (a, b, CHECK (b > a)); ALTER TABLE t DROP COLUMN b`, `error in table t after drop column: no such column: b`)
+	AssertExecError(t, `CREATE TABLE t (a CHECK (a > b), b); ALTER TABLE t DROP COLUMN b`, `error in table t after drop column: no such column: b`)
+	AssertExecError(t, `CREATE VIEW v AS SELECT 1; ALTER TABLE v RENAME TO w`, `Cannot alter view v`)
+}
+
+// Ensure renaming a table or column updates the views & trigger bodies
+// referring to it.
+func TestCatalog_AlterTable_Rewrite(t *testing.T) {
+	c := MustExec(t, `
+		CREATE TABLE t (a, b);
+		CREATE TABLE log (a);
+		CREATE VIEW v AS SELECT a, t.b FROM t WHERE a IN (SELECT a FROM log);
+		CREATE VIEW w AS SELECT x.a FROM t AS x, (SELECT 1 AS a) AS t;
+		CREATE TRIGGER tr AFTER UPDATE ON t WHEN new.a > 0 BEGIN
+			INSERT INTO log (a) SELECT a FROM t WHERE t.b = old.b;
+			UPDATE t SET a = new.a;
+		END;
+		ALTER TABLE t RENAME COLUMN a TO x;
+		ALTER TABLE log RENAME COLUMN a TO y;
+		ALTER TABLE t RENAME TO u;
+	`)
+
+	if got, want := c.View("v").Select.String(), `SELECT "x", "u"."b" FROM "u" WHERE "x" IN (SELECT "y" FROM "log")`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := c.View("w").Select.String(), `SELECT "x"."x" FROM "u" AS "x", (SELECT 1 AS "a") AS "t"`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	want := `CREATE TRIGGER "tr" AFTER UPDATE ON "u" WHEN "new"."x" > 0 BEGIN ` +
+		`INSERT INTO "log" ("y") SELECT "x" FROM "u" WHERE "u"."b" = "old"."b"; ` +
+		`UPDATE "u" SET "x" = "new"."x"; END`
+	if got := c.Trigger("tr").Stmt.String(); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestCatalog_Drop(t *testing.T) {
+	c := MustExec(t, `
+		CREATE TABLE t (a);
+		CREATE INDEX i ON t (a);
+		CREATE TRIGGER tr AFTER INSERT ON t BEGIN SELECT 1; END;
+		CREATE VIEW v AS SELECT a FROM t;
+		CREATE TABLE keep (a);
+		DROP TABLE t;
+		DROP VIEW IF EXISTS v;
+		DROP VIEW IF EXISTS v;
+		DROP INDEX IF EXISTS i;
+		DROP TRIGGER IF EXISTS tr;
+	`)
+	if c.Table("t") != nil || c.Index("i") != nil || c.Trigger("tr") != nil || c.View("v") != nil {
+		t.Fatal("expected objects to be dropped")
+	}
+	if tables := c.Schema("main").Tables(); len(tables) != 1 || tables[0].Name != "keep" {
+		t.Fatalf("unexpected tables: %v", tables)
+	}
+
+	AssertExecError(t, `DROP TABLE t`, `no such table: t`)
+	AssertExecError(t, `DROP TABLE main.t`, `no such table: main.t`)
+	AssertExecError(t, `DROP INDEX i`, `no such index: i`)
+	AssertExecError(t, `DROP VIEW v`, `no such view: v`)
+	AssertExecError(t, `DROP TRIGGER tr`, `no such trigger: tr`)
+	AssertExecError(t, `CREATE TABLE t (a); DROP VIEW t`, `use DROP TABLE to delete table t`)
+	AssertExecError(t, `CREATE VIEW v AS SELECT 1; DROP TABLE v`, `use DROP VIEW to delete view v`)
+	AssertExecError(t, `DROP TABLE other.t`, `no such database: other`)
+}
+
+// Ensure temporary triggers are dropped with the table they are attached to.
+func TestCatalog_DropTempTrigger(t *testing.T) {
+	c := MustExec(t, `
+		CREATE TABLE t (a);
+		CREATE TEMP TRIGGER tr AFTER INSERT ON t BEGIN SELECT 1; END;
+		DROP TABLE t;
+	`)
+	if c.Trigger("tr") != nil {
+		t.Fatal("expected trigger to be dropped")
+	}
+
+	// A temporary trigger on a temporary table is kept when dropping the
+	// table of the same name in main.
+	c = MustExec(t, `
+		CREATE TABLE t (a);
+		CREATE TEMP TABLE t (b);
+		CREATE TEMP TRIGGER tr AFTER INSERT ON t BEGIN SELECT 1; END;
+		DROP TABLE main.t;
+	`)
+	if c.Trigger("tr") == nil {
+		t.Fatal("expected trigger to be kept")
+	}
+	if err := c.ExecString(`DROP TABLE t`); err != nil {
+		t.Fatal(err)
+	} else if c.Trigger("tr") != nil {
+		t.Fatal("expected trigger to be dropped")
+	}
+}
+
+func TestCatalog_Attach(t *testing.T) {
+	c := MustExec(t, `
+		ATTACH DATABASE 'other.db' AS other;
+		CREATE TABLE other.t (a);
+		CREATE TABLE t (b);
+	`)
+	if diff := deep.Equal(columnNames(c.Schema("OTHER").Table("t")), []string{"a"}); diff != nil {
+		t.Fatal(diff)
+	}
+	if diff := deep.Equal(columnNames(c.Table("t")), []string{"b"}); diff != nil {
+		t.Fatal(diff)
+	}
+	if err := c.ExecString(`DETACH other`); err != nil || c.Schema("other") != nil {
+		t.Fatalf("unexpected detach: %v", err)
+	}
+
+	AssertExecError(t, `ATTACH 'a.db' AS a; ATTACH 'b.db' AS a`, `database a is already in use`)
+	AssertExecError(t, `DETACH main`, `cannot detach database main`)
+}
+
+func TestCatalog_Error(t *testing.T) {
+	c := MustExec(t, `CREATE TABLE t (a)`)
+	err := c.ExecString(`CREATE INDEX i ON t (a); CREATE TABLE t (b)`)
+	catErr, ok := err.(*catalog.Error)
+	if !ok {
+		t.Fatalf("unexpected error: %#v", err)
+	} else if got, want := catErr.Stmt.String(), `CREATE TABLE "t" ("b")`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	// The catalog is left unchanged by a failing statement.
+	if err := c.ExecString(`CREATE TABLE u (a, a)`); err == nil || c.Table("u") != nil {
+		t.Fatal("expected table not to be created")
+	}
+}
+
+func TestTypeAffinity(t *testing.T) {
+	for typ, want := range map[string]catalog.Affinity{
+		"INT":               catalog.INTEGER,
+		"BIGINT":            catalog.INTEGER,
+		"VARCHAR(255)":      catalog.TEXT,
+		"NCHAR(55)":         catalog.TEXT,
+		"CLOB":              catalog.TEXT,
+		"BLOB":              catalog.BLOB,
+		"":                  catalog.BLOB,
+		"REAL":              catalog.REAL,
+		"DOUBLE PRECISION":  catalog.REAL,
+		"FLOAT":             catalog.REAL,
+		"NUMERIC":           catalog.NUMERIC,
+		"DECIMAL(10,5)":     catalog.NUMERIC,
+		"BOOLEAN":           catalog.NUMERIC,
+		"DATETIME":          catalog.NUMERIC,
+		"FLOATING POINT":    catalog.INTEGER,
+		"CHARINT":           catalog.INTEGER,
+		"STRING":            catalog.NUMERIC,
+		"unsigned big int":  catalog.INTEGER,
+		"varying character": catalog.TEXT,
+	} {
+		if got := catalog.TypeAffinity(typ); got != want {
+			t.Errorf("TypeAffinity(%q)=%s, want %s", typ, got, want)
+		}
+	}
+}
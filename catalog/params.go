@@ -0,0 +1,360 @@
+package catalog
+
+import (
+	"github.com/TcMits/sql"
+)
+
+// Param is a bind parameter of a statement with the affinity of the values it
+// is expected to be bound to.
+type Param struct {
+	*sql.Param
+	Affinity Affinity // expected affinity, BLOB if unknown
+	Column   *Column  // column the parameter is compared with or stored in, or nil
+}
+
+// Params returns the bind parameters of stmt, numbered as by sql.Params, and
+// infers the affinity of each from the first occurrence whose context
+// determines one:
+//
+//   - compared with a column: "col = ?", "col IN (?)", "? BETWEEN col AND 10"
+//   - stored in a column: "SET col = ?", "INSERT INTO t (col) VALUES (?)"
+//   - converted by CAST: the affinity of the type
+//   - LIMIT & OFFSET, operands of bitwise operators: INTEGER
+//   - operands of arithmetic operators: NUMERIC
+//   - operands of "||", LIKE, GLOB, REGEXP & MATCH: TEXT
+//
+// Column references are resolved as by Resolve. Parameters out of range and
+// unknown or ambiguous names are returned as a sql.ErrorList, along with the
+// parameters.
+func (c *Catalog) Params(stmt sql.Statement) ([]*Param, error) {
+	for {
+		explain, ok := stmt.(*sql.ExplainStatement)
+		if !ok {
+			break
+		}
+		stmt = explain.Stmt
+	}
+
+	params, err := sql.Params(stmt)
+	errs, _ := err.(sql.ErrorList)
+
+	r := newResolver(c)
+	inf := inferrer{catalog: c, res: r.res, parents: make(map[sql.Node]parent)}
+	switch stmt := stmt.(type) {
+	case *sql.SelectStatement:
+		r.selectStmt(stmt, nil, nil, nil)
+	case *sql.InsertStatement:
+		r.insertStmt(stmt, nil)
+		inf.insert(stmt)
+	case *sql.UpdateStatement:
+		r.updateStmt(stmt, nil)
+		inf.target = r.res.Sources[stmt.Table].Table
+	case *sql.DeleteStatement:
+		r.deleteStmt(stmt, nil)
+	}
+	errs = append(errs, r.errs...)
+
+	sql.Apply(stmt, func(c *sql.Cursor) bool {
+		inf.parents[c.Node()] = parent{node: c.Parent(), name: c.Name(), index: c.Index()}
+		return true
+	}, nil)
+
+	a := make([]*Param, len(params))
+	for i, p := range params {
+		a[i] = &Param{Param: p}
+		for _, expr := range p.Exprs {
+			if aff, col := inf.infer(expr); aff != BLOB || col != nil {
+				a[i].Affinity, a[i].Column = aff, col
+				break
+			}
+		}
+	}
+	return a, errs.Err()
+}
+
+// parent is the parent of a node and the field of the parent holding it.
+type parent struct {
+	node  sql.Node
+	name  string
+	index int
+}
+
+// inferrer infers the affinity of bind parameters from their context.
+type inferrer struct {
+	catalog *Catalog
+	res     *Resolution
+	parents map[sql.Node]parent
+
+	target  *Table                        // table of an INSERT or UPDATE statement
+	columns []*Column                     // columns inserted by an INSERT statement
+	cores   map[*sql.SelectStatement]bool // SELECT cores inserted by an INSERT statement
+}
+
+// insert sets the target table & inserted columns of stmt.
+func (inf *inferrer) insert(stmt *sql.InsertStatement) {
+	src := inf.res.Sources[stmt.Table]
+	if inf.target = src.Table; inf.target == nil {
+		return
+	}
+
+	if len(stmt.Columns) > 0 {
+		for _, ident := range stmt.Columns {
+			inf.columns = append(inf.columns, inf.target.Column(ident.Name))
+		}
+	} else {
+		// Generated columns cannot be inserted.
+		for _, col := range inf.target.Columns {
+			if col.Generated == nil {
+				inf.columns = append(inf.columns, col)
+			}
+		}
+	}
+
+	inf.cores = make(map[*sql.SelectStatement]bool)
+	for core := stmt.Select; core != nil; core = core.Compound {
+		inf.cores[core] = true
+	}
+}
+
+// infer returns the affinity and column expected of the bind parameter expr.
+func (inf *inferrer) infer(expr *sql.BindExpr) (Affinity, *Column) {
+	// Parentheses & collations do not change the affinity.
+	var n sql.Node = expr
+	p := inf.parents[n]
+	for isTransparent(p.node) {
+		n, p = p.node, inf.parents[p.node]
+	}
+
+	switch parent := p.node.(type) {
+	case *sql.BinaryExpr:
+		return inf.binary(parent, n)
+	case *sql.UnaryExpr:
+		switch parent.Op {
+		case sql.OP_PLUS, sql.OP_MINUS:
+			return NUMERIC, nil
+		case sql.OP_BITNOT:
+			return INTEGER, nil
+		}
+	case *sql.CastExpr:
+		return typeAffinity(parent.Type), nil
+	case *sql.InExpr:
+		if parent.Values != nil && len(parent.Values.Exprs) > 0 {
+			return inf.expr(parent.Values.Exprs[0])
+		} else if parent.Select != nil && len(parent.Select.Columns) > 0 {
+			return inf.expr(parent.Select.Columns[0].Expr)
+		}
+	case *sql.ExprList:
+		return inf.list(parent, p.index)
+	case *sql.Assignment:
+		if len(parent.Columns) == 1 {
+			return inf.column(parent.Columns[0])
+		}
+	case *sql.ResultColumn:
+		if sel, ok := inf.parents[parent].node.(*sql.SelectStatement); ok && inf.cores[sel] {
+			return inf.inserted(inf.position(sel, inf.parents[parent].index))
+		}
+	case *sql.SelectStatement, *sql.UpdateStatement, *sql.DeleteStatement:
+		if p.name == "LimitExpr" || p.name == "OffsetExpr" {
+			return INTEGER, nil
+		}
+	case *sql.CaseExpr:
+		if p.name == "Operand" && len(parent.Blocks) > 0 {
+			return inf.expr(parent.Blocks[0].Condition)
+		}
+	case *sql.CaseBlock:
+		if expr, ok := inf.parents[parent].node.(*sql.CaseExpr); ok && p.name == "Condition" && expr.Operand != nil {
+			return inf.expr(expr.Operand)
+		}
+	}
+	return BLOB, nil
+}
+
+// binary returns the affinity and column expected of the operand n of expr.
+func (inf *inferrer) binary(expr *sql.BinaryExpr, n sql.Node) (Affinity, *Column) {
+	other := expr.X
+	if n == expr.X {
+		other = expr.Y
+	}
+
+	if isComparison(expr.Op) {
+		return inf.expr(other)
+	}
+
+	switch expr.Op {
+	case sql.OP_BETWEEN, sql.OP_NOT_BETWEEN:
+		// The bounds are the operands of an AND expression.
+		if bounds, ok := expr.Y.(*sql.BinaryExpr); ok && n == expr.X {
+			if aff, col := inf.expr(bounds.X); aff != BLOB || col != nil {
+				return aff, col
+			}
+			return inf.expr(bounds.Y)
+		}
+	case sql.OP_AND:
+		if between, ok := inf.parents[expr].node.(*sql.BinaryExpr); ok &&
+			(between.Op == sql.OP_BETWEEN || between.Op == sql.OP_NOT_BETWEEN) && between.Y == sql.Expr(expr) {
+			return inf.expr(between.X)
+		}
+	case sql.OP_PLUS, sql.OP_MINUS, sql.OP_MULTIPLY, sql.OP_DIVIDE, sql.OP_MODULO:
+		return NUMERIC, nil
+	case sql.OP_BITAND, sql.OP_BITOR, sql.OP_LSHIFT, sql.OP_RSHIFT:
+		return INTEGER, nil
+	case sql.OP_CONCAT, sql.OP_LIKE, sql.OP_NOT_LIKE, sql.OP_GLOB, sql.OP_NOT_GLOB,
+		sql.OP_REGEXP, sql.OP_NOT_REGEXP, sql.OP_MATCH, sql.OP_NOT_MATCH:
+		return TEXT, nil
+	}
+	return BLOB, nil
+}
+
+// list returns the affinity and column expected of the i-th expression of
+// list, a row value or a row of VALUES.
+func (inf *inferrer) list(list *sql.ExprList, i int) (Affinity, *Column) {
+	switch parent := inf.parents[list].node.(type) {
+	case *sql.BinaryExpr:
+		// Row values are compared element by element.
+		other := parent.X
+		if parent.X == sql.Expr(list) {
+			other = parent.Y
+		}
+		if other, ok := other.(*sql.ExprList); ok && isComparison(parent.Op) && i < len(other.Exprs) {
+			return inf.expr(other.Exprs[i])
+		}
+	case *sql.InExpr:
+		if parent.Values == list {
+			return inf.expr(parent.X)
+		}
+	case *sql.Assignment:
+		if i < len(parent.Columns) {
+			return inf.column(parent.Columns[i])
+		}
+	case *sql.InsertStatement:
+		return inf.inserted(i)
+	case *sql.SelectStatement:
+		if inf.cores[parent] {
+			return inf.inserted(i)
+		}
+	}
+	return BLOB, nil
+}
+
+// expr returns the affinity of expr and the column it refers to. Like SQLite,
+// only column references and CAST expressions have an affinity.
+func (inf *inferrer) expr(expr sql.Expr) (Affinity, *Column) {
+	switch expr := expr.(type) {
+	case *sql.ParenExpr:
+		return inf.expr(expr.Expr)
+	case *sql.CastExpr:
+		return typeAffinity(expr.Type), nil
+	case *sql.BinaryExpr:
+		if expr.Op == sql.OP_COLLATE {
+			return inf.expr(expr.X)
+		}
+	case *sql.Ident, *sql.QualifiedRef:
+		if b := inf.res.Refs[expr]; b != nil {
+			return inf.binding(b)
+		}
+	}
+	return BLOB, nil
+}
+
+// binding returns the affinity of the column bound by b and the column of its
+// table, if any.
+func (inf *inferrer) binding(b *Binding) (Affinity, *Column) {
+	src := b.Source
+	switch {
+	case b.Result != nil:
+		return inf.expr(b.Result.Expr)
+	case src == nil:
+		return BLOB, nil
+	case src.Table != nil:
+		if col := src.Table.Column(b.Column); col != nil {
+			return col.Affinity(), col
+		} else if src.hasRowID(b.Column) {
+			return INTEGER, nil
+		}
+		return BLOB, nil
+	}
+
+	// Columns of views & subqueries have the affinity of their expression.
+	var sel *sql.SelectStatement
+	switch {
+	case src.View != nil:
+		sel = src.View.Select
+	case src.CTE != nil:
+		sel = src.CTE.Select
+	case src.Select != nil:
+		sel = src.Select
+	default:
+		return BLOB, nil
+	}
+	cols, _ := inf.catalog.resultColumns(sel, nil)
+	for i, name := range src.Columns {
+		if equalName(name, b.Column) && i < len(cols) {
+			return TypeAffinity(cols[i].Type), nil
+		}
+	}
+	return BLOB, nil
+}
+
+// column returns the affinity of the column ident of the target table.
+func (inf *inferrer) column(ident *sql.Ident) (Affinity, *Column) {
+	if inf.target == nil {
+		return BLOB, nil
+	} else if col := inf.target.Column(ident.Name); col != nil {
+		return col.Affinity(), col
+	}
+	return BLOB, nil
+}
+
+// inserted returns the affinity of the i-th column inserted by an INSERT
+// statement.
+func (inf *inferrer) inserted(i int) (Affinity, *Column) {
+	if i < 0 || i >= len(inf.columns) || inf.columns[i] == nil {
+		return BLOB, nil
+	}
+	return inf.columns[i].Affinity(), inf.columns[i]
+}
+
+// position returns the position of the i-th result column of sel in its
+// result, counting the columns "*" & "tbl.*" expand to.
+func (inf *inferrer) position(sel *sql.SelectStatement, i int) int {
+	var pos int
+	for _, rc := range sel.Columns[:i] {
+		if rc.Star {
+			pos += len(inf.res.Stars[rc])
+		} else if ref, ok := rc.Expr.(*sql.QualifiedRef); ok && ref.Star {
+			pos += len(inf.res.Stars[ref])
+		} else {
+			pos++
+		}
+	}
+	return pos
+}
+
+// isTransparent reports whether n has the affinity of its operand.
+func isTransparent(n sql.Node) bool {
+	switch n := n.(type) {
+	case *sql.ParenExpr:
+		return true
+	case *sql.BinaryExpr:
+		return n.Op == sql.OP_COLLATE
+	}
+	return false
+}
+
+// isComparison reports whether op compares its operands.
+func isComparison(op sql.OpType) bool {
+	switch op {
+	case sql.OP_EQ, sql.OP_NE, sql.OP_LT, sql.OP_LE, sql.OP_GT, sql.OP_GE,
+		sql.OP_IS, sql.OP_IS_NOT, sql.OP_IS_DISTINCT_FROM, sql.OP_IS_NOT_DISTINCT_FROM:
+		return true
+	}
+	return false
+}
+
+// typeAffinity returns the affinity of the CAST type typ.
+func typeAffinity(typ *sql.Type) Affinity {
+	if typ == nil || typ.Name == nil {
+		return BLOB
+	}
+	return TypeAffinity(typ.Name.Name)
+}
@@ -0,0 +1,140 @@
+package catalog_test
+
+import (
+	"testing"
+
+	"github.com/TcMits/sql"
+	"github.com/go-test/deep"
+)
+
+const paramsSchema = `
+	CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, score REAL, data BLOB, created, total AS (score * 2));
+	CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER, title VARCHAR(80), body TEXT);
+	CREATE VIEW scores AS SELECT id, CAST(score AS INTEGER) AS score FROM users;
+`
+
+// params returns the parameters of s inferred against the schema, formatted
+// as "name affinity" or "name affinity column".
+func params(tb testing.TB, schema, s string) ([]string, error) {
+	tb.Helper()
+	stmt, err := sql.ParseStmtString(s)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	params, err := MustExec(tb, schema).Params(stmt)
+	var a []string
+	for _, p := range params {
+		str := p.Name + " " + p.Affinity.String()
+		if p.Name == "" {
+			str = "?" + str
+		}
+		if p.Column != nil {
+			str += " " + p.Column.Name
+		}
+		a = append(a, str)
+	}
+	return a, err
+}
+
+func TestCatalog_Params(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want []string
+	}{
+		{`SELECT * FROM users WHERE id = ? AND ? < score`, []string{"? INTEGER id", "? REAL score"}},
+		{`SELECT * FROM users u WHERE u.name LIKE :pattern`, []string{":pattern TEXT"}},
+		{`SELECT * FROM users WHERE name = (?) COLLATE NOCASE`, []string{"? TEXT name"}},
+		{`SELECT * FROM users WHERE name COLLATE NOCASE = ?`, []string{"? TEXT name"}},
+		{`SELECT * FROM users WHERE rowid = ?`, []string{"? INTEGER"}},
+		{`SELECT * FROM users WHERE data = ? OR created = ?`, []string{"? BLOB data", "? BLOB created"}},
+		{`SELECT * FROM users WHERE id IN (?, ?)`, []string{"? INTEGER id", "? INTEGER id"}},
+		{`SELECT * FROM users WHERE ? IN (id, name)`, []string{"? INTEGER id"}},
+		{`SELECT * FROM users WHERE ? IN (SELECT user_id FROM posts)`, []string{"? INTEGER user_id"}},
+		{`SELECT * FROM users WHERE score BETWEEN ? AND ?`, []string{"? REAL score", "? REAL score"}},
+		{`SELECT * FROM users WHERE ? BETWEEN 0 AND score`, []string{"? REAL score"}},
+		{`SELECT * FROM users WHERE (id, name) = (?, ?)`, []string{"? INTEGER id", "? TEXT name"}},
+		{`SELECT * FROM users WHERE CASE name WHEN ? THEN 1 END`, []string{"? TEXT name"}},
+		{`SELECT CASE ? WHEN score THEN 1 END FROM users`, []string{"? REAL score"}},
+		{`SELECT ? + 1, ? || 'x', ? & 1, -?, CAST(? AS VARCHAR(10)), ?`, []string{"? NUMERIC", "? TEXT", "? INTEGER", "? NUMERIC", "? TEXT", "? BLOB"}},
+		{`SELECT * FROM users LIMIT ? OFFSET ?`, []string{"? INTEGER", "? INTEGER"}},
+		{`SELECT * FROM users WHERE name = :name OR :name IS NULL`, []string{":name TEXT name"}},
+		{`SELECT * FROM users WHERE :name IS NULL OR name = :name`, []string{":name TEXT name"}},
+		{`SELECT * FROM users JOIN posts ON posts.user_id = users.id WHERE title = ?2 AND users.id = ?1`, []string{"?1 INTEGER id", "?2 TEXT title"}},
+		{`SELECT * FROM scores WHERE score > ?`, []string{"? INTEGER"}},
+		{`WITH t AS (SELECT name AS n FROM users) SELECT * FROM t WHERE n = ?`, []string{"? TEXT"}},
+		{`SELECT name AS n FROM users WHERE n = ?`, []string{"? TEXT name"}},
+		{`SELECT * FROM users WHERE id = (SELECT user_id FROM posts WHERE title = ?)`, []string{"? TEXT title"}},
+		{`SELECT * FROM users WHERE EXISTS (SELECT 1 FROM posts WHERE user_id = users.id AND body = ?)`, []string{"? TEXT body"}},
+		{`EXPLAIN SELECT * FROM users WHERE id = ?`, []string{"? INTEGER id"}},
+
+		{`INSERT INTO users VALUES (?, ?, ?, ?, ?)`, []string{"? INTEGER id", "? TEXT name", "? REAL score", "? BLOB data", "? BLOB created"}},
+		{`INSERT INTO users (name, id) VALUES (:name, :id), (:name2, :id2)`, []string{":name TEXT name", ":id INTEGER id", ":name2 TEXT name", ":id2 INTEGER id"}},
+		{`INSERT INTO posts (user_id, title) SELECT id, ? FROM users WHERE name = ?`, []string{"? TEXT title", "? TEXT name"}},
+		{`INSERT INTO posts SELECT *, ?, ? FROM users LIMIT 0`, []string{"? BLOB", "? BLOB"}},
+		{`INSERT INTO posts (user_id, title) SELECT id, 'x' FROM users UNION ALL VALUES (?, ?)`, []string{"? INTEGER user_id", "? TEXT title"}},
+		{`INSERT INTO users (id, score) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET score = excluded.score + ? WHERE score < ?`, []string{"? INTEGER id", "? REAL score", "? NUMERIC", "? REAL score"}},
+		{`INSERT INTO posts (user_id) VALUES (?) RETURNING id = ?`, []string{"? INTEGER user_id", "? INTEGER id"}},
+
+		{`UPDATE users SET name = ?, (score, data) = (?, ?) WHERE id = ?`, []string{"? TEXT name", "? REAL score", "? BLOB data", "? INTEGER id"}},
+		{`UPDATE posts SET title = u.name FROM users u WHERE u.id = posts.user_id AND u.score > ?`, []string{"? REAL score"}},
+		{`UPDATE posts SET body = ? RETURNING title = ?`, []string{"? TEXT body", "? TEXT title"}},
+		{`DELETE FROM posts WHERE user_id = ? RETURNING *`, []string{"? INTEGER user_id"}},
+		{`WITH old AS (SELECT id FROM users WHERE score < ?) DELETE FROM posts WHERE user_id IN old AND title = ?`, []string{"? REAL score", "? TEXT title"}},
+	} {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := params(t, paramsSchema, tt.s)
+			if err != nil {
+				t.Fatal(err)
+			} else if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestCatalog_Params_Error(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want []string
+	}{
+		{`SELECT * FROM users WHERE nope = ?`, []string{"no such column: nope"}},
+		{`SELECT * FROM nope WHERE id = ?0`, []string{"variable number must be between ?1 and ?32766", "no such table: nope"}},
+		{`UPDATE users SET name = ? WHERE nope = 1`, []string{"no such column: nope"}},
+		{`DELETE FROM posts WHERE users.id = ?`, []string{"no such column: users.id"}},
+		{`INSERT INTO users (id) VALUES (?) ON CONFLICT DO UPDATE SET name = other.name`, []string{"no such column: other.name"}},
+	} {
+		t.Run(tt.s, func(t *testing.T) {
+			_, err := params(t, paramsSchema, tt.s)
+			errs, ok := err.(sql.ErrorList)
+			if !ok {
+				t.Fatalf("Params()=%v, want %q", err, tt.want)
+			}
+			var msgs []string
+			for _, e := range errs {
+				msgs = append(msgs, e.Msg)
+			}
+			if diff := deep.Equal(msgs, tt.want); diff != nil {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+// Ensure the parameters are returned along with the errors.
+func TestCatalog_Params_Partial(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want []string
+	}{
+		{`SELECT * FROM users WHERE nope = ? AND id = ?`, []string{"? BLOB", "? INTEGER id"}},
+		{`SELECT * FROM nope WHERE id = ? LIMIT ?`, []string{"? BLOB", "? INTEGER"}},
+	} {
+		got, err := params(t, paramsSchema, tt.s)
+		if err == nil {
+			t.Fatalf("Params(%q): expected error", tt.s)
+		} else if diff := deep.Equal(got, tt.want); diff != nil {
+			t.Fatalf("Params(%q): %v", tt.s, diff)
+		}
+	}
+}
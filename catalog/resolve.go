@@ -0,0 +1,697 @@
+package catalog
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/TcMits/sql"
+)
+
+// Source represents a table, view, common table expression or subquery in a
+// FROM clause.
+type Source struct {
+	Name    string               // name used to qualify columns: alias or table name
+	Node    sql.Node             // *sql.QualifiedName or *sql.ParenSource in the FROM clause
+	Schema  *Schema              // schema of a table or view, nil otherwise
+	Table   *Table               // base table, or nil
+	View    *View                // view, or nil
+	CTE     *sql.CTE             // common table expression, or nil
+	Select  *sql.SelectStatement // subquery, or nil
+	Columns []string             // column names, nil if unknown such as for table-valued functions
+
+	merged []string // columns merged into a source to the left by USING or NATURAL
+}
+
+// Binding is the target of a column reference.
+type Binding struct {
+	Source *Source           // source of the column, nil for a result column
+	Column string            // column name as declared by the source, or alias
+	Result *sql.ResultColumn // result column referenced by its alias, or nil
+	Depth  int               // number of enclosing queries crossed, non-zero if correlated
+}
+
+// Resolution holds the bindings of the names within a SELECT statement.
+type Resolution struct {
+	Sources map[sql.Node]*Source    // sources keyed by their FROM clause node
+	Refs    map[sql.Expr]*Binding   // column references keyed by *sql.Ident or *sql.QualifiedRef
+	Stars   map[sql.Node][]*Binding // columns of "*" keyed by *sql.ResultColumn, and of "tbl.*" keyed by *sql.QualifiedRef
+}
+
+// Resolve binds each column reference within sel to the source it refers to.
+// Sources are tables & views of the catalog, common table expressions and
+// subqueries. References to outer queries are bound as correlated.
+//
+// Unknown and ambiguous names are returned as a sql.ErrorList, along with the
+// bindings of the names that were resolved. Like SQLite, an unknown
+// identifier in double quotes is treated as a string literal.
+func (c *Catalog) Resolve(sel *sql.SelectStatement) (*Resolution, error) {
+	r := newResolver(c)
+	r.selectStmt(sel, nil, nil, nil)
+	return r.res, r.errs.Err()
+}
+
+// resolver holds the state of Resolve & Params.
+type resolver struct {
+	catalog *Catalog
+	res     *Resolution
+	errs    sql.ErrorList
+}
+
+func newResolver(c *Catalog) *resolver {
+	return &resolver{
+		catalog: c,
+		res: &Resolution{
+			Sources: make(map[sql.Node]*Source),
+			Refs:    make(map[sql.Expr]*Binding),
+			Stars:   make(map[sql.Node][]*Binding),
+		},
+	}
+}
+
+// scope is the name space of a single SELECT core.
+type scope struct {
+	outer   *scope              // enclosing query, for correlated references
+	ctes    []*cteDef           // common table expressions in scope
+	sources []*Source           // sources of the FROM clause
+	results []*sql.ResultColumn // result columns, for aliases
+}
+
+// cteDef is a common table expression in scope.
+type cteDef struct {
+	cte   *sql.CTE
+	cols  []string
+	ready bool // false until the columns are known
+}
+
+func (r *resolver) errorf(n sql.Node, format string, args ...any) {
+	r.errs = append(r.errs, &sql.Error{Pos: n.Pos(), Msg: fmt.Sprintf(format, args...)})
+}
+
+// selectStmt resolves sel and returns the names of its result columns. The
+// columns of self, the CTE defined by sel, are set once known so recursive
+// references can be resolved.
+func (r *resolver) selectStmt(sel *sql.SelectStatement, outer *scope, ctes []*cteDef, self *cteDef) []string {
+	ctes = r.with(sel.WithClause, outer, ctes)
+
+	var first *scope
+	var names []string
+	for core := sel; core != nil; core = core.Compound {
+		sc := r.core(core, outer, ctes)
+		if first == nil {
+			first, names = sc, r.resultNames(core)
+			if self != nil && !self.ready {
+				self.cols, self.ready = names, true
+			}
+		}
+	}
+
+	for i, term := range sel.OrderingTerms {
+		if sel.Compound != nil {
+			r.compoundTerm(i, term, first)
+			continue
+		}
+		if ident, ok := term.X.(*sql.Ident); ok && r.alias(ident, first) {
+			continue
+		}
+		r.expr(term.X, first, true)
+	}
+
+	// LIMIT & OFFSET cannot refer to the columns of the query itself.
+	limit := &scope{outer: outer, ctes: ctes}
+	r.expr(sel.LimitExpr, limit, false)
+	r.expr(sel.OffsetExpr, limit, false)
+	return names
+}
+
+// with resolves the common table expressions of w and returns them appended
+// to the ones in scope.
+func (r *resolver) with(w *sql.WithClause, outer *scope, ctes []*cteDef) []*cteDef {
+	if w == nil {
+		return ctes
+	}
+
+	ctes = slices.Clip(ctes)
+	for _, cte := range w.CTEs {
+		def := &cteDef{cte: cte, ready: len(cte.Columns) > 0}
+		for _, col := range cte.Columns {
+			def.cols = append(def.cols, col.Name)
+		}
+		ctes = append(ctes, def)
+		r.selectStmt(cte.Select, outer, ctes, def)
+		def.ready = true
+	}
+	return ctes
+}
+
+// insertStmt resolves the INSERT statement stmt within outer, which is nil
+// unless stmt is part of a trigger body. Like SQLite, the upsert clause may
+// refer to the row proposed for insertion as "excluded".
+func (r *resolver) insertStmt(stmt *sql.InsertStatement, outer *scope) {
+	ctes := r.with(stmt.WithClause, outer, nil)
+	if stmt.Select != nil {
+		r.selectStmt(stmt.Select, outer, ctes, nil)
+	}
+	for _, list := range stmt.ValueLists {
+		r.expr(list, &scope{outer: outer, ctes: ctes}, false)
+	}
+
+	sc := &scope{outer: outer, ctes: ctes}
+	r.table(stmt.Table, sc)
+	if u := stmt.UpsertClause; u != nil {
+		// Unqualified names refer to the table, so "excluded" is only
+		// found by qualified references.
+		excluded := *sc.sources[0]
+		excluded.Name = "excluded"
+		upsert := &scope{outer: &scope{outer: outer, ctes: ctes, sources: []*Source{&excluded}}, ctes: ctes, sources: sc.sources}
+		for _, col := range u.Columns {
+			r.expr(col, upsert, false)
+		}
+		r.expr(u.WhereExpr, upsert, false)
+		for _, a := range u.Assignments {
+			r.expr(a.Expr, upsert, false)
+		}
+		r.expr(u.UpdateWhereExpr, upsert, false)
+	}
+	r.returning(stmt.ReturningColumns, sc)
+}
+
+// updateStmt resolves the UPDATE statement stmt within outer.
+func (r *resolver) updateStmt(stmt *sql.UpdateStatement, outer *scope) {
+	sc := &scope{outer: outer, ctes: r.with(stmt.WithClause, outer, nil)}
+	r.table(stmt.Table, sc)
+	r.join(stmt.Source, nil, nil, sc)
+	for _, a := range stmt.Assignments {
+		r.expr(a.Expr, sc, false)
+	}
+	r.expr(stmt.WhereExpr, sc, false)
+	r.returning(stmt.ReturningColumns, sc)
+	for _, term := range stmt.OrderingTerms {
+		r.expr(term.X, sc, false)
+	}
+	limit := &scope{outer: outer, ctes: sc.ctes}
+	r.expr(stmt.LimitExpr, limit, false)
+	r.expr(stmt.OffsetExpr, limit, false)
+}
+
+// deleteStmt resolves the DELETE statement stmt within outer.
+func (r *resolver) deleteStmt(stmt *sql.DeleteStatement, outer *scope) {
+	sc := &scope{outer: outer, ctes: r.with(stmt.WithClause, outer, nil)}
+	r.table(stmt.Table, sc)
+	r.expr(stmt.WhereExpr, sc, false)
+	r.returning(stmt.ReturningColumns, sc)
+	for _, term := range stmt.OrderingTerms {
+		r.expr(term.X, sc, false)
+	}
+	limit := &scope{outer: outer, ctes: sc.ctes}
+	r.expr(stmt.LimitExpr, limit, false)
+	r.expr(stmt.OffsetExpr, limit, false)
+}
+
+// triggerStmt resolves the WHEN clause & body of the trigger trig of schema s.
+// Like SQLite, they may refer to the rows of the table or view the trigger is
+// attached to as "new" & "old".
+func (r *resolver) triggerStmt(s *Schema, trig *Trigger) {
+	// A temporary trigger is attached to the first table of that name in
+	// search order, others to the table of their own schema.
+	name := &sql.QualifiedName{Name: &sql.Ident{Name: trig.Table}}
+	if s != r.catalog.schemas[1] {
+		name.Schema = &sql.Ident{Name: s.Name}
+	}
+	sc := &scope{}
+	r.table(name, sc)
+	newRow, oldRow := *sc.sources[0], *sc.sources[0]
+	newRow.Name, oldRow.Name = "new", "old"
+	sc.sources = []*Source{&newRow, &oldRow}
+
+	r.expr(trig.Stmt.WhenExpr, sc, false)
+	for _, stmt := range trig.Stmt.Body {
+		switch stmt := stmt.(type) {
+		case *sql.SelectStatement:
+			r.selectStmt(stmt, sc, nil, nil)
+		case *sql.InsertStatement:
+			r.insertStmt(stmt, sc)
+		case *sql.UpdateStatement:
+			r.updateStmt(stmt, sc)
+		case *sql.DeleteStatement:
+			r.deleteStmt(stmt, sc)
+		}
+	}
+}
+
+// returning resolves the RETURNING clause columns against the sources of sc.
+func (r *resolver) returning(columns []*sql.ResultColumn, sc *scope) {
+	for _, rc := range columns {
+		if rc.Star {
+			r.star(rc, sc)
+		} else {
+			r.expr(rc.Expr, sc, false)
+		}
+	}
+}
+
+// core resolves a single SELECT or VALUES clause of a compound statement and
+// returns its scope.
+func (r *resolver) core(core *sql.SelectStatement, outer *scope, ctes []*cteDef) *scope {
+	sc := &scope{outer: outer, ctes: ctes}
+	for _, list := range core.ValueLists {
+		r.expr(list, sc, false)
+	}
+
+	r.join(core.Source, nil, nil, sc)
+	sc.results = core.Columns
+
+	for _, rc := range core.Columns {
+		if rc.Star {
+			r.star(rc, sc)
+		} else if ref, ok := rc.Expr.(*sql.QualifiedRef); ok && ref.Star {
+			r.tableStar(ref, sc)
+		} else {
+			r.expr(rc.Expr, sc, false)
+		}
+	}
+
+	// SQLite also accepts result column aliases in WHERE, GROUP BY & HAVING
+	// where no column of the same name exists.
+	r.expr(core.WhereExpr, sc, true)
+	for _, expr := range core.GroupByExprs {
+		r.expr(expr, sc, true)
+	}
+	r.expr(core.HavingExpr, sc, true)
+	for _, w := range core.Windows {
+		r.expr(w, sc, false)
+	}
+	return sc
+}
+
+// resultNames returns the names of the result columns of core.
+func (r *resolver) resultNames(core *sql.SelectStatement) []string {
+	if len(core.ValueLists) > 0 {
+		names := make([]string, len(core.ValueLists[0].Exprs))
+		for i := range names {
+			names[i] = "column" + strconv.Itoa(i+1)
+		}
+		return names
+	}
+
+	names := []string{}
+	for _, rc := range core.Columns {
+		var star []*Binding
+		if rc.Star {
+			star = r.res.Stars[rc]
+		} else if ref, ok := rc.Expr.(*sql.QualifiedRef); ok && ref.Star {
+			star = r.res.Stars[ref]
+		} else {
+			names = append(names, resultName(rc))
+			continue
+		}
+		for _, b := range star {
+			names = append(names, b.Column)
+		}
+	}
+	return names
+}
+
+// join adds the sources of src to sc in order. The operator & constraint
+// join src to the sources added before it.
+func (r *resolver) join(src sql.Source, op *sql.JoinOperator, cons sql.JoinConstraint, sc *scope) {
+	n := len(sc.sources)
+	switch src := src.(type) {
+	case nil:
+		return
+	case *sql.JoinClause:
+		// The parser nests each subsequent join on the right side of the
+		// previous one, so the outer operator joins the leftmost source.
+		r.join(src.X, op, cons, sc)
+		r.join(src.Y, src.Operator, src.Constraint, sc)
+		return
+	case *sql.ParenSource:
+		if sel, ok := src.X.(*sql.SelectStatement); ok {
+			r.subquery(sel, src, src.Alias, sc)
+		} else {
+			r.join(src.X, nil, nil, sc)
+		}
+	case *sql.SelectStatement:
+		r.subquery(src, src, nil, sc)
+	case *sql.QualifiedName:
+		r.table(src, sc)
+	}
+
+	left, right := sc.sources[:n], sc.sources[n:]
+	switch cons := cons.(type) {
+	case *sql.OnConstraint:
+		r.expr(cons.X, sc, false)
+	case *sql.UsingConstraint:
+		for _, ident := range cons.Columns {
+			if !merge(left, right, ident.Name) {
+				r.errorf(ident, "cannot join using column %s - column not present in both tables", ident.Name)
+			}
+		}
+	}
+
+	if op != nil && op.Natural {
+		for _, src := range right {
+			for _, col := range src.Columns {
+				merge(left, right, col)
+			}
+		}
+	}
+}
+
+// merge merges the column name of the right sources into the first left
+// source with that column. Returns false if either side lacks the column.
+func merge(left, right []*Source, name string) bool {
+	i := slices.IndexFunc(left, func(src *Source) bool { return src.visible(name) })
+	j := slices.IndexFunc(right, func(src *Source) bool { return src.visible(name) })
+	if i == -1 || j == -1 {
+		return false
+	}
+	right[j].merged = append(right[j].merged, name)
+	return true
+}
+
+// subquery adds the subquery sel of a FROM clause to sc.
+func (r *resolver) subquery(sel *sql.SelectStatement, node sql.Node, alias *sql.Ident, sc *scope) {
+	// Subqueries in a FROM clause cannot refer to the sources beside them.
+	src := &Source{Node: node, Select: sel, Columns: r.selectStmt(sel, sc.outer, sc.ctes, nil)}
+	if alias != nil {
+		src.Name = alias.Name
+	}
+	r.add(src, sc)
+}
+
+// table adds the table, view, CTE or table-valued function name to sc.
+func (r *resolver) table(name *sql.QualifiedName, sc *scope) {
+	src := &Source{Name: name.Name.Name, Node: name}
+	if name.Alias != nil {
+		src.Name = name.Alias.Name
+	}
+	defer r.add(src, sc)
+
+	if name.FunctionCall {
+		for _, arg := range name.FunctionArgs {
+			r.expr(arg, sc, false)
+		}
+		return
+	}
+
+	if name.Schema == nil {
+		for i := len(sc.ctes) - 1; i >= 0; i-- {
+			def := sc.ctes[i]
+			if !equalName(def.cte.TableName.Name, name.Name.Name) {
+				continue
+			} else if !def.ready {
+				r.errorf(name, "circular reference: %s", def.cte.TableName.Name)
+				return
+			}
+			src.CTE, src.Columns = def.cte, def.cols
+			return
+		}
+	}
+
+	schemas, err := r.catalog.lookupSchemas(name)
+	if err != nil {
+		r.errorf(name, "%s", err)
+		return
+	}
+	for _, s := range schemas {
+		if t := s.Table(name.Name.Name); t != nil {
+			src.Schema, src.Table = s, t
+			if !t.Virtual || len(t.Columns) > 0 {
+				src.Columns = make([]string, len(t.Columns))
+				for i, col := range t.Columns {
+					src.Columns[i] = col.Name
+				}
+			}
+			return
+		} else if v := s.View(name.Name.Name); v != nil {
+			src.Schema, src.View = s, v
+			cols, _ := r.catalog.resultColumns(v.Select, nil)
+			src.Columns = make([]string, len(cols))
+			for i, col := range cols {
+				src.Columns[i] = col.Name
+				if i < len(v.Columns) {
+					src.Columns[i] = v.Columns[i]
+				}
+			}
+			return
+		}
+	}
+	r.errorf(name, "no such table: %s", displayName(name))
+}
+
+func (r *resolver) add(src *Source, sc *scope) {
+	r.res.Sources[src.Node] = src
+	sc.sources = append(sc.sources, src)
+}
+
+// expr resolves the column references within n. If aliases is true, names
+// not found in any source may refer to result columns of sc.
+func (r *resolver) expr(n sql.Node, sc *scope, aliases bool) {
+	if n == nil || reflect.ValueOf(n).IsNil() {
+		return
+	}
+
+	sql.Apply(n, func(c *sql.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *sql.SelectStatement:
+			r.selectStmt(n, sc, sc.ctes, nil)
+			return false
+		case *sql.QualifiedRef:
+			r.qualifiedRef(n, sc)
+			return false
+		case *sql.Ident:
+			if c.Depth() == 0 || isExprField(c) {
+				r.ident(n, sc, aliases)
+			}
+		}
+		return true
+	}, nil)
+}
+
+// ident binds the unqualified column reference ident.
+func (r *resolver) ident(ident *sql.Ident, sc *scope, aliases bool) {
+	for s, depth := sc, 0; s != nil; s, depth = s.outer, depth+1 {
+		var matches []*Source
+		for _, src := range s.sources {
+			if src.visible(ident.Name) {
+				matches = append(matches, src)
+			}
+		}
+		if len(matches) == 0 {
+			for _, src := range s.sources {
+				if src.hasRowID(ident.Name) {
+					matches = append(matches, src)
+				}
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+		case 1:
+			r.res.Refs[ident] = &Binding{Source: matches[0], Column: columnName(matches[0], ident.Name), Depth: depth}
+			return
+		default:
+			r.errorf(ident, "ambiguous column name: %s", ident.Name)
+			return
+		}
+
+		if aliases && depth == 0 && r.alias(ident, s) {
+			return
+		}
+
+		// The name may belong to a source with unknown columns.
+		var unknown []*Source
+		for _, src := range s.sources {
+			if src.Columns == nil {
+				unknown = append(unknown, src)
+			}
+		}
+		if len(unknown) == 1 {
+			r.res.Refs[ident] = &Binding{Source: unknown[0], Column: ident.Name, Depth: depth}
+			return
+		} else if len(unknown) > 1 {
+			return
+		}
+	}
+
+	if ident.Quoted && (ident.Quote == "" || ident.Quote == "'") {
+		return
+	}
+	r.errorf(ident, "no such column: %s", ident.Name)
+}
+
+// alias binds ident to the result column of sc with the same alias. Returns
+// false if there is none.
+func (r *resolver) alias(ident *sql.Ident, sc *scope) bool {
+	for _, rc := range sc.results {
+		if rc.Alias != nil && equalName(rc.Alias.Name, ident.Name) {
+			r.res.Refs[ident] = &Binding{Column: rc.Alias.Name, Result: rc}
+			return true
+		}
+	}
+	return false
+}
+
+// qualifiedRef binds the column reference "tbl.col".
+func (r *resolver) qualifiedRef(ref *sql.QualifiedRef, sc *scope) {
+	name := displayName(ref.Table) + "." + ref.Column.Name
+	for s, depth := sc, 0; s != nil; s, depth = s.outer, depth+1 {
+		matches := s.lookup(ref.Table)
+		if len(matches) == 0 {
+			continue
+		} else if len(matches) > 1 {
+			r.errorf(ref, "ambiguous column name: %s", name)
+			return
+		}
+
+		src := matches[0]
+		if src.Columns != nil && !src.hasColumn(ref.Column.Name) && !src.hasRowID(ref.Column.Name) {
+			break
+		}
+		r.res.Refs[ref] = &Binding{Source: src, Column: columnName(src, ref.Column.Name), Depth: depth}
+		return
+	}
+	r.errorf(ref, "no such column: %s", name)
+}
+
+// star expands the "*" result column rc. Columns merged by USING or NATURAL
+// are included once.
+func (r *resolver) star(rc *sql.ResultColumn, sc *scope) {
+	if len(sc.sources) == 0 {
+		r.errorf(rc, "no tables specified")
+		return
+	}
+
+	bindings := []*Binding{}
+	for _, src := range sc.sources {
+		for _, col := range src.Columns {
+			if !slices.ContainsFunc(src.merged, func(name string) bool { return equalName(name, col) }) {
+				bindings = append(bindings, &Binding{Source: src, Column: col})
+			}
+		}
+	}
+	r.res.Stars[rc] = bindings
+}
+
+// tableStar expands the "tbl.*" result column ref.
+func (r *resolver) tableStar(ref *sql.QualifiedRef, sc *scope) {
+	matches := sc.lookup(ref.Table)
+	if len(matches) == 0 {
+		r.errorf(ref, "no such table: %s", displayName(ref.Table))
+		return
+	}
+
+	bindings := []*Binding{}
+	for _, col := range matches[0].Columns {
+		bindings = append(bindings, &Binding{Source: matches[0], Column: col})
+	}
+	r.res.Stars[ref] = bindings
+}
+
+// compoundTerm binds the i-th ORDER BY term of a compound SELECT. Terms must
+// name a result column of the first SELECT.
+func (r *resolver) compoundTerm(i int, term *sql.OrderingTerm, first *scope) {
+	ident, ok := term.X.(*sql.Ident)
+	if !ok {
+		r.expr(term.X, first, true)
+		return
+	}
+
+	for _, rc := range first.results {
+		var star []*Binding
+		if rc.Star {
+			star = r.res.Stars[rc]
+		} else if ref, ok := rc.Expr.(*sql.QualifiedRef); ok && ref.Star {
+			star = r.res.Stars[ref]
+		} else if name := resultName(rc); equalName(name, ident.Name) {
+			r.res.Refs[ident] = &Binding{Column: name, Result: rc}
+			return
+		}
+
+		for _, b := range star {
+			if equalName(b.Column, ident.Name) {
+				r.res.Refs[ident] = &Binding{Source: b.Source, Column: b.Column}
+				return
+			}
+		}
+	}
+	r.errorf(ident, "%s ORDER BY term does not match any column in the result set", ordinal(i+1))
+}
+
+// lookup returns the sources of sc named by name.
+func (sc *scope) lookup(name *sql.QualifiedName) []*Source {
+	var matches []*Source
+	for _, src := range sc.sources {
+		if !equalName(src.Name, name.Name.Name) {
+			continue
+		} else if name.Schema != nil && (src.Schema == nil || src.Name != src.tableName() || !equalName(src.Schema.Name, name.Schema.Name)) {
+			continue
+		}
+		matches = append(matches, src)
+	}
+	return matches
+}
+
+// tableName returns the name of the table or view of src, if any.
+func (src *Source) tableName() string {
+	if src.Table != nil {
+		return src.Table.Name
+	} else if src.View != nil {
+		return src.View.Name
+	}
+	return ""
+}
+
+// hasColumn reports whether src has a column named name.
+func (src *Source) hasColumn(name string) bool {
+	return slices.ContainsFunc(src.Columns, func(col string) bool { return equalName(col, name) })
+}
+
+// visible reports whether an unqualified reference to name may refer to a
+// column of src, i.e. the column exists and was not merged by a join.
+func (src *Source) visible(name string) bool {
+	return src.hasColumn(name) && !slices.ContainsFunc(src.merged, func(col string) bool { return equalName(col, name) })
+}
+
+// hasRowID reports whether name refers to the rowid of the base table src.
+func (src *Source) hasRowID(name string) bool {
+	if src.Table == nil || src.Table.WithoutRowID || src.Table.Virtual {
+		return false
+	}
+	return equalName(name, "rowid") || equalName(name, "oid") || equalName(name, "_rowid_")
+}
+
+// columnName returns the declared name of the column of src named name.
+func columnName(src *Source, name string) string {
+	if i := slices.IndexFunc(src.Columns, func(col string) bool { return equalName(col, name) }); i != -1 {
+		return src.Columns[i]
+	}
+	return name
+}
+
+// resultName returns the name of the result column rc.
+func resultName(rc *sql.ResultColumn) string {
+	if rc.Alias != nil {
+		return rc.Alias.Name
+	}
+	return exprName(rc.Expr)
+}
+
+// ordinal returns n as an English ordinal number, such as "2nd".
+func ordinal(n int) string {
+	suffix := "th"
+	switch n % 10 {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	if n%100 >= 11 && n%100 <= 13 {
+		suffix = "th"
+	}
+	return strconv.Itoa(n) + suffix
+}
@@ -0,0 +1,218 @@
+package catalog_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/TcMits/sql"
+	"github.com/TcMits/sql/catalog"
+	"github.com/go-test/deep"
+)
+
+const resolveSchema = `
+	CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, org_id INTEGER);
+	CREATE TABLE orgs (id INTEGER PRIMARY KEY, name TEXT);
+	CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER, title TEXT);
+	CREATE TABLE kv (k PRIMARY KEY, v) WITHOUT ROWID;
+	CREATE VIEW user_orgs (user_name, org_name) AS SELECT users.name, orgs.name FROM users JOIN orgs ON users.org_id = orgs.id;
+	CREATE VIRTUAL TABLE docs USING fts5(body);
+`
+
+// MustResolve parses s as a SELECT statement and resolves it against the
+// schema. Fail on error.
+func MustResolve(tb testing.TB, schema, s string) (*sql.SelectStatement, *catalog.Resolution) {
+	tb.Helper()
+	sel, res, err := resolve(tb, schema, s)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return sel, res
+}
+
+// AssertResolveError asserts that resolving s against the schema fails with
+// the error messages want.
+func AssertResolveError(tb testing.TB, schema, s string, want ...string) {
+	tb.Helper()
+	_, _, err := resolve(tb, schema, s)
+	errs, ok := err.(sql.ErrorList)
+	if !ok {
+		tb.Fatalf("Resolve(%q)=%v, want %q", s, err, want)
+	}
+	var msgs []string
+	for _, e := range errs {
+		msgs = append(msgs, e.Msg)
+	}
+	if diff := deep.Equal(msgs, want); diff != nil {
+		tb.Fatalf("Resolve(%q): %v", s, diff)
+	}
+}
+
+func resolve(tb testing.TB, schema, s string) (*sql.SelectStatement, *catalog.Resolution, error) {
+	tb.Helper()
+	stmt, err := sql.ParseStmtString(s)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	sel := stmt.(*sql.SelectStatement)
+	res, err := MustExec(tb, schema).Resolve(sel)
+	return sel, res, err
+}
+
+// refs returns the bindings of the column references within n in source
+// order, formatted as "ref=source.column". A "^N" suffix marks correlated
+// references and "AS" marks result column aliases.
+func refs(n sql.Node, res *catalog.Resolution) []string {
+	var a []string
+	for c, enter := range sql.Traverse(n) {
+		if expr, ok := c.Node().(sql.Expr); ok && enter && res.Refs[expr] != nil {
+			a = append(a, expr.String()+"="+binding(res.Refs[expr]))
+		}
+	}
+	return a
+}
+
+func binding(b *catalog.Binding) string {
+	var s string
+	if b.Result != nil {
+		s = "AS " + b.Column
+	} else {
+		s = b.Source.Name + "." + b.Column
+	}
+	if b.Depth > 0 {
+		s += fmt.Sprintf("^%d", b.Depth)
+	}
+	return s
+}
+
+func stars(res *catalog.Resolution, n sql.Node) string {
+	var a []string
+	for _, b := range res.Stars[n] {
+		a = append(a, binding(b))
+	}
+	return strings.Join(a, ", ")
+}
+
+func TestResolve(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		s    string
+		want []string
+	}{
+		{"Unqualified", `SELECT name FROM users WHERE id = 1`, []string{`"name"=users.name`, `"id"=users.id`}},
+		{"Alias", `SELECT u.name FROM users AS u WHERE u.ID = 1`, []string{`"u"."name"=u.name`, `"u"."ID"=u.id`}},
+		{"Schema", `SELECT main.users.name FROM main.users`, []string{`"main"."users"."name"=users.name`}},
+		{"Join", `SELECT users.name, orgs.name, org_id FROM users JOIN orgs ON org_id = orgs.id`, []string{
+			`"users"."name"=users.name`, `"orgs"."name"=orgs.name`, `"org_id"=users.org_id`, `"org_id"=users.org_id`, `"orgs"."id"=orgs.id`,
+		}},
+		{"Using", `SELECT id, name FROM users JOIN orgs USING (id, name)`, []string{`"id"=users.id`, `"name"=users.name`}},
+		{"Natural", `SELECT id, o.id FROM users NATURAL JOIN orgs AS o`, []string{`"id"=users.id`, `"o"."id"=o.id`}},
+		{"View", `SELECT user_name FROM user_orgs`, []string{`"user_name"=user_orgs.user_name`}},
+		{"CTE", `WITH u (n) AS (SELECT name FROM users) SELECT n FROM u`, []string{`"name"=users.name`, `"n"=u.n`}},
+		{"RecursiveCTE", `WITH RECURSIVE c AS (SELECT 1 AS x UNION ALL SELECT x + 1 FROM c WHERE x < 10) SELECT x FROM c`, []string{
+			`"x"=c.x`, `"x"=c.x`, `"x"=c.x`,
+		}},
+		{"Subquery", `SELECT s.n FROM (SELECT name AS n FROM users) AS s`, []string{`"s"."n"=s.n`, `"name"=users.name`}},
+		{"Correlated", `SELECT name FROM users AS u WHERE EXISTS (SELECT 1 FROM posts WHERE user_id = u.id AND id = 1)`, []string{
+			`"name"=u.name`, `"user_id"=posts.user_id`, `"u"."id"=u.id^1`, `"id"=posts.id`,
+		}},
+		{"ScalarSubquery", `SELECT (SELECT count(*) FROM posts WHERE user_id = id) FROM users`, []string{`"user_id"=posts.user_id`, `"id"=posts.id`}},
+		{"OrderByAlias", `SELECT name AS n FROM users ORDER BY n, id`, []string{`"name"=users.name`, `"n"=AS n`, `"id"=users.id`}},
+		{"OrderByColumn", `SELECT id AS name FROM users ORDER BY name + 1`, []string{`"id"=users.id`, `"name"=users.name`}},
+		{"WhereAlias", `SELECT length(name) AS len FROM users WHERE len > 1`, []string{`"name"=users.name`, `"len"=AS len`}},
+		{"Compound", `SELECT name FROM users UNION SELECT name FROM orgs ORDER BY name`, []string{`"name"=users.name`, `"name"=orgs.name`, `"name"=AS name`}},
+		{"RowID", `SELECT rowid, _ROWID_ FROM users`, []string{`"rowid"=users.rowid`, `"_ROWID_"=users._ROWID_`}},
+		{"TableFunction", `SELECT key, value FROM users, json_each(users.name)`, []string{`"key"=json_each.key`, `"value"=json_each.value`, `"users"."name"=users.name`}},
+		{"Virtual", `SELECT body FROM docs WHERE docs MATCH 'x'`, []string{`"body"=docs.body`, `"docs"=docs.docs`}},
+		{"Collate", `SELECT name COLLATE nocase FROM users`, []string{`"name"=users.name`}},
+		{"Window", `SELECT row_number() OVER w FROM users WINDOW w AS (PARTITION BY org_id)`, []string{`"org_id"=users.org_id`}},
+		{"StringLiteral", `SELECT "unknown" FROM users`, nil},
+		{"Values", `SELECT column2 FROM (VALUES (1, 2))`, []string{`"column2"=.column2`}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sel, res := MustResolve(t, resolveSchema, tt.s)
+			if diff := deep.Equal(refs(sel, res), tt.want); diff != nil {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestResolve_Sources(t *testing.T) {
+	sel, res := MustResolve(t, resolveSchema, `WITH c AS (SELECT 1) SELECT * FROM users, user_orgs, c, (SELECT 1) AS s`)
+
+	var kinds []string
+	for c, enter := range sql.Traverse(sel.Source) {
+		src := res.Sources[c.Node()]
+		if !enter || src == nil {
+			continue
+		}
+		switch {
+		case src.Table != nil:
+			kinds = append(kinds, "table "+src.Schema.Name+"."+src.Table.Name)
+		case src.View != nil:
+			kinds = append(kinds, "view "+src.Schema.Name+"."+src.View.Name)
+		case src.CTE != nil:
+			kinds = append(kinds, "cte "+src.CTE.TableName.Name)
+		case src.Select != nil:
+			kinds = append(kinds, "subquery "+src.Name)
+		}
+	}
+	if diff := deep.Equal(kinds, []string{"table main.users", "view main.user_orgs", "cte c", "subquery s"}); diff != nil {
+		t.Fatal(diff)
+	}
+}
+
+func TestResolve_Star(t *testing.T) {
+	sel, res := MustResolve(t, resolveSchema, `SELECT *, o.* FROM users JOIN orgs AS o USING (id)`)
+	if got, want := stars(res, sel.Columns[0]), "users.id, users.name, users.org_id, o.name"; got != want {
+		t.Fatalf("*=%s, want %s", got, want)
+	}
+	if got, want := stars(res, sel.Columns[1].Expr), "o.id, o.name"; got != want {
+		t.Fatalf("o.*=%s, want %s", got, want)
+	}
+
+	// Expanded columns name the columns of subqueries & CTEs.
+	sel, res = MustResolve(t, resolveSchema, `WITH c AS (SELECT * FROM users NATURAL JOIN orgs) SELECT org_id FROM c`)
+	if diff := deep.Equal(refs(sel, res), []string{`"org_id"=c.org_id`}); diff != nil {
+		t.Fatal(diff)
+	}
+	AssertResolveError(t, resolveSchema, `SELECT org_id FROM (SELECT o.* FROM users, orgs AS o)`, `no such column: org_id`)
+}
+
+func TestResolve_Error(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want []string
+	}{
+		{`SELECT x FROM users`, []string{`no such column: x`}},
+		{`SELECT id FROM users, orgs`, []string{`ambiguous column name: id`}},
+		{`SELECT users.id FROM users, users`, []string{`ambiguous column name: users.id`}},
+		{`SELECT u.x FROM users AS u`, []string{`no such column: u.x`}},
+		{`SELECT users.id FROM users AS u`, []string{`no such column: users.id`}},
+		{`SELECT temp.users.id FROM users`, []string{`no such column: temp.users.id`}},
+		{`SELECT * FROM nosuch`, []string{`no such table: nosuch`}},
+		{`SELECT * FROM aux.users`, []string{`no such database: aux`}},
+		{`SELECT x.* FROM users`, []string{`no such table: x`}},
+		{`SELECT *`, []string{`no tables specified`}},
+		{`SELECT rowid FROM kv`, []string{`no such column: rowid`}},
+		{`SELECT 1 FROM users JOIN posts USING (title)`, []string{`cannot join using column title - column not present in both tables`}},
+		{`SELECT 1 FROM users AS u WHERE (SELECT name FROM posts WHERE u.id = 1) LIMIT id`, []string{`no such column: id`}},
+		{`SELECT name FROM users UNION SELECT name FROM orgs ORDER BY name, id`, []string{`2nd ORDER BY term does not match any column in the result set`}},
+		{`WITH c AS (SELECT * FROM c) SELECT 1`, []string{`circular reference: c`}},
+		{`SELECT (SELECT n FROM users) AS n FROM users`, []string{`no such column: n`}},
+		{`SELECT x, y FROM users`, []string{`no such column: x`, `no such column: y`}},
+	} {
+		t.Run(tt.s, func(t *testing.T) {
+			AssertResolveError(t, resolveSchema, tt.s, tt.want...)
+		})
+	}
+}
+
+// Ensure errors are reported at the position of the offending name.
+func TestResolve_ErrorPos(t *testing.T) {
+	_, _, err := resolve(t, resolveSchema, `SELECT id, x FROM users`)
+	if errs, ok := err.(sql.ErrorList); !ok || errs[0].Pos.GetOffset() != 11 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package catalog
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/TcMits/sql"
+)
+
+// resultColumn is a column of the result of a SELECT statement.
+type resultColumn struct {
+	Name string
+	Type string   // declared type derived from the expression affinity
+	Expr sql.Expr // unaliased expression of the statement being executed, or nil
+}
+
+// relation is a named set of columns a SELECT statement reads from.
+type relation struct {
+	name string
+	cols []resultColumn
+}
+
+// resultColumns returns the result columns of sel. Common table expressions
+// in scope shadow tables & views of the catalog.
+func (c *Catalog) resultColumns(sel *sql.SelectStatement, scope []relation) ([]resultColumn, error) {
+	if sel.WithClause != nil {
+		scope = slices.Clip(scope)
+		for _, cte := range sel.WithClause.CTEs {
+			cols, err := c.resultColumns(cte.Select, scope)
+			if err != nil {
+				return nil, err
+			}
+			for i, ident := range cte.Columns {
+				if i < len(cols) {
+					cols[i].Name, cols[i].Expr = ident.Name, nil
+				}
+			}
+			scope = append(scope, relation{name: cte.TableName.Name, cols: cols})
+		}
+	}
+
+	if len(sel.ValueLists) > 0 {
+		cols := make([]resultColumn, len(sel.ValueLists[0].Exprs))
+		for i := range cols {
+			cols[i].Name = "column" + strconv.Itoa(i+1)
+		}
+		return cols, nil
+	}
+
+	rels, err := c.relations(sel.Source, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	var cols []resultColumn
+	for _, rc := range sel.Columns {
+		if rc.Star {
+			if len(rels) == 0 {
+				return nil, fmt.Errorf("no tables specified")
+			}
+			for _, rel := range rels {
+				cols = append(cols, rel.cols...)
+			}
+			continue
+		}
+
+		if ref, ok := rc.Expr.(*sql.QualifiedRef); ok && ref.Star {
+			rel := findRelation(rels, ref.Table.Name.Name)
+			if rel == nil {
+				return nil, fmt.Errorf("no such table: %s", ref.Table.Name.Name)
+			}
+			cols = append(cols, rel.cols...)
+			continue
+		}
+
+		col := resultColumn{Name: exprName(rc.Expr), Type: exprType(rc.Expr, rels)}
+		if rc.Alias != nil {
+			col.Name = rc.Alias.Name
+		} else {
+			col.Expr = rc.Expr
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// relations returns the relations of a FROM clause in order.
+func (c *Catalog) relations(src sql.Source, scope []relation) ([]relation, error) {
+	switch src := src.(type) {
+	case *sql.JoinClause:
+		x, err := c.relations(src.X, scope)
+		if err != nil {
+			return nil, err
+		}
+		y, err := c.relations(src.Y, scope)
+		if err != nil {
+			return nil, err
+		}
+		return append(x, y...), nil
+
+	case *sql.ParenSource:
+		sel, ok := src.X.(*sql.SelectStatement)
+		if !ok {
+			return c.relations(src.X, scope)
+		}
+		cols, err := c.resultColumns(sel, scope)
+		if err != nil {
+			return nil, err
+		}
+		rel := relation{cols: cols}
+		if src.Alias != nil {
+			rel.name = src.Alias.Name
+		}
+		return []relation{rel}, nil
+
+	case *sql.SelectStatement:
+		cols, err := c.resultColumns(src, scope)
+		if err != nil {
+			return nil, err
+		}
+		return []relation{{cols: cols}}, nil
+
+	case *sql.QualifiedName:
+		rel := relation{name: src.Name.Name}
+		if src.Alias != nil {
+			rel.name = src.Alias.Name
+		}
+
+		// The columns of table-valued functions are unknown.
+		if src.FunctionCall {
+			return []relation{rel}, nil
+		}
+
+		cols, err := c.sourceColumns(src, scope)
+		if err != nil {
+			return nil, err
+		}
+		rel.cols = cols
+		return []relation{rel}, nil
+	}
+	return nil, nil
+}
+
+// sourceColumns returns the columns of the CTE, table or view named by name.
+func (c *Catalog) sourceColumns(name *sql.QualifiedName, scope []relation) ([]resultColumn, error) {
+	if name.Schema == nil {
+		for i := len(scope) - 1; i >= 0; i-- {
+			if equalName(scope[i].name, name.Name.Name) {
+				return slices.Clone(scope[i].cols), nil
+			}
+		}
+	}
+
+	schemas, err := c.lookupSchemas(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range schemas {
+		if t := s.Table(name.Name.Name); t != nil {
+			cols := make([]resultColumn, len(t.Columns))
+			for i, col := range t.Columns {
+				cols[i] = resultColumn{Name: col.Name, Type: affinityType(col.Affinity())}
+			}
+			return cols, nil
+		} else if v := s.View(name.Name.Name); v != nil {
+			cols, err := c.resultColumns(v.Select, nil)
+			if err != nil {
+				return nil, err
+			}
+			// The view was parsed from another source.
+			for i := range cols {
+				cols[i].Expr = nil
+			}
+			for i, name := range v.Columns {
+				if i < len(cols) {
+					cols[i].Name = name
+				}
+			}
+			return cols, nil
+		}
+	}
+	return nil, fmt.Errorf("no such table: %s", displayName(name))
+}
+
+func findRelation(rels []relation, name string) *relation {
+	for i := range rels {
+		if equalName(rels[i].name, name) {
+			return &rels[i]
+		}
+	}
+	return nil
+}
+
+// exprName returns the name of a result column without an alias.
+func exprName(expr sql.Expr) string {
+	switch expr := expr.(type) {
+	case *sql.Ident:
+		return expr.Name
+	case *sql.QualifiedRef:
+		return expr.Column.Name
+	}
+	return expr.String()
+}
+
+// exprType returns the declared type of a column created from expr.
+func exprType(expr sql.Expr, rels []relation) string {
+	switch expr := expr.(type) {
+	case *sql.Ident:
+		for _, rel := range rels {
+			for _, col := range rel.cols {
+				if equalName(col.Name, expr.Name) {
+					return col.Type
+				}
+			}
+		}
+	case *sql.QualifiedRef:
+		if rel := findRelation(rels, expr.Table.Name.Name); rel != nil {
+			for _, col := range rel.cols {
+				if equalName(col.Name, expr.Column.Name) {
+					return col.Type
+				}
+			}
+		}
+	case *sql.CastExpr:
+		return affinityType(TypeAffinity(expr.Type.Name.Name))
+	case *sql.ParenExpr:
+		return exprType(expr.Expr, rels)
+	}
+	return ""
+}
+
+// affinityType returns the declared type SQLite uses for a column with
+// affinity a created by CREATE TABLE ... AS SELECT.
+func affinityType(a Affinity) string {
+	switch a {
+	case TEXT:
+		return "TEXT"
+	case NUMERIC:
+		return "NUM"
+	case INTEGER:
+		return "INT"
+	case REAL:
+		return "REAL"
+	}
+	return ""
+}
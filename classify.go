@@ -0,0 +1,215 @@
+package sql
+
+import (
+	"strings"
+)
+
+// Effects describes the side effects of executing a statement. The zero value
+// describes a read-only statement.
+type Effects struct {
+	Write       bool // writes to the database file, e.g. table rows
+	Schema      bool // creates, alters or drops schema objects
+	Transaction bool // begins or ends a transaction or savepoint
+	Connection  bool // changes the state of the connection, e.g. ATTACH or a PRAGMA setting
+	Exclusive   bool // needs an exclusive lock on the database, e.g. VACUUM
+	Explain     bool // the statement is explained and not executed
+}
+
+// ReadOnly reports whether the statement has no side effects. EXPLAIN is not
+// considered, so an explained statement is read-only only if the statement it
+// explains is.
+func (e Effects) ReadOnly() bool {
+	e.Explain = false
+	return e == Effects{}
+}
+
+// Or returns the union of e and other.
+func (e Effects) Or(other Effects) Effects {
+	return Effects{
+		Write:       e.Write || other.Write,
+		Schema:      e.Schema || other.Schema,
+		Transaction: e.Transaction || other.Transaction,
+		Connection:  e.Connection || other.Connection,
+		Exclusive:   e.Exclusive || other.Exclusive,
+		Explain:     e.Explain || other.Explain,
+	}
+}
+
+// String returns the effects as a comma-separated list, such as
+// "write, schema", or "read-only" if there are none.
+func (e Effects) String() string {
+	var a []string
+	for _, f := range []struct {
+		set  bool
+		name string
+	}{
+		{e.Explain, "explain"},
+		{e.Write, "write"},
+		{e.Schema, "schema"},
+		{e.Transaction, "transaction"},
+		{e.Connection, "connection"},
+		{e.Exclusive, "exclusive"},
+	} {
+		if f.set {
+			a = append(a, f.name)
+		}
+	}
+	if e.ReadOnly() {
+		a = append(a, "read-only")
+	}
+	return strings.Join(a, ", ")
+}
+
+// ClassifyOption configures Classify.
+type ClassifyOption func(*classifier)
+
+// FunctionEffects declares the effects of calling the functions in m, keyed by
+// function name. Names are compared case-insensitively and override the
+// built-in functions with side effects, such as load_extension().
+func FunctionEffects(m map[string]Effects) ClassifyOption {
+	return func(c *classifier) {
+		for name, e := range m {
+			c.functions[strings.ToLower(name)] = e
+		}
+	}
+}
+
+// functionEffects holds the effects of SQLite functions with side effects,
+// including those of extensions shipped with the sqlite3 shell.
+var functionEffects = map[string]Effects{
+	"load_extension":     {Connection: true},
+	"fts3_tokenizer":     {Connection: true},
+	"icu_load_collation": {Connection: true},
+	"writefile":          {Write: true},
+}
+
+// pragmaEffects holds the effects of pragmas that do more than query or set a
+// property of the connection. The first element applies when the pragma is
+// invoked without a value, the second when a value is given.
+var pragmaEffects = map[string][2]Effects{
+	"application_id":     {{}, {Write: true}},
+	"auto_vacuum":        {{}, {Write: true}},
+	"foreign_key_check":  {{}, {}},
+	"foreign_key_list":   {{}, {}},
+	"incremental_vacuum": {{Write: true}, {Write: true}},
+	"index_info":         {{}, {}},
+	"index_list":         {{}, {}},
+	"index_xinfo":        {{}, {}},
+	"integrity_check":    {{}, {}},
+	"journal_mode":       {{}, {Write: true, Connection: true}},
+	"optimize":           {{Write: true}, {Write: true}},
+	"quick_check":        {{}, {}},
+	"schema_version":     {{}, {Write: true, Schema: true}},
+	"shrink_memory":      {{Connection: true}, {Connection: true}},
+	"table_info":         {{}, {}},
+	"table_list":         {{}, {}},
+	"table_xinfo":        {{}, {}},
+	"user_version":       {{}, {Write: true}},
+	"wal_checkpoint":     {{Write: true}, {Write: true}},
+}
+
+// Classify returns the side effects of executing stmt.
+//
+// Statements are classified by kind: INSERT, UPDATE, DELETE, ANALYZE and
+// REINDEX write; CREATE, ALTER and DROP change the schema; BEGIN, COMMIT,
+// ROLLBACK, SAVEPOINT and RELEASE control transactions; ATTACH, DETACH and
+// pragmas given a value change the connection; VACUUM needs an exclusive lock
+// and writes, unless it writes INTO another file. Calls to functions with side
+// effects add their effects.
+//
+// The effects of EXPLAIN are those of the statement it explains with Explain
+// set, so that it can be routed like the statement itself, although SQLite
+// does not execute it.
+func Classify(stmt Statement, opts ...ClassifyOption) Effects {
+	c := classifier{functions: make(map[string]Effects, len(functionEffects))}
+	for name, e := range functionEffects {
+		c.functions[name] = e
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c.stmt(stmt)
+}
+
+type classifier struct {
+	functions map[string]Effects
+}
+
+func (c *classifier) stmt(stmt Statement) Effects {
+	var e Effects
+	switch stmt := stmt.(type) {
+	case *ExplainStatement:
+		e = c.stmt(stmt.Stmt)
+		e.Explain = true
+		return e
+	case *SelectStatement:
+	case *InsertStatement, *UpdateStatement, *DeleteStatement, *AnalyzeStatement, *ReindexStatement:
+		e.Write = true
+	case *CreateTableStatement:
+		e.Schema = true
+		e.Write = stmt.Select != nil
+		return e.Or(c.calls(stmt.Select))
+	case *CreateViewStatement, *CreateTriggerStatement:
+		// The statements within are not executed.
+		return Effects{Schema: true}
+	case *CreateVirtualTableStatement, *CreateIndexStatement, *AlterTableStatement,
+		*DropTableStatement, *DropViewStatement, *DropIndexStatement, *DropTriggerStatement:
+		e.Schema = true
+	case *BeginStatement:
+		e.Transaction = true
+		e.Exclusive = stmt.Exclusive
+	case *CommitStatement, *RollbackStatement, *SavepointStatement, *ReleaseStatement:
+		e.Transaction = true
+	case *AttachStatement, *DetachStatement:
+		e.Connection = true
+	case *PragmaStatement:
+		return c.pragma(stmt)
+	case *VacuumStatement:
+		e.Write = stmt.Expr == nil
+		e.Exclusive = stmt.Expr == nil
+	}
+	return e.Or(c.calls(stmt))
+}
+
+// pragma returns the effects of the pragma stmt.
+func (c *classifier) pragma(stmt *PragmaStatement) Effects {
+	var name *Ident
+	var value bool
+	switch expr := stmt.Expr.(type) {
+	case *Ident:
+		name = expr
+	case *Call:
+		name, value = expr.Name.Name, len(expr.Name.FunctionArgs) > 0
+	case *BinaryExpr:
+		name, _ = expr.X.(*Ident)
+		value = true
+	}
+	if name == nil {
+		return Effects{}
+	}
+
+	effects, ok := pragmaEffects[strings.ToLower(name.Name)]
+	if !value {
+		return effects[0]
+	} else if !ok {
+		return Effects{Connection: true}
+	}
+	return effects[1]
+}
+
+// calls returns the effects of the function calls within n.
+func (c *classifier) calls(n Node) Effects {
+	var e Effects
+	if n == nil || !n.node() {
+		return e
+	}
+
+	// Function calls & table-valued functions share the name node.
+	Walk(n, func(n Node) bool {
+		if name, ok := n.(*QualifiedName); ok && name.FunctionCall {
+			e = e.Or(c.functions[strings.ToLower(name.Name.Name)])
+		}
+		return true
+	})
+	return e
+}
@@ -0,0 +1,85 @@
+package sql_test
+
+import (
+	"testing"
+
+	"github.com/TcMits/sql"
+)
+
+func Test_Classify(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want string
+	}{
+		{`SELECT * FROM t WHERE x IN (SELECT y FROM u)`, "read-only"},
+		{`WITH x AS (SELECT 1) SELECT random(), abs(-1) FROM x`, "read-only"},
+		{`SELECT load_extension('ext')`, "connection"},
+		{`SELECT * FROM t WHERE writefile('out', x)`, "write"},
+		{`SELECT * FROM t, LOAD_EXTENSION('ext')`, "connection"},
+		{`INSERT INTO t VALUES (1)`, "write"},
+		{`REPLACE INTO t VALUES (1)`, "write"},
+		{`UPDATE t SET x = 1`, "write"},
+		{`DELETE FROM t RETURNING load_extension('ext')`, "write, connection"},
+		{`ANALYZE`, "write"},
+		{`REINDEX t`, "write"},
+		{`CREATE TABLE t (x DEFAULT (load_extension('ext')))`, "schema"},
+		{`CREATE TABLE t AS SELECT 1`, "write, schema"},
+		{`CREATE VIEW v AS SELECT load_extension('ext')`, "schema"},
+		{`CREATE TRIGGER tr AFTER INSERT ON t BEGIN DELETE FROM u; END`, "schema"},
+		{`CREATE INDEX i ON t (x)`, "schema"},
+		{`CREATE VIRTUAL TABLE t USING fts5(x)`, "schema"},
+		{`ALTER TABLE t RENAME TO u`, "schema"},
+		{`DROP TABLE t`, "schema"},
+		{`DROP TRIGGER tr`, "schema"},
+		{`BEGIN`, "transaction"},
+		{`BEGIN EXCLUSIVE`, "transaction, exclusive"},
+		{`COMMIT`, "transaction"},
+		{`END`, "transaction"},
+		{`ROLLBACK TO sp`, "transaction"},
+		{`SAVEPOINT sp`, "transaction"},
+		{`RELEASE sp`, "transaction"},
+		{`ATTACH 'other.db' AS other`, "connection"},
+		{`DETACH other`, "connection"},
+		{`PRAGMA journal_mode`, "read-only"},
+		{`PRAGMA journal_mode = WAL`, "write, connection"},
+		{`PRAGMA main.journal_mode = WAL`, "write, connection"},
+		{`PRAGMA foreign_keys = ON`, "connection"},
+		{`PRAGMA cache_size(-2000)`, "connection"},
+		{`PRAGMA table_info(t)`, "read-only"},
+		{`PRAGMA integrity_check`, "read-only"},
+		{`PRAGMA user_version = 2`, "write"},
+		{`PRAGMA optimize`, "write"},
+		{`PRAGMA wal_checkpoint(TRUNCATE)`, "write"},
+		{`VACUUM`, "write, exclusive"},
+		{`VACUUM INTO 'backup.db'`, "read-only"},
+		{`EXPLAIN SELECT 1`, "explain, read-only"},
+		{`EXPLAIN QUERY PLAN INSERT INTO t VALUES (1)`, "explain, write"},
+	} {
+		t.Run(tt.s, func(t *testing.T) {
+			stmt, err := sql.ParseStmtString(tt.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sql.Classify(stmt).String(); got != tt.want {
+				t.Fatalf("Classify()=%s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Classify_FunctionEffects(t *testing.T) {
+	stmt, err := sql.ParseStmtString(`SELECT nextval('seq'), load_extension('ext')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := sql.Classify(stmt, sql.FunctionEffects(map[string]sql.Effects{
+		"NEXTVAL":        {Write: true},
+		"load_extension": {},
+	}))
+	if got, want := e.String(), "write"; got != want {
+		t.Fatalf("Classify()=%s, want %s", got, want)
+	} else if e.ReadOnly() {
+		t.Fatal("expected statement not to be read-only")
+	}
+}
@@ -0,0 +1,1152 @@
+package sql
+
+// Clone returns a deep copy of n. Comments attached to n and its descendants
+// are copied as well. Clone returns nil if n is nil.
+func Clone(n Node) Node {
+	switch n := n.(type) {
+	case nil:
+		return nil
+	case *ExplainStatement:
+		return n.Clone()
+	case *BeginStatement:
+		return n.Clone()
+	case *CommitStatement:
+		return n.Clone()
+	case *RollbackStatement:
+		return n.Clone()
+	case *SavepointStatement:
+		return n.Clone()
+	case *ReleaseStatement:
+		return n.Clone()
+	case *CreateTableStatement:
+		return n.Clone()
+	case *ColumnDefinition:
+		return n.Clone()
+	case *PrimaryKeyConstraint:
+		return n.Clone()
+	case *NotNullConstraint:
+		return n.Clone()
+	case *UniqueConstraint:
+		return n.Clone()
+	case *CheckConstraint:
+		return n.Clone()
+	case *DefaultConstraint:
+		return n.Clone()
+	case *GeneratedConstraint:
+		return n.Clone()
+	case *CollateConstraint:
+		return n.Clone()
+	case *ForeignKeyConstraint:
+		return n.Clone()
+	case *ForeignKeyArg:
+		return n.Clone()
+	case *CreateVirtualTableStatement:
+		return n.Clone()
+	case *ModuleArgument:
+		return n.Clone()
+	case *AnalyzeStatement:
+		return n.Clone()
+	case *ReindexStatement:
+		return n.Clone()
+	case *AlterTableStatement:
+		return n.Clone()
+	case *Ident:
+		return n.Clone()
+	case *Type:
+		return n.Clone()
+	case *StringLit:
+		return n.Clone()
+	case *TimestampLit:
+		return n.Clone()
+	case *BlobLit:
+		return n.Clone()
+	case *NumberLit:
+		return n.Clone()
+	case *NullLit:
+		return n.Clone()
+	case *BoolLit:
+		return n.Clone()
+	case *BindExpr:
+		return n.Clone()
+	case *UnaryExpr:
+		return n.Clone()
+	case *BinaryExpr:
+		return n.Clone()
+	case *CastExpr:
+		return n.Clone()
+	case *CaseExpr:
+		return n.Clone()
+	case *CaseBlock:
+		return n.Clone()
+	case *Raise:
+		return n.Clone()
+	case *Exists:
+		return n.Clone()
+	case *Null:
+		return n.Clone()
+	case *ExprList:
+		return n.Clone()
+	case *QualifiedRef:
+		return n.Clone()
+	case *Call:
+		return n.Clone()
+	case *OrderingTerm:
+		return n.Clone()
+	case *FrameSpec:
+		return n.Clone()
+	case *DropTableStatement:
+		return n.Clone()
+	case *CreateViewStatement:
+		return n.Clone()
+	case *DropViewStatement:
+		return n.Clone()
+	case *CreateIndexStatement:
+		return n.Clone()
+	case *DropIndexStatement:
+		return n.Clone()
+	case *CreateTriggerStatement:
+		return n.Clone()
+	case *DropTriggerStatement:
+		return n.Clone()
+	case *InsertStatement:
+		return n.Clone()
+	case *UpsertClause:
+		return n.Clone()
+	case *UpdateStatement:
+		return n.Clone()
+	case *DeleteStatement:
+		return n.Clone()
+	case *Assignment:
+		return n.Clone()
+	case *IndexedColumn:
+		return n.Clone()
+	case *SelectStatement:
+		return n.Clone()
+	case *ResultColumn:
+		return n.Clone()
+	case *QualifiedName:
+		return n.Clone()
+	case *ParenSource:
+		return n.Clone()
+	case *JoinClause:
+		return n.Clone()
+	case *JoinOperator:
+		return n.Clone()
+	case *OnConstraint:
+		return n.Clone()
+	case *UsingConstraint:
+		return n.Clone()
+	case *WithClause:
+		return n.Clone()
+	case *CTE:
+		return n.Clone()
+	case *Window:
+		return n.Clone()
+	case *WindowDefinition:
+		return n.Clone()
+	case *PragmaStatement:
+		return n.Clone()
+	case *AttachStatement:
+		return n.Clone()
+	case *DetachStatement:
+		return n.Clone()
+	case *VacuumStatement:
+		return n.Clone()
+	case *ConflictClause:
+		return n.Clone()
+	case *FunctionArg:
+		return n.Clone()
+	case *InExpr:
+		return n.Clone()
+	case *ParenExpr:
+		return n.Clone()
+	default:
+		panic("sql.Clone: unexpected node type")
+	}
+}
+
+func cloneExpr(expr Expr) Expr {
+	if expr == nil {
+		return nil
+	}
+	return Clone(expr).(Expr)
+}
+
+func cloneSource(src Source) Source {
+	if src == nil {
+		return nil
+	}
+	return Clone(src).(Source)
+}
+
+func cloneStatement(stmt Statement) Statement {
+	if stmt == nil {
+		return nil
+	}
+	return Clone(stmt).(Statement)
+}
+
+func cloneConstraint(cons Constraint) Constraint {
+	if cons == nil {
+		return nil
+	}
+	return Clone(cons).(Constraint)
+}
+
+func cloneJoinConstraint(cons JoinConstraint) JoinConstraint {
+	if cons == nil {
+		return nil
+	}
+	return Clone(cons).(JoinConstraint)
+}
+
+// cloneSlice returns a new slice with each element of a copied by fn.
+func cloneSlice[T any](a []T, fn func(T) T) []T {
+	if a == nil {
+		return nil
+	}
+	other := make([]T, len(a))
+	for i := range a {
+		other[i] = fn(a[i])
+	}
+	return other
+}
+
+// Clone returns a deep copy of s.
+func (s *ExplainStatement) Clone() *ExplainStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Stmt = cloneStatement(s.Stmt)
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *BeginStatement) Clone() *BeginStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *CommitStatement) Clone() *CommitStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *RollbackStatement) Clone() *RollbackStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.SavepointName = s.SavepointName.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *SavepointStatement) Clone() *SavepointStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *ReleaseStatement) Clone() *ReleaseStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *CreateTableStatement) Clone() *CreateTableStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	other.Columns = cloneSlice(s.Columns, (*ColumnDefinition).Clone)
+	other.Constraints = cloneSlice(s.Constraints, cloneConstraint)
+	other.Select = s.Select.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *ColumnDefinition) Clone() *ColumnDefinition {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Name = c.Name.Clone()
+	other.Type = c.Type.Clone()
+	other.Constraints = cloneSlice(c.Constraints, cloneConstraint)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *PrimaryKeyConstraint) Clone() *PrimaryKeyConstraint {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Name = c.Name.Clone()
+	other.Conflict = c.Conflict.Clone()
+	other.Columns = cloneSlice(c.Columns, (*Ident).Clone)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *NotNullConstraint) Clone() *NotNullConstraint {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Name = c.Name.Clone()
+	other.Conflict = c.Conflict.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *UniqueConstraint) Clone() *UniqueConstraint {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Name = c.Name.Clone()
+	other.Conflict = c.Conflict.Clone()
+	other.Columns = cloneSlice(c.Columns, (*IndexedColumn).Clone)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *CheckConstraint) Clone() *CheckConstraint {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Name = c.Name.Clone()
+	other.Expr = cloneExpr(c.Expr)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *DefaultConstraint) Clone() *DefaultConstraint {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Name = c.Name.Clone()
+	other.Expr = cloneExpr(c.Expr)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *GeneratedConstraint) Clone() *GeneratedConstraint {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Name = c.Name.Clone()
+	other.Expr = cloneExpr(c.Expr)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *CollateConstraint) Clone() *CollateConstraint {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Name = c.Name.Clone()
+	other.Collation = c.Collation.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *ForeignKeyConstraint) Clone() *ForeignKeyConstraint {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Name = c.Name.Clone()
+	other.Columns = cloneSlice(c.Columns, (*Ident).Clone)
+	other.ForeignTable = c.ForeignTable.Clone()
+	other.ForeignColumns = cloneSlice(c.ForeignColumns, (*Ident).Clone)
+	other.Args = cloneSlice(c.Args, (*ForeignKeyArg).Clone)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *ForeignKeyArg) Clone() *ForeignKeyArg {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *CreateVirtualTableStatement) Clone() *CreateVirtualTableStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	other.ModuleName = s.ModuleName.Clone()
+	other.Arguments = cloneSlice(s.Arguments, (*ModuleArgument).Clone)
+	return &other
+}
+
+// Clone returns a deep copy of a.
+func (a *ModuleArgument) Clone() *ModuleArgument {
+	if a == nil {
+		return nil
+	}
+	other := *a
+	other.comments = a.comments.clone()
+	other.Name = a.Name.Clone()
+	other.Literal = cloneExpr(a.Literal)
+	other.Type = a.Type.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *AnalyzeStatement) Clone() *AnalyzeStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *ReindexStatement) Clone() *ReindexStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *AlterTableStatement) Clone() *AlterTableStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	other.NewName = s.NewName.Clone()
+	other.ColumnName = s.ColumnName.Clone()
+	other.NewColumnName = s.NewColumnName.Clone()
+	other.ColumnDef = s.ColumnDef.Clone()
+	other.DropColumnName = s.DropColumnName.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of i.
+func (i *Ident) Clone() *Ident {
+	if i == nil {
+		return nil
+	}
+	other := *i
+	other.comments = i.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of t.
+func (t *Type) Clone() *Type {
+	if t == nil {
+		return nil
+	}
+	other := *t
+	other.comments = t.comments.clone()
+	other.Name = t.Name.Clone()
+	other.Precision = t.Precision.Clone()
+	other.Scale = t.Scale.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of lit.
+func (lit *StringLit) Clone() *StringLit {
+	if lit == nil {
+		return nil
+	}
+	other := *lit
+	other.comments = lit.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of lit.
+func (lit *TimestampLit) Clone() *TimestampLit {
+	if lit == nil {
+		return nil
+	}
+	other := *lit
+	other.comments = lit.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of lit.
+func (lit *BlobLit) Clone() *BlobLit {
+	if lit == nil {
+		return nil
+	}
+	other := *lit
+	other.comments = lit.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of lit.
+func (lit *NumberLit) Clone() *NumberLit {
+	if lit == nil {
+		return nil
+	}
+	other := *lit
+	other.comments = lit.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of lit.
+func (lit *NullLit) Clone() *NullLit {
+	if lit == nil {
+		return nil
+	}
+	other := *lit
+	other.comments = lit.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of lit.
+func (lit *BoolLit) Clone() *BoolLit {
+	if lit == nil {
+		return nil
+	}
+	other := *lit
+	other.comments = lit.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of expr.
+func (expr *BindExpr) Clone() *BindExpr {
+	if expr == nil {
+		return nil
+	}
+	other := *expr
+	other.comments = expr.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of expr.
+func (expr *UnaryExpr) Clone() *UnaryExpr {
+	if expr == nil {
+		return nil
+	}
+	other := *expr
+	other.comments = expr.comments.clone()
+	other.X = cloneExpr(expr.X)
+	return &other
+}
+
+// Clone returns a deep copy of expr.
+func (expr *BinaryExpr) Clone() *BinaryExpr {
+	if expr == nil {
+		return nil
+	}
+	other := *expr
+	other.comments = expr.comments.clone()
+	other.X = cloneExpr(expr.X)
+	other.Y = cloneExpr(expr.Y)
+	return &other
+}
+
+// Clone returns a deep copy of expr.
+func (expr *CastExpr) Clone() *CastExpr {
+	if expr == nil {
+		return nil
+	}
+	other := *expr
+	other.comments = expr.comments.clone()
+	other.X = cloneExpr(expr.X)
+	other.Type = expr.Type.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of expr.
+func (expr *CaseExpr) Clone() *CaseExpr {
+	if expr == nil {
+		return nil
+	}
+	other := *expr
+	other.comments = expr.comments.clone()
+	other.Operand = cloneExpr(expr.Operand)
+	other.Blocks = cloneSlice(expr.Blocks, (*CaseBlock).Clone)
+	other.ElseExpr = cloneExpr(expr.ElseExpr)
+	return &other
+}
+
+// Clone returns a deep copy of b.
+func (b *CaseBlock) Clone() *CaseBlock {
+	if b == nil {
+		return nil
+	}
+	other := *b
+	other.comments = b.comments.clone()
+	other.Condition = cloneExpr(b.Condition)
+	other.Body = cloneExpr(b.Body)
+	return &other
+}
+
+// Clone returns a deep copy of r.
+func (r *Raise) Clone() *Raise {
+	if r == nil {
+		return nil
+	}
+	other := *r
+	other.comments = r.comments.clone()
+	other.Error = r.Error.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of expr.
+func (expr *Exists) Clone() *Exists {
+	if expr == nil {
+		return nil
+	}
+	other := *expr
+	other.comments = expr.comments.clone()
+	other.Select = expr.Select.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of expr.
+func (expr *Null) Clone() *Null {
+	if expr == nil {
+		return nil
+	}
+	other := *expr
+	other.comments = expr.comments.clone()
+	other.X = cloneExpr(expr.X)
+	return &other
+}
+
+// Clone returns a deep copy of l.
+func (l *ExprList) Clone() *ExprList {
+	if l == nil {
+		return nil
+	}
+	other := *l
+	other.comments = l.comments.clone()
+	other.Exprs = cloneSlice(l.Exprs, cloneExpr)
+	return &other
+}
+
+// Clone returns a deep copy of r.
+func (r *QualifiedRef) Clone() *QualifiedRef {
+	if r == nil {
+		return nil
+	}
+	other := *r
+	other.comments = r.comments.clone()
+	other.Table = r.Table.Clone()
+	other.Column = r.Column.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *Call) Clone() *Call {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Name = c.Name.Clone()
+	other.Filter = cloneExpr(c.Filter)
+	other.OverName = c.OverName.Clone()
+	other.OverWindow = c.OverWindow.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of t.
+func (t *OrderingTerm) Clone() *OrderingTerm {
+	if t == nil {
+		return nil
+	}
+	other := *t
+	other.comments = t.comments.clone()
+	other.X = cloneExpr(t.X)
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *FrameSpec) Clone() *FrameSpec {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.X = cloneExpr(s.X)
+	other.Y = cloneExpr(s.Y)
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *DropTableStatement) Clone() *DropTableStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *CreateViewStatement) Clone() *CreateViewStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	other.Columns = cloneSlice(s.Columns, (*Ident).Clone)
+	other.Select = s.Select.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *DropViewStatement) Clone() *DropViewStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *CreateIndexStatement) Clone() *CreateIndexStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	other.Table = s.Table.Clone()
+	other.Columns = cloneSlice(s.Columns, (*IndexedColumn).Clone)
+	other.WhereExpr = cloneExpr(s.WhereExpr)
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *DropIndexStatement) Clone() *DropIndexStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *CreateTriggerStatement) Clone() *CreateTriggerStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	other.UpdateOfColumns = cloneSlice(s.UpdateOfColumns, (*Ident).Clone)
+	other.Table = s.Table.Clone()
+	other.WhenExpr = cloneExpr(s.WhenExpr)
+	other.Body = cloneSlice(s.Body, cloneStatement)
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *DropTriggerStatement) Clone() *DropTriggerStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *InsertStatement) Clone() *InsertStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.WithClause = s.WithClause.Clone()
+	other.Table = s.Table.Clone()
+	other.Columns = cloneSlice(s.Columns, (*Ident).Clone)
+	other.ValueLists = cloneSlice(s.ValueLists, (*ExprList).Clone)
+	other.Select = s.Select.Clone()
+	other.UpsertClause = s.UpsertClause.Clone()
+	other.ReturningColumns = cloneSlice(s.ReturningColumns, (*ResultColumn).Clone)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *UpsertClause) Clone() *UpsertClause {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Columns = cloneSlice(c.Columns, (*IndexedColumn).Clone)
+	other.WhereExpr = cloneExpr(c.WhereExpr)
+	other.Assignments = cloneSlice(c.Assignments, (*Assignment).Clone)
+	other.UpdateWhereExpr = cloneExpr(c.UpdateWhereExpr)
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *UpdateStatement) Clone() *UpdateStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.WithClause = s.WithClause.Clone()
+	other.Table = s.Table.Clone()
+	other.Assignments = cloneSlice(s.Assignments, (*Assignment).Clone)
+	other.Source = cloneSource(s.Source)
+	other.WhereExpr = cloneExpr(s.WhereExpr)
+	other.ReturningColumns = cloneSlice(s.ReturningColumns, (*ResultColumn).Clone)
+	other.OrderingTerms = cloneSlice(s.OrderingTerms, (*OrderingTerm).Clone)
+	other.LimitExpr = cloneExpr(s.LimitExpr)
+	other.OffsetExpr = cloneExpr(s.OffsetExpr)
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *DeleteStatement) Clone() *DeleteStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.WithClause = s.WithClause.Clone()
+	other.Table = s.Table.Clone()
+	other.WhereExpr = cloneExpr(s.WhereExpr)
+	other.ReturningColumns = cloneSlice(s.ReturningColumns, (*ResultColumn).Clone)
+	other.OrderingTerms = cloneSlice(s.OrderingTerms, (*OrderingTerm).Clone)
+	other.LimitExpr = cloneExpr(s.LimitExpr)
+	other.OffsetExpr = cloneExpr(s.OffsetExpr)
+	return &other
+}
+
+// Clone returns a deep copy of a.
+func (a *Assignment) Clone() *Assignment {
+	if a == nil {
+		return nil
+	}
+	other := *a
+	other.comments = a.comments.clone()
+	other.Columns = cloneSlice(a.Columns, (*Ident).Clone)
+	other.Expr = cloneExpr(a.Expr)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *IndexedColumn) Clone() *IndexedColumn {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.X = cloneExpr(c.X)
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *SelectStatement) Clone() *SelectStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.WithClause = s.WithClause.Clone()
+	other.ValueLists = cloneSlice(s.ValueLists, (*ExprList).Clone)
+	other.Columns = cloneSlice(s.Columns, (*ResultColumn).Clone)
+	other.Source = cloneSource(s.Source)
+	other.WhereExpr = cloneExpr(s.WhereExpr)
+	other.GroupByExprs = cloneSlice(s.GroupByExprs, cloneExpr)
+	other.HavingExpr = cloneExpr(s.HavingExpr)
+	other.Windows = cloneSlice(s.Windows, (*Window).Clone)
+	other.Compound = s.Compound.Clone()
+	other.OrderingTerms = cloneSlice(s.OrderingTerms, (*OrderingTerm).Clone)
+	other.LimitExpr = cloneExpr(s.LimitExpr)
+	other.OffsetExpr = cloneExpr(s.OffsetExpr)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *ResultColumn) Clone() *ResultColumn {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Expr = cloneExpr(c.Expr)
+	other.Alias = c.Alias.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of n.
+func (n *QualifiedName) Clone() *QualifiedName {
+	if n == nil {
+		return nil
+	}
+	other := *n
+	other.comments = n.comments.clone()
+	other.Schema = n.Schema.Clone()
+	other.Name = n.Name.Clone()
+	other.FunctionArgs = cloneSlice(n.FunctionArgs, (*FunctionArg).Clone)
+	other.Alias = n.Alias.Clone()
+	other.Index = n.Index.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *ParenSource) Clone() *ParenSource {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.X = cloneSource(s.X)
+	other.Alias = s.Alias.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *JoinClause) Clone() *JoinClause {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.X = cloneSource(c.X)
+	other.Operator = c.Operator.Clone()
+	other.Y = cloneSource(c.Y)
+	other.Constraint = cloneJoinConstraint(c.Constraint)
+	return &other
+}
+
+// Clone returns a deep copy of op.
+func (op *JoinOperator) Clone() *JoinOperator {
+	if op == nil {
+		return nil
+	}
+	other := *op
+	other.comments = op.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *OnConstraint) Clone() *OnConstraint {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.X = cloneExpr(c.X)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *UsingConstraint) Clone() *UsingConstraint {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.Columns = cloneSlice(c.Columns, (*Ident).Clone)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *WithClause) Clone() *WithClause {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	other.CTEs = cloneSlice(c.CTEs, (*CTE).Clone)
+	return &other
+}
+
+// Clone returns a deep copy of cte.
+func (cte *CTE) Clone() *CTE {
+	if cte == nil {
+		return nil
+	}
+	other := *cte
+	other.comments = cte.comments.clone()
+	other.TableName = cte.TableName.Clone()
+	other.Columns = cloneSlice(cte.Columns, (*Ident).Clone)
+	other.Select = cte.Select.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of w.
+func (w *Window) Clone() *Window {
+	if w == nil {
+		return nil
+	}
+	other := *w
+	other.comments = w.comments.clone()
+	other.Name = w.Name.Clone()
+	other.Definition = w.Definition.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of d.
+func (d *WindowDefinition) Clone() *WindowDefinition {
+	if d == nil {
+		return nil
+	}
+	other := *d
+	other.comments = d.comments.clone()
+	other.Base = d.Base.Clone()
+	other.Partitions = cloneSlice(d.Partitions, cloneExpr)
+	other.OrderingTerms = cloneSlice(d.OrderingTerms, (*OrderingTerm).Clone)
+	other.Frame = d.Frame.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *PragmaStatement) Clone() *PragmaStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Schema = s.Schema.Clone()
+	other.Expr = cloneExpr(s.Expr)
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *AttachStatement) Clone() *AttachStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Expr = cloneExpr(s.Expr)
+	other.Schema = s.Schema.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *DetachStatement) Clone() *DetachStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Schema = s.Schema.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of s.
+func (s *VacuumStatement) Clone() *VacuumStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.comments = s.comments.clone()
+	other.Schema = s.Schema.Clone()
+	other.Expr = cloneExpr(s.Expr)
+	return &other
+}
+
+// Clone returns a deep copy of c.
+func (c *ConflictClause) Clone() *ConflictClause {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.comments = c.comments.clone()
+	return &other
+}
+
+// Clone returns a deep copy of a.
+func (a *FunctionArg) Clone() *FunctionArg {
+	if a == nil {
+		return nil
+	}
+	other := *a
+	other.comments = a.comments.clone()
+	other.Expr = cloneExpr(a.Expr)
+	other.OrderingTerms = cloneSlice(a.OrderingTerms, (*OrderingTerm).Clone)
+	return &other
+}
+
+// Clone returns a deep copy of e.
+func (e *InExpr) Clone() *InExpr {
+	if e == nil {
+		return nil
+	}
+	other := *e
+	other.comments = e.comments.clone()
+	other.X = cloneExpr(e.X)
+	other.Select = e.Select.Clone()
+	other.Values = e.Values.Clone()
+	other.TableOrFunction = e.TableOrFunction.Clone()
+	return &other
+}
+
+// Clone returns a deep copy of e.
+func (e *ParenExpr) Clone() *ParenExpr {
+	if e == nil {
+		return nil
+	}
+	other := *e
+	other.comments = e.comments.clone()
+	other.Expr = cloneExpr(e.Expr)
+	return &other
+}
@@ -0,0 +1,98 @@
+package sql_test
+
+import (
+	"testing"
+
+	"github.com/TcMits/sql"
+	"github.com/go-test/deep"
+)
+
+func Test_Clone(t *testing.T) {
+	for _, s := range []string{
+		`WITH x AS MATERIALIZED (SELECT a FROM t) SELECT DISTINCT x.a, count(*) FILTER (WHERE b > 1) OVER w FROM x LEFT JOIN y USING (a) WHERE a IN (1, 2) GROUP BY a HAVING count(*) > 1 WINDOW w AS (PARTITION BY a) ORDER BY 1 DESC LIMIT 10 OFFSET 2`,
+		`VALUES (1, 'a'), (2, 'b') UNION ALL SELECT 3, 'c'`,
+		`INSERT INTO t (a, b) VALUES (1, 2), (3, 4) ON CONFLICT (a) DO UPDATE SET b = excluded.b WHERE b IS NOT NULL RETURNING *`,
+		`UPDATE OR IGNORE t SET a = s.a FROM s WHERE t.id = s.id`,
+		`DELETE FROM t WHERE a = ? ORDER BY b LIMIT 1`,
+		`CREATE TABLE IF NOT EXISTS t (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL DEFAULT 'x' CHECK (name <> ''), p INTEGER REFERENCES p (id) ON DELETE CASCADE, UNIQUE (name) ON CONFLICT REPLACE) WITHOUT ROWID`,
+		`CREATE INDEX i ON t (a COLLATE NOCASE DESC, b) WHERE a > 0`,
+		`CREATE TRIGGER tr AFTER UPDATE OF a ON t FOR EACH ROW WHEN new.a > 0 BEGIN INSERT INTO log VALUES (new.a); DELETE FROM t2 WHERE x = old.a; END`,
+		`CREATE VIEW v (a) AS SELECT CASE WHEN a THEN b ELSE c END FROM t`,
+		`CREATE VIRTUAL TABLE v USING fts5(a, b)`,
+		`ALTER TABLE t RENAME COLUMN a TO b`,
+		`EXPLAIN QUERY PLAN SELECT CAST(a AS INTEGER) FROM t`,
+		`ATTACH DATABASE 'file.db' AS other`,
+	} {
+		t.Run(s, func(t *testing.T) {
+			stmt, err := sql.ParseStmtString(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			other := sql.Clone(stmt).(sql.Statement)
+			if diff := deep.Equal(other, stmt); diff != nil {
+				t.Fatal(diff)
+			}
+			if got, want := other.String(), stmt.String(); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+
+			// No node may be shared between the original and the copy.
+			seen := make(map[sql.Node]bool)
+			sql.Walk(stmt, func(n sql.Node) bool {
+				seen[n] = true
+				return true
+			})
+			sql.Walk(other, func(n sql.Node) bool {
+				if seen[n] {
+					t.Fatalf("node %T shared with original", n)
+				}
+				return true
+			})
+		})
+	}
+}
+
+func Test_Clone_Independent(t *testing.T) {
+	stmt, err := sql.ParseStmtString(`VALUES (1, 2), (3, 4)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sel := stmt.(*sql.SelectStatement)
+	other := sel.Clone()
+	other.ValueLists[0].Exprs[0] = &sql.NumberLit{Value: "5"}
+	other.ValueLists = append(other.ValueLists[:1], other.ValueLists[2:]...)
+	AssertStatementStringer(t, sel, `VALUES (1, 2), (3, 4)`)
+	AssertStatementStringer(t, other, `VALUES (5, 2)`)
+
+	stmt, err = sql.ParseStmtString(`CREATE TRIGGER tr AFTER INSERT ON t BEGIN DELETE FROM a; END`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trig := stmt.(*sql.CreateTriggerStatement)
+	otherTrig := trig.Clone()
+	otherTrig.Body[0].(*sql.DeleteStatement).Table.Name.Name = "b"
+	AssertStatementStringer(t, trig, `CREATE TRIGGER "tr" AFTER INSERT ON "t" BEGIN DELETE FROM "a"; END`)
+	AssertStatementStringer(t, otherTrig, `CREATE TRIGGER "tr" AFTER INSERT ON "t" BEGIN DELETE FROM "b"; END`)
+}
+
+func Test_Clone_Comments(t *testing.T) {
+	stmt, err := sql.ParseStmtString("-- drop it\nDROP TABLE foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := sql.Clone(stmt).(*sql.DropTableStatement)
+	other.LeadingComments().List[0].Text = "-- keep it"
+	AssertStatementStringer(t, stmt, "-- drop it\nDROP TABLE \"foo\"")
+	AssertStatementStringer(t, other, "-- keep it\nDROP TABLE \"foo\"")
+
+	if sql.Clone(nil) != nil {
+		t.Fatal("expected nil")
+	}
+	if (*sql.SelectStatement)(nil).Clone() != nil {
+		t.Fatal("expected nil")
+	}
+}
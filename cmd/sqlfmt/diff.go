@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines printed around each change.
+const diffContext = 3
+
+// unifiedDiff returns the unified diff of the lines of old and new, labeled
+// with oldName and newName. It returns nil if old and new are equal.
+func unifiedDiff(oldName, newName, old, new string) []byte {
+	if old == new {
+		return nil
+	}
+
+	a, b := splitLines(old), splitLines(new)
+	ops := diffLines(a, b)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "diff -u %s %s\n", oldName, newName)
+	fmt.Fprintf(&buf, "--- %s\n", oldName)
+	fmt.Fprintf(&buf, "+++ %s\n", newName)
+
+	for i := 0; i < len(ops); {
+		// Skip to the next change.
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// Extend the hunk while changes are separated by few enough unchanged
+		// lines to share their context.
+		start := max(i-diffContext, 0)
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			} else if j-end >= 2*diffContext {
+				break
+			}
+		}
+		end = min(end+diffContext, len(ops))
+
+		var aStart, aLen, bStart, bLen int
+		for _, op := range ops[:start] {
+			if op.kind != '+' {
+				aStart++
+			}
+			if op.kind != '-' {
+				bStart++
+			}
+		}
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				aLen++
+			}
+			if op.kind != '-' {
+				bLen++
+			}
+		}
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+		for _, op := range ops[start:end] {
+			buf.WriteByte(op.kind)
+			buf.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return buf.Bytes()
+}
+
+// hunkRange formats the line range of a hunk. Line numbers are 1-based
+// except for an empty range, which refers to the line before it.
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if n == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+// splitLines splits s after each newline.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffOp is a line of a diff: ' ' for a common line, '-' for a line only
+// in the old text and '+' for a line only in the new text.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// diffLines returns a shortest edit script turning a into b.
+func diffLines(a, b []string) []diffOp {
+	return appendDiff(nil, a, b)
+}
+
+// appendDiff appends the edit script turning a into b to ops. It uses the
+// linear space variant of Myers' O(ND) algorithm: the common prefix & suffix
+// are trimmed, then the middle of a shortest edit script splits the rest in
+// two halves which are diffed in turn.
+func appendDiff(ops []diffOp, a, b []string) []diffOp {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		ops = append(ops, diffOp{' ', a[0]})
+		a, b = a[1:], b[1:]
+	}
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	suffix := a[len(a)-n:]
+	a, b = a[:len(a)-n], b[:len(b)-n]
+
+	if x, y, ok := bisect(a, b); ok {
+		ops = appendDiff(ops, a[:x], b[:y])
+		ops = appendDiff(ops, a[x:], b[y:])
+	} else {
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+	}
+
+	for _, line := range suffix {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// bisect returns the point where the forward & reverse searches for a
+// shortest edit script of a and b meet. It returns false if a and b have no
+// line in common, or either is empty.
+//
+// See: Myers, "An O(ND) Difference Algorithm and Its Variations" (1986).
+func bisect(a, b []string) (x, y int, ok bool) {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return 0, 0, false
+	}
+
+	// v1 & v2 hold the furthest x reached on each diagonal k by the forward
+	// & reverse searches, offset by maxD.
+	maxD := (n + m + 1) / 2
+	v1 := make([]int, 2*maxD+2)
+	v2 := make([]int, 2*maxD+2)
+	for i := range v1 {
+		v1[i], v2[i] = -1, -1
+	}
+	v1[maxD+1], v2[maxD+1] = 0, 0
+
+	// If the difference of lengths is odd, the searches meet while moving
+	// forward, else while moving in reverse.
+	delta := n - m
+	front := delta%2 != 0
+
+	// Diagonals leaving the edit graph are skipped.
+	var k1start, k1end, k2start, k2end int
+	for d := 0; d < maxD; d++ {
+		for k1 := -d + k1start; k1 <= d-k1end; k1 += 2 {
+			i := maxD + k1
+			var x1 int
+			if k1 == -d || (k1 != d && v1[i-1] < v1[i+1]) {
+				x1 = v1[i+1]
+			} else {
+				x1 = v1[i-1] + 1
+			}
+			y1 := x1 - k1
+			for x1 < n && y1 < m && a[x1] == b[y1] {
+				x1, y1 = x1+1, y1+1
+			}
+			v1[i] = x1
+
+			if x1 > n {
+				k1end += 2
+			} else if y1 > m {
+				k1start += 2
+			} else if front {
+				if j := maxD + delta - k1; j >= 0 && j < len(v2) && v2[j] != -1 && x1 >= n-v2[j] {
+					return x1, y1, true
+				}
+			}
+		}
+
+		for k2 := -d + k2start; k2 <= d-k2end; k2 += 2 {
+			i := maxD + k2
+			var x2 int
+			if k2 == -d || (k2 != d && v2[i-1] < v2[i+1]) {
+				x2 = v2[i+1]
+			} else {
+				x2 = v2[i-1] + 1
+			}
+			y2 := x2 - k2
+			for x2 < n && y2 < m && a[n-x2-1] == b[m-y2-1] {
+				x2, y2 = x2+1, y2+1
+			}
+			v2[i] = x2
+
+			if x2 > n {
+				k2end += 2
+			} else if y2 > m {
+				k2start += 2
+			} else if !front {
+				if j := maxD + delta - k2; j >= 0 && j < len(v1) && v1[j] != -1 && v1[j] >= n-x2 {
+					x1 := v1[j]
+					return x1, x1 - (j - maxD), true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
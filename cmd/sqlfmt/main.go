@@ -0,0 +1,242 @@
+// Command sqlfmt formats SQL source files.
+//
+// Without an explicit path, it processes the standard input. Given a file, it
+// operates on that file; given a directory, it operates on all .sql files in
+// that directory, recursively. By default, sqlfmt prints the reformatted
+// sources to standard output.
+//
+// Usage:
+//
+//	sqlfmt [flags] [path ...]
+//
+// The flags are:
+//
+//	-d
+//		Do not print reformatted sources to standard output.
+//		If a file's formatting is different than sqlfmt's, print diffs
+//		to standard output.
+//	-l
+//		Do not print reformatted sources to standard output.
+//		If a file's formatting is different from sqlfmt's, print its name
+//		to standard output.
+//	-w
+//		Do not print reformatted sources to standard output.
+//		If a file's formatting is different from sqlfmt's, overwrite it
+//		with sqlfmt's version.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TcMits/sql"
+)
+
+var (
+	list  = flag.Bool("l", false, "list files whose formatting differs from sqlfmt's")
+	write = flag.Bool("w", false, "write result to (source) file instead of stdout")
+	diff  = flag.Bool("d", false, "display diffs instead of rewriting files")
+)
+
+var exitCode = 0
+
+func report(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	exitCode = 2
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: sqlfmt [flags] [path ...]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		if *write {
+			report(errors.New("error: cannot use -w with standard input"))
+		} else if err := processFile("<standard input>", os.Stdin, os.Stdout); err != nil {
+			report(err)
+		}
+		os.Exit(exitCode)
+	}
+
+	for _, path := range flag.Args() {
+		switch info, err := os.Stat(path); {
+		case err != nil:
+			report(err)
+		case info.IsDir():
+			walkDir(path)
+		default:
+			if err := processFile(path, nil, os.Stdout); err != nil {
+				report(err)
+			}
+		}
+	}
+	os.Exit(exitCode)
+}
+
+func walkDir(root string) {
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			report(err)
+			return nil
+		}
+		if d.IsDir() || !isSQLFile(d.Name()) {
+			return nil
+		}
+		if err := processFile(path, nil, os.Stdout); err != nil {
+			report(err)
+		}
+		return nil
+	})
+	if err != nil {
+		report(err)
+	}
+}
+
+func isSQLFile(name string) bool {
+	return !strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".sql")
+}
+
+// processFile formats the file at filename, reading it from in if non-nil,
+// and writes the result to out according to the flags.
+func processFile(filename string, in io.Reader, out io.Writer) error {
+	if in == nil {
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	src, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	res, err := format(string(src))
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	if res != string(src) {
+		if *list {
+			fmt.Fprintln(out, filename)
+		}
+		if *write {
+			info, err := os.Stat(filename)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filename, []byte(res), info.Mode().Perm()); err != nil {
+				return err
+			}
+		}
+		if *diff {
+			out.Write(unifiedDiff(filename+".orig", filename, string(src), res))
+		}
+	}
+
+	if !*list && !*write && !*diff {
+		_, err = io.WriteString(out, res)
+	}
+	return err
+}
+
+// format returns the canonical form of the SQL statements in src. Statements
+// are terminated by a semicolon and separated by a blank line.
+func format(src string) (string, error) {
+	var buf bytes.Buffer
+	var stmts []sql.Statement
+	err := sql.ParseMultiStmtString(src, func(stmt sql.Statement) error {
+		stmts = append(stmts, stmt)
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+
+		// Print comments trailing the statement after its semicolon.
+		trailing := stmt.TrailingComments()
+		if trailing != nil {
+			stmt.(interface{ SetTrailingComments(*sql.CommentGroup) }).SetTrailingComments(nil)
+		}
+
+		// A line comment at the very end pushes the semicolon to its own line.
+		buf.WriteString(sql.Format(stmt, sql.FormatOptions{}))
+		buf.WriteString(";")
+		if trailing != nil {
+			for i, c := range trailing.List {
+				if i > 0 && strings.HasPrefix(trailing.List[i-1].Text, "--") {
+					buf.WriteString("\n")
+				} else {
+					buf.WriteString(" ")
+				}
+				buf.WriteString(c.Text)
+			}
+		}
+		buf.WriteString("\n")
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Leave files without statements, e.g. only comments, untouched.
+	if buf.Len() == 0 {
+		return src, nil
+	}
+
+	// Refuse to drop comments the parser could not attach to a statement.
+	if n, m := countComments(src), countComments(buf.String()); n != m {
+		return "", fmt.Errorf("formatting would drop %d comment(s)", n-m)
+	}
+	if err := verify(stmts, buf.String()); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// verify returns an error unless out parses into statements equal to stmts,
+// ignoring positions and identifier quoting.
+func verify(stmts []sql.Statement, out string) error {
+	var i int
+	err := sql.ParseMultiStmtString(out, func(stmt sql.Statement) error {
+		if i >= len(stmts) {
+			return fmt.Errorf("formatting would add a statement")
+		} else if !sql.Equal(stmts[i], stmt, sql.IgnorePositions(), sql.IgnoreQuoting()) {
+			return fmt.Errorf("formatting would change statement %d", i+1)
+		}
+		i++
+		return nil
+	})
+	if err != nil {
+		return err
+	} else if i != len(stmts) {
+		return fmt.Errorf("formatting would drop %d statement(s)", len(stmts)-i)
+	}
+	return nil
+}
+
+func countComments(src string) int {
+	var n int
+	s := sql.NewScanner(src)
+	for {
+		_, tok, _ := s.Scan()
+		switch tok {
+		case sql.EOF:
+			return n
+		case sql.COMMENT:
+			n++
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/TcMits/sql"
+)
+
+func TestFormat(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"select a,b from t", "SELECT \"a\", \"b\" FROM \"t\";\n"},
+		{"SELECT 1; SELECT 2;", "SELECT 1;\n\nSELECT 2;\n"},
+		{"-- first\nSELECT 1; -- one\nSELECT 2 -- two\n", "-- first\nSELECT 1; -- one\n\nSELECT 2; -- two\n"},
+		{"BEGIN;\nUPDATE t SET a = 1;\nEND;", "BEGIN;\n\nUPDATE \"t\" SET \"a\" = 1;\n\nEND;\n"},
+		{"BEGIN IMMEDIATE TRANSACTION; END TRANSACTION", "BEGIN IMMEDIATE;\n\nEND;\n"},
+		{"-- nothing to see\n", "-- nothing to see\n"},
+		{"", ""},
+	} {
+		got, err := format(tt.in)
+		if err != nil {
+			t.Fatalf("format(%q): %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("format(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if again, err := format(got); err != nil || again != got {
+			t.Errorf("format(%q) is not idempotent: %q, %v", got, again, err)
+		}
+	}
+
+	if _, err := format("SELECT FROM"); err == nil {
+		t.Error("expected syntax error")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	var stmts []sql.Statement
+	if err := sql.ParseMultiStmtString("BEGIN; UPDATE t SET a = 1; END;", func(stmt sql.Statement) error {
+		stmts = append(stmts, stmt)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		out, want string
+	}{
+		{"BEGIN;\n\nUPDATE \"t\" SET \"a\" = 1;\n\nEND;\n", ""},
+		{"BEGIN;\n\nUPDATE t SET a = 1;\n\nCOMMIT;\n", "formatting would change statement 3"},
+		{"BEGIN;\n\nUPDATE t SET a = 1;\n", "formatting would drop 1 statement(s)"},
+		{"BEGIN;\n\nUPDATE t SET a = 2;\n\nEND;\n", "formatting would change statement 2"},
+		{"BEGIN;\n\nUPDATE t SET a = 1;\n\nEND;\n\nEND;\n", "formatting would add a statement"},
+	} {
+		err := verify(stmts, tt.out)
+		if got := fmt.Sprint(err); (tt.want == "" && err != nil) || (tt.want != "" && got != tt.want) {
+			t.Errorf("verify(%q) = %v, want %q", tt.out, err, tt.want)
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	got := string(unifiedDiff("x.sql.orig", "x.sql", "a\nb\nc\nd\ne\nf\ng\nh\ni\n", "a\nB\nc\nd\ne\nf\ng\nh\ni\nj"))
+	want := `diff -u x.sql.orig x.sql
+--- x.sql.orig
++++ x.sql
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -7,3 +7,4 @@
+ g
+ h
+ i
++j
+\ No newline at end of file
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if unifiedDiff("a", "b", "x\n", "x\n") != nil {
+		t.Error("expected no diff for equal inputs")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want string // kinds of the edit script
+	}{
+		{"", "", ""},
+		{"abc", "abc", "   "},
+		{"abc", "", "---"},
+		{"", "abc", "+++"},
+		{"abcabba", "cbabac", "-+ -  - +"},
+		{"xaby", "zabw", "-+  -+"},
+	} {
+		ops := diffLines(strings.Split(tt.a, ""), strings.Split(tt.b, ""))
+		var got []byte
+		for _, op := range ops {
+			got = append(got, op.kind)
+		}
+		if string(got) != tt.want {
+			t.Errorf("diffLines(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// Ensure large inputs are diffed without a quadratic table, which would take
+// gigabytes here.
+func TestDiffLines_Large(t *testing.T) {
+	var a, b []string
+	for i := range 20000 {
+		line := strconv.Itoa(i)
+		a = append(a, line)
+		if i%10 == 0 {
+			line = "changed"
+		}
+		b = append(b, line)
+	}
+
+	if ops := diffLines(a, b); len(ops) != 22000 {
+		t.Fatalf("len(ops)=%d, want 22000", len(ops))
+	}
+}
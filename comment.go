@@ -0,0 +1,188 @@
+package sql
+
+import (
+	"strings"
+)
+
+// Comment represents a single "--" or "/* */" comment.
+type Comment struct {
+	span
+
+	Text string // comment text, including the comment markers
+}
+
+// CommentGroup represents a sequence of comments with no other tokens and no
+// empty lines between.
+type CommentGroup struct {
+	List []*Comment // len(List) > 0
+}
+
+// Pos returns the position of the first comment in the group.
+func (g *CommentGroup) Pos() Pos { return g.List[0].Pos() }
+
+// End returns the position immediately after the last comment in the group.
+func (g *CommentGroup) End() Pos { return g.List[len(g.List)-1].End() }
+
+// Text returns the text of the comment group with the comment markers and
+// surrounding whitespace removed. Comments are separated by a newline.
+func (g *CommentGroup) Text() string {
+	if g == nil {
+		return ""
+	}
+
+	lines := make([]string, 0, len(g.List))
+	for _, c := range g.List {
+		text := c.Text
+		if strings.HasPrefix(text, "--") {
+			text = text[2:]
+		} else {
+			text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+		}
+		lines = append(lines, strings.TrimSpace(text))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// comments holds the comment groups attached to a node. It is embedded in
+// every node next to span.
+type comments struct {
+	leading  *CommentGroup // comments before the node
+	trailing *CommentGroup // comments after the node
+}
+
+// LeadingComments returns the comments placed before the node, or nil.
+func (c comments) LeadingComments() *CommentGroup { return c.leading }
+
+// TrailingComments returns the comments placed after the node, or nil.
+func (c comments) TrailingComments() *CommentGroup { return c.trailing }
+
+// SetLeadingComments sets the comments placed before the node.
+func (c *comments) SetLeadingComments(g *CommentGroup) { c.leading = g }
+
+// SetTrailingComments sets the comments placed after the node.
+func (c *comments) SetTrailingComments(g *CommentGroup) { c.trailing = g }
+
+// clone returns a deep copy of c.
+func (c comments) clone() comments {
+	return comments{leading: c.leading.clone(), trailing: c.trailing.clone()}
+}
+
+// clone returns a deep copy of g.
+func (g *CommentGroup) clone() *CommentGroup {
+	if g == nil {
+		return nil
+	}
+	other := &CommentGroup{List: make([]*Comment, len(g.List))}
+	for i, c := range g.List {
+		cc := *c
+		other.List[i] = &cc
+	}
+	return other
+}
+
+// commented returns s surrounded by the leading & trailing comments of n.
+// Line comments are always followed by a newline so s remains valid SQL.
+func commented(n Node, s string) string {
+	leading, trailing := n.LeadingComments(), n.TrailingComments()
+	if leading == nil && trailing == nil {
+		return s
+	}
+
+	var buf strings.Builder
+	if leading != nil {
+		for _, c := range leading.List {
+			buf.WriteString(c.Text)
+			if isLineComment(c.Text) {
+				buf.WriteString("\n")
+			} else {
+				buf.WriteString(" ")
+			}
+		}
+	}
+
+	buf.WriteString(s)
+
+	if trailing != nil {
+		for _, c := range trailing.List {
+			buf.WriteString(" ")
+			buf.WriteString(c.Text)
+			if isLineComment(c.Text) {
+				buf.WriteString("\n")
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+func isLineComment(text string) bool {
+	return strings.HasPrefix(text, "--")
+}
+
+// attachComments attaches each comment group to the nearest node within root.
+// A group starting on the same line as the end of the preceding node becomes a
+// trailing comment of that node, otherwise it becomes a leading comment of the
+// following node. When several nodes share a boundary the outermost one wins.
+func attachComments(src string, root Node, groups []*CommentGroup) {
+	if len(groups) == 0 {
+		return
+	}
+
+	var nodes []Node
+	Walk(root, func(n Node) bool {
+		nodes = append(nodes, n)
+		return true
+	})
+
+	for _, g := range groups {
+		var prev, next Node
+		for _, n := range nodes {
+			if n.End().GetOffset() <= g.Pos().GetOffset() {
+				if prev == nil || n.End().GetOffset() > prev.End().GetOffset() ||
+					(n.End().GetOffset() == prev.End().GetOffset() && n.Pos().GetOffset() < prev.Pos().GetOffset()) {
+					prev = n
+				}
+			}
+			if n.Pos().GetOffset() >= g.End().GetOffset() {
+				if next == nil || n.Pos().GetOffset() < next.Pos().GetOffset() ||
+					(n.Pos().GetOffset() == next.Pos().GetOffset() && n.End().GetOffset() > next.End().GetOffset()) {
+					next = n
+				}
+			}
+		}
+
+		switch {
+		case prev != nil && !strings.Contains(src[prev.End().GetOffset():g.Pos().GetOffset()], "\n"):
+			addComments(prev, g, false)
+		case next != nil:
+			addComments(next, g, true)
+		case prev != nil:
+			addComments(prev, g, false)
+		default:
+			addComments(root, g, false)
+		}
+	}
+}
+
+// addComments adds the comments of g to the leading or trailing comments of n.
+func addComments(n Node, g *CommentGroup, leading bool) {
+	c, ok := n.(interface {
+		SetLeadingComments(*CommentGroup)
+		SetTrailingComments(*CommentGroup)
+	})
+	if !ok {
+		return
+	}
+
+	if leading {
+		if existing := n.LeadingComments(); existing != nil {
+			g = &CommentGroup{List: append(existing.List, g.List...)}
+		}
+		c.SetLeadingComments(g)
+	} else {
+		if existing := n.TrailingComments(); existing != nil {
+			g = &CommentGroup{List: append(existing.List, g.List...)}
+		}
+		c.SetTrailingComments(g)
+	}
+}
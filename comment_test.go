@@ -0,0 +1,86 @@
+package sql_test
+
+import (
+	"testing"
+
+	"github.com/TcMits/sql"
+)
+
+func Test_Comments_Statement(t *testing.T) {
+	src := `-- migration: irreversible
+-- author: tcmits
+CREATE TABLE foo (
+	-- primary key
+	id INTEGER PRIMARY KEY,
+	name TEXT /* display name */
+); -- end of foo
+
+/* second */
+DROP TABLE bar;
+-- trailing file comment`
+
+	var stmts []sql.Statement
+	if err := sql.ParseMultiStmtString(src, func(stmt sql.Statement) error {
+		stmts = append(stmts, stmt)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(stmts) != 2 {
+		t.Fatalf("expected 2 statements, got %d", len(stmts))
+	}
+
+	create := stmts[0].(*sql.CreateTableStatement)
+	if got, want := create.LeadingComments().Text(), "migration: irreversible\nauthor: tcmits"; got != want {
+		t.Errorf("leading comments of create: got %q, want %q", got, want)
+	}
+	if got, want := create.TrailingComments().Text(), "end of foo"; got != want {
+		t.Errorf("trailing comments of create: got %q, want %q", got, want)
+	}
+	if got, want := create.Columns[0].LeadingComments().Text(), "primary key"; got != want {
+		t.Errorf("leading comments of id: got %q, want %q", got, want)
+	}
+	if got, want := create.Columns[1].TrailingComments().Text(), "display name"; got != want {
+		t.Errorf("trailing comments of name: got %q, want %q", got, want)
+	}
+
+	drop := stmts[1].(*sql.DropTableStatement)
+	if got, want := drop.LeadingComments().Text(), "second"; got != want {
+		t.Errorf("leading comments of drop: got %q, want %q", got, want)
+	}
+	if got, want := drop.TrailingComments().Text(), "trailing file comment"; got != want {
+		t.Errorf("trailing comments of drop: got %q, want %q", got, want)
+	}
+
+	AssertStatementStringer(t, create, `-- migration: irreversible
+-- author: tcmits
+CREATE TABLE "foo" (-- primary key
+"id" INTEGER PRIMARY KEY, "name" TEXT /* display name */) -- end of foo
+`)
+	AssertStatementStringer(t, drop, `/* second */ DROP TABLE "bar" -- trailing file comment
+`)
+}
+
+func Test_Comments_Expr(t *testing.T) {
+	expr, err := sql.ParseExprString("a /* x */ + b -- y")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bin := expr.(*sql.BinaryExpr)
+	if got, want := bin.X.TrailingComments().Text(), "x"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := bin.TrailingComments().Text(), "y"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := expr.String(), "\"a\" /* x */ + \"b\" -- y\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func Test_Comments_Set(t *testing.T) {
+	stmt := &sql.DropTableStatement{Name: &sql.QualifiedName{Name: &sql.Ident{Name: "foo"}}}
+	stmt.SetLeadingComments(&sql.CommentGroup{List: []*sql.Comment{{Text: "-- drop it"}}})
+	AssertStatementStringer(t, stmt, "-- drop it\nDROP TABLE \"foo\"")
+}
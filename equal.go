@@ -0,0 +1,1151 @@
+package sql
+
+import (
+	"strings"
+)
+
+// EqualOption configures the comparison performed by Equal.
+type EqualOption func(*equaler)
+
+// IgnorePositions makes Equal ignore the source positions of nodes.
+func IgnorePositions() EqualOption {
+	return func(e *equaler) { e.ignorePositions = true }
+}
+
+// IgnoreQuoting makes Equal ignore whether identifiers are quoted.
+func IgnoreQuoting() EqualOption {
+	return func(e *equaler) { e.ignoreQuoting = true }
+}
+
+// IgnoreCase makes Equal compare identifier names case-insensitively, the way
+// SQLite resolves them.
+func IgnoreCase() EqualOption {
+	return func(e *equaler) { e.ignoreCase = true }
+}
+
+// Equal reports whether a and b are structurally equal. Comments are never
+// compared. By default positions, identifier quoting and identifier case must
+// match as well; use the options to relax the comparison.
+//
+//	sql.Equal(a, b, sql.IgnorePositions(), sql.IgnoreQuoting(), sql.IgnoreCase())
+func Equal(a, b Node, opts ...EqualOption) bool {
+	var e equaler
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return e.node(a, b)
+}
+
+type equaler struct {
+	ignorePositions bool
+	ignoreQuoting   bool
+	ignoreCase      bool
+}
+
+func (e *equaler) node(a, b Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	switch a := a.(type) {
+	case *ExplainStatement:
+		b, ok := b.(*ExplainStatement)
+		return ok && e.explainStatement(a, b)
+	case *BeginStatement:
+		b, ok := b.(*BeginStatement)
+		return ok && e.beginStatement(a, b)
+	case *CommitStatement:
+		b, ok := b.(*CommitStatement)
+		return ok && e.commitStatement(a, b)
+	case *RollbackStatement:
+		b, ok := b.(*RollbackStatement)
+		return ok && e.rollbackStatement(a, b)
+	case *SavepointStatement:
+		b, ok := b.(*SavepointStatement)
+		return ok && e.savepointStatement(a, b)
+	case *ReleaseStatement:
+		b, ok := b.(*ReleaseStatement)
+		return ok && e.releaseStatement(a, b)
+	case *CreateTableStatement:
+		b, ok := b.(*CreateTableStatement)
+		return ok && e.createTableStatement(a, b)
+	case *ColumnDefinition:
+		b, ok := b.(*ColumnDefinition)
+		return ok && e.columnDefinition(a, b)
+	case *PrimaryKeyConstraint:
+		b, ok := b.(*PrimaryKeyConstraint)
+		return ok && e.primaryKeyConstraint(a, b)
+	case *NotNullConstraint:
+		b, ok := b.(*NotNullConstraint)
+		return ok && e.notNullConstraint(a, b)
+	case *UniqueConstraint:
+		b, ok := b.(*UniqueConstraint)
+		return ok && e.uniqueConstraint(a, b)
+	case *CheckConstraint:
+		b, ok := b.(*CheckConstraint)
+		return ok && e.checkConstraint(a, b)
+	case *DefaultConstraint:
+		b, ok := b.(*DefaultConstraint)
+		return ok && e.defaultConstraint(a, b)
+	case *GeneratedConstraint:
+		b, ok := b.(*GeneratedConstraint)
+		return ok && e.generatedConstraint(a, b)
+	case *CollateConstraint:
+		b, ok := b.(*CollateConstraint)
+		return ok && e.collateConstraint(a, b)
+	case *ForeignKeyConstraint:
+		b, ok := b.(*ForeignKeyConstraint)
+		return ok && e.foreignKeyConstraint(a, b)
+	case *ForeignKeyArg:
+		b, ok := b.(*ForeignKeyArg)
+		return ok && e.foreignKeyArg(a, b)
+	case *CreateVirtualTableStatement:
+		b, ok := b.(*CreateVirtualTableStatement)
+		return ok && e.createVirtualTableStatement(a, b)
+	case *ModuleArgument:
+		b, ok := b.(*ModuleArgument)
+		return ok && e.moduleArgument(a, b)
+	case *AnalyzeStatement:
+		b, ok := b.(*AnalyzeStatement)
+		return ok && e.analyzeStatement(a, b)
+	case *ReindexStatement:
+		b, ok := b.(*ReindexStatement)
+		return ok && e.reindexStatement(a, b)
+	case *AlterTableStatement:
+		b, ok := b.(*AlterTableStatement)
+		return ok && e.alterTableStatement(a, b)
+	case *Ident:
+		b, ok := b.(*Ident)
+		return ok && e.ident(a, b)
+	case *Type:
+		b, ok := b.(*Type)
+		return ok && e.typ(a, b)
+	case *StringLit:
+		b, ok := b.(*StringLit)
+		return ok && e.stringLit(a, b)
+	case *TimestampLit:
+		b, ok := b.(*TimestampLit)
+		return ok && e.timestampLit(a, b)
+	case *BlobLit:
+		b, ok := b.(*BlobLit)
+		return ok && e.blobLit(a, b)
+	case *NumberLit:
+		b, ok := b.(*NumberLit)
+		return ok && e.numberLit(a, b)
+	case *NullLit:
+		b, ok := b.(*NullLit)
+		return ok && e.nullLit(a, b)
+	case *BoolLit:
+		b, ok := b.(*BoolLit)
+		return ok && e.boolLit(a, b)
+	case *BindExpr:
+		b, ok := b.(*BindExpr)
+		return ok && e.bindExpr(a, b)
+	case *UnaryExpr:
+		b, ok := b.(*UnaryExpr)
+		return ok && e.unaryExpr(a, b)
+	case *BinaryExpr:
+		b, ok := b.(*BinaryExpr)
+		return ok && e.binaryExpr(a, b)
+	case *CastExpr:
+		b, ok := b.(*CastExpr)
+		return ok && e.castExpr(a, b)
+	case *CaseExpr:
+		b, ok := b.(*CaseExpr)
+		return ok && e.caseExpr(a, b)
+	case *CaseBlock:
+		b, ok := b.(*CaseBlock)
+		return ok && e.caseBlock(a, b)
+	case *Raise:
+		b, ok := b.(*Raise)
+		return ok && e.raise(a, b)
+	case *Exists:
+		b, ok := b.(*Exists)
+		return ok && e.exists(a, b)
+	case *Null:
+		b, ok := b.(*Null)
+		return ok && e.null(a, b)
+	case *ExprList:
+		b, ok := b.(*ExprList)
+		return ok && e.exprList(a, b)
+	case *QualifiedRef:
+		b, ok := b.(*QualifiedRef)
+		return ok && e.qualifiedRef(a, b)
+	case *Call:
+		b, ok := b.(*Call)
+		return ok && e.call(a, b)
+	case *OrderingTerm:
+		b, ok := b.(*OrderingTerm)
+		return ok && e.orderingTerm(a, b)
+	case *FrameSpec:
+		b, ok := b.(*FrameSpec)
+		return ok && e.frameSpec(a, b)
+	case *DropTableStatement:
+		b, ok := b.(*DropTableStatement)
+		return ok && e.dropTableStatement(a, b)
+	case *CreateViewStatement:
+		b, ok := b.(*CreateViewStatement)
+		return ok && e.createViewStatement(a, b)
+	case *DropViewStatement:
+		b, ok := b.(*DropViewStatement)
+		return ok && e.dropViewStatement(a, b)
+	case *CreateIndexStatement:
+		b, ok := b.(*CreateIndexStatement)
+		return ok && e.createIndexStatement(a, b)
+	case *DropIndexStatement:
+		b, ok := b.(*DropIndexStatement)
+		return ok && e.dropIndexStatement(a, b)
+	case *CreateTriggerStatement:
+		b, ok := b.(*CreateTriggerStatement)
+		return ok && e.createTriggerStatement(a, b)
+	case *DropTriggerStatement:
+		b, ok := b.(*DropTriggerStatement)
+		return ok && e.dropTriggerStatement(a, b)
+	case *InsertStatement:
+		b, ok := b.(*InsertStatement)
+		return ok && e.insertStatement(a, b)
+	case *UpsertClause:
+		b, ok := b.(*UpsertClause)
+		return ok && e.upsertClause(a, b)
+	case *UpdateStatement:
+		b, ok := b.(*UpdateStatement)
+		return ok && e.updateStatement(a, b)
+	case *DeleteStatement:
+		b, ok := b.(*DeleteStatement)
+		return ok && e.deleteStatement(a, b)
+	case *Assignment:
+		b, ok := b.(*Assignment)
+		return ok && e.assignment(a, b)
+	case *IndexedColumn:
+		b, ok := b.(*IndexedColumn)
+		return ok && e.indexedColumn(a, b)
+	case *SelectStatement:
+		b, ok := b.(*SelectStatement)
+		return ok && e.selectStatement(a, b)
+	case *ResultColumn:
+		b, ok := b.(*ResultColumn)
+		return ok && e.resultColumn(a, b)
+	case *QualifiedName:
+		b, ok := b.(*QualifiedName)
+		return ok && e.qualifiedName(a, b)
+	case *ParenSource:
+		b, ok := b.(*ParenSource)
+		return ok && e.parenSource(a, b)
+	case *JoinClause:
+		b, ok := b.(*JoinClause)
+		return ok && e.joinClause(a, b)
+	case *JoinOperator:
+		b, ok := b.(*JoinOperator)
+		return ok && e.joinOperator(a, b)
+	case *OnConstraint:
+		b, ok := b.(*OnConstraint)
+		return ok && e.onConstraint(a, b)
+	case *UsingConstraint:
+		b, ok := b.(*UsingConstraint)
+		return ok && e.usingConstraint(a, b)
+	case *WithClause:
+		b, ok := b.(*WithClause)
+		return ok && e.withClause(a, b)
+	case *CTE:
+		b, ok := b.(*CTE)
+		return ok && e.cte(a, b)
+	case *Window:
+		b, ok := b.(*Window)
+		return ok && e.window(a, b)
+	case *WindowDefinition:
+		b, ok := b.(*WindowDefinition)
+		return ok && e.windowDefinition(a, b)
+	case *PragmaStatement:
+		b, ok := b.(*PragmaStatement)
+		return ok && e.pragmaStatement(a, b)
+	case *AttachStatement:
+		b, ok := b.(*AttachStatement)
+		return ok && e.attachStatement(a, b)
+	case *DetachStatement:
+		b, ok := b.(*DetachStatement)
+		return ok && e.detachStatement(a, b)
+	case *VacuumStatement:
+		b, ok := b.(*VacuumStatement)
+		return ok && e.vacuumStatement(a, b)
+	case *ConflictClause:
+		b, ok := b.(*ConflictClause)
+		return ok && e.conflictClause(a, b)
+	case *FunctionArg:
+		b, ok := b.(*FunctionArg)
+		return ok && e.functionArg(a, b)
+	case *InExpr:
+		b, ok := b.(*InExpr)
+		return ok && e.inExpr(a, b)
+	case *ParenExpr:
+		b, ok := b.(*ParenExpr)
+		return ok && e.parenExpr(a, b)
+	default:
+		panic("sql.Equal: unexpected node type")
+	}
+}
+
+func (e *equaler) expr(a, b Expr) bool { return e.node(a, b) }
+
+func (e *equaler) statement(a, b Statement) bool { return e.node(a, b) }
+
+func (e *equaler) constraint(a, b Constraint) bool { return e.node(a, b) }
+
+func (e *equaler) span(a, b span) bool {
+	return e.ignorePositions || a == b
+}
+
+func (e *equaler) identName(a, b string) bool {
+	if e.ignoreCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// equalSlice reports whether a and b have the same length and fn reports
+// each pair of elements as equal.
+func equalSlice[T any](a, b []T, fn func(T, T) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !fn(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *equaler) explainStatement(a, b *ExplainStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Explain == b.Explain &&
+		a.QueryPlan == b.QueryPlan &&
+		e.node(a.Stmt, b.Stmt)
+}
+
+func (e *equaler) beginStatement(a, b *BeginStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Deferred == b.Deferred &&
+		a.Immediate == b.Immediate &&
+		a.Exclusive == b.Exclusive
+}
+
+func (e *equaler) commitStatement(a, b *CommitStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.EndKeyword == b.EndKeyword
+}
+
+func (e *equaler) rollbackStatement(a, b *RollbackStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.SavepointName, b.SavepointName)
+}
+
+func (e *equaler) savepointStatement(a, b *SavepointStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name)
+}
+
+func (e *equaler) releaseStatement(a, b *ReleaseStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name)
+}
+
+func (e *equaler) createTableStatement(a, b *CreateTableStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Temp == b.Temp &&
+		a.IfNotExists == b.IfNotExists &&
+		e.qualifiedName(a.Name, b.Name) &&
+		equalSlice(a.Columns, b.Columns, e.columnDefinition) &&
+		equalSlice(a.Constraints, b.Constraints, e.constraint) &&
+		a.WithoutRowID == b.WithoutRowID &&
+		a.Strict == b.Strict &&
+		e.selectStatement(a.Select, b.Select)
+}
+
+func (e *equaler) columnDefinition(a, b *ColumnDefinition) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name) &&
+		e.typ(a.Type, b.Type) &&
+		equalSlice(a.Constraints, b.Constraints, e.constraint)
+}
+
+func (e *equaler) primaryKeyConstraint(a, b *PrimaryKeyConstraint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name) &&
+		a.Asc == b.Asc &&
+		a.Desc == b.Desc &&
+		e.conflictClause(a.Conflict, b.Conflict) &&
+		equalSlice(a.Columns, b.Columns, e.ident) &&
+		a.Autoincrement == b.Autoincrement
+}
+
+func (e *equaler) notNullConstraint(a, b *NotNullConstraint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name) &&
+		e.conflictClause(a.Conflict, b.Conflict)
+}
+
+func (e *equaler) uniqueConstraint(a, b *UniqueConstraint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name) &&
+		e.conflictClause(a.Conflict, b.Conflict) &&
+		equalSlice(a.Columns, b.Columns, e.indexedColumn)
+}
+
+func (e *equaler) checkConstraint(a, b *CheckConstraint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name) &&
+		e.node(a.Expr, b.Expr)
+}
+
+func (e *equaler) defaultConstraint(a, b *DefaultConstraint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name) &&
+		e.node(a.Expr, b.Expr)
+}
+
+func (e *equaler) generatedConstraint(a, b *GeneratedConstraint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name) &&
+		e.node(a.Expr, b.Expr) &&
+		a.Stored == b.Stored &&
+		a.Virtual == b.Virtual
+}
+
+func (e *equaler) collateConstraint(a, b *CollateConstraint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name) &&
+		e.ident(a.Collation, b.Collation)
+}
+
+func (e *equaler) foreignKeyConstraint(a, b *ForeignKeyConstraint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name) &&
+		equalSlice(a.Columns, b.Columns, e.ident) &&
+		e.ident(a.ForeignTable, b.ForeignTable) &&
+		equalSlice(a.ForeignColumns, b.ForeignColumns, e.ident) &&
+		equalSlice(a.Args, b.Args, e.foreignKeyArg) &&
+		a.Deferrable == b.Deferrable &&
+		a.NotDeferrable == b.NotDeferrable &&
+		a.InitiallyDeferred == b.InitiallyDeferred &&
+		a.InitiallyImmediate == b.InitiallyImmediate
+}
+
+func (e *equaler) foreignKeyArg(a, b *ForeignKeyArg) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.OnUpdate == b.OnUpdate &&
+		a.OnDelete == b.OnDelete &&
+		a.SetNull == b.SetNull &&
+		a.SetDefault == b.SetDefault &&
+		a.Cascade == b.Cascade &&
+		a.Restrict == b.Restrict &&
+		a.NoAction == b.NoAction
+}
+
+func (e *equaler) createVirtualTableStatement(a, b *CreateVirtualTableStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.IfNotExists == b.IfNotExists &&
+		e.qualifiedName(a.Name, b.Name) &&
+		e.ident(a.ModuleName, b.ModuleName) &&
+		equalSlice(a.Arguments, b.Arguments, e.moduleArgument)
+}
+
+func (e *equaler) moduleArgument(a, b *ModuleArgument) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name) &&
+		e.node(a.Literal, b.Literal) &&
+		e.typ(a.Type, b.Type)
+}
+
+func (e *equaler) analyzeStatement(a, b *AnalyzeStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.qualifiedName(a.Name, b.Name)
+}
+
+func (e *equaler) reindexStatement(a, b *ReindexStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.qualifiedName(a.Name, b.Name)
+}
+
+func (e *equaler) alterTableStatement(a, b *AlterTableStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.qualifiedName(a.Name, b.Name) &&
+		e.ident(a.NewName, b.NewName) &&
+		e.ident(a.ColumnName, b.ColumnName) &&
+		e.ident(a.NewColumnName, b.NewColumnName) &&
+		e.columnDefinition(a.ColumnDef, b.ColumnDef) &&
+		e.ident(a.DropColumnName, b.DropColumnName)
+}
+
+func (e *equaler) ident(a, b *Ident) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		(e.ignoreQuoting || (a.Quoted == b.Quoted && a.Quote == b.Quote)) &&
+		e.identName(a.Name, b.Name)
+}
+
+func (e *equaler) typ(a, b *Type) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name) &&
+		e.numberLit(a.Precision, b.Precision) &&
+		e.numberLit(a.Scale, b.Scale)
+}
+
+func (e *equaler) stringLit(a, b *StringLit) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Value == b.Value
+}
+
+func (e *equaler) timestampLit(a, b *TimestampLit) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Value == b.Value
+}
+
+func (e *equaler) blobLit(a, b *BlobLit) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Value == b.Value
+}
+
+func (e *equaler) numberLit(a, b *NumberLit) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Value == b.Value
+}
+
+func (e *equaler) nullLit(a, b *NullLit) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span)
+}
+
+func (e *equaler) boolLit(a, b *BoolLit) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Value == b.Value
+}
+
+func (e *equaler) bindExpr(a, b *BindExpr) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Name == b.Name
+}
+
+func (e *equaler) unaryExpr(a, b *UnaryExpr) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Op == b.Op &&
+		e.node(a.X, b.X)
+}
+
+func (e *equaler) binaryExpr(a, b *BinaryExpr) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.X, b.X) &&
+		a.Op == b.Op &&
+		a.AltOp == b.AltOp &&
+		e.node(a.Y, b.Y)
+}
+
+func (e *equaler) castExpr(a, b *CastExpr) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.X, b.X) &&
+		e.typ(a.Type, b.Type)
+}
+
+func (e *equaler) caseExpr(a, b *CaseExpr) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.Operand, b.Operand) &&
+		equalSlice(a.Blocks, b.Blocks, e.caseBlock) &&
+		e.node(a.ElseExpr, b.ElseExpr)
+}
+
+func (e *equaler) caseBlock(a, b *CaseBlock) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.Condition, b.Condition) &&
+		e.node(a.Body, b.Body)
+}
+
+func (e *equaler) raise(a, b *Raise) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Ignore == b.Ignore &&
+		a.Rollback == b.Rollback &&
+		a.Abort == b.Abort &&
+		a.Fail == b.Fail &&
+		e.stringLit(a.Error, b.Error)
+}
+
+func (e *equaler) exists(a, b *Exists) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Not == b.Not &&
+		e.selectStatement(a.Select, b.Select)
+}
+
+func (e *equaler) null(a, b *Null) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.X, b.X) &&
+		a.Op == b.Op
+}
+
+func (e *equaler) exprList(a, b *ExprList) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		equalSlice(a.Exprs, b.Exprs, e.expr)
+}
+
+func (e *equaler) qualifiedRef(a, b *QualifiedRef) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.qualifiedName(a.Table, b.Table) &&
+		a.Star == b.Star &&
+		e.ident(a.Column, b.Column)
+}
+
+func (e *equaler) call(a, b *Call) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.qualifiedName(a.Name, b.Name) &&
+		e.node(a.Filter, b.Filter) &&
+		e.ident(a.OverName, b.OverName) &&
+		e.windowDefinition(a.OverWindow, b.OverWindow)
+}
+
+func (e *equaler) orderingTerm(a, b *OrderingTerm) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.X, b.X) &&
+		a.Asc == b.Asc &&
+		a.Desc == b.Desc &&
+		a.NullsFirst == b.NullsFirst &&
+		a.NullsLast == b.NullsLast
+}
+
+func (e *equaler) frameSpec(a, b *FrameSpec) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Range == b.Range &&
+		a.Rows == b.Rows &&
+		a.Groups == b.Groups &&
+		a.Between == b.Between &&
+		e.node(a.X, b.X) &&
+		a.CurrentRowX == b.CurrentRowX &&
+		a.FollowingX == b.FollowingX &&
+		a.PrecedingX == b.PrecedingX &&
+		a.UnboundedX == b.UnboundedX &&
+		e.node(a.Y, b.Y) &&
+		a.CurrentRowY == b.CurrentRowY &&
+		a.FollowingY == b.FollowingY &&
+		a.PrecedingY == b.PrecedingY &&
+		a.UnboundedY == b.UnboundedY &&
+		a.ExcludeNoOthers == b.ExcludeNoOthers &&
+		a.ExcludeCurrentRow == b.ExcludeCurrentRow &&
+		a.ExcludeGroup == b.ExcludeGroup &&
+		a.ExcludeTies == b.ExcludeTies
+}
+
+func (e *equaler) dropTableStatement(a, b *DropTableStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.IfExists == b.IfExists &&
+		e.qualifiedName(a.Name, b.Name)
+}
+
+func (e *equaler) createViewStatement(a, b *CreateViewStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Temp == b.Temp &&
+		a.IfNotExists == b.IfNotExists &&
+		e.qualifiedName(a.Name, b.Name) &&
+		equalSlice(a.Columns, b.Columns, e.ident) &&
+		e.selectStatement(a.Select, b.Select)
+}
+
+func (e *equaler) dropViewStatement(a, b *DropViewStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.IfExists == b.IfExists &&
+		e.qualifiedName(a.Name, b.Name)
+}
+
+func (e *equaler) createIndexStatement(a, b *CreateIndexStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Unique == b.Unique &&
+		a.IfNotExists == b.IfNotExists &&
+		e.qualifiedName(a.Name, b.Name) &&
+		e.ident(a.Table, b.Table) &&
+		equalSlice(a.Columns, b.Columns, e.indexedColumn) &&
+		e.node(a.WhereExpr, b.WhereExpr)
+}
+
+func (e *equaler) dropIndexStatement(a, b *DropIndexStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.IfExists == b.IfExists &&
+		e.qualifiedName(a.Name, b.Name)
+}
+
+func (e *equaler) createTriggerStatement(a, b *CreateTriggerStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Temp == b.Temp &&
+		a.IfNotExists == b.IfNotExists &&
+		e.qualifiedName(a.Name, b.Name) &&
+		a.Before == b.Before &&
+		a.After == b.After &&
+		a.InsteadOf == b.InsteadOf &&
+		a.Delete == b.Delete &&
+		a.Insert == b.Insert &&
+		a.Update == b.Update &&
+		equalSlice(a.UpdateOfColumns, b.UpdateOfColumns, e.ident) &&
+		e.ident(a.Table, b.Table) &&
+		a.ForEachRow == b.ForEachRow &&
+		e.node(a.WhenExpr, b.WhenExpr) &&
+		equalSlice(a.Body, b.Body, e.statement)
+}
+
+func (e *equaler) dropTriggerStatement(a, b *DropTriggerStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.IfExists == b.IfExists &&
+		e.qualifiedName(a.Name, b.Name)
+}
+
+func (e *equaler) insertStatement(a, b *InsertStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.withClause(a.WithClause, b.WithClause) &&
+		a.Replace == b.Replace &&
+		a.InsertOrReplace == b.InsertOrReplace &&
+		a.InsertOrRollback == b.InsertOrRollback &&
+		a.InsertOrAbort == b.InsertOrAbort &&
+		a.InsertOrFail == b.InsertOrFail &&
+		a.InsertOrIgnore == b.InsertOrIgnore &&
+		e.qualifiedName(a.Table, b.Table) &&
+		equalSlice(a.Columns, b.Columns, e.ident) &&
+		equalSlice(a.ValueLists, b.ValueLists, e.exprList) &&
+		e.selectStatement(a.Select, b.Select) &&
+		a.DefaultValues == b.DefaultValues &&
+		e.upsertClause(a.UpsertClause, b.UpsertClause) &&
+		equalSlice(a.ReturningColumns, b.ReturningColumns, e.resultColumn)
+}
+
+func (e *equaler) upsertClause(a, b *UpsertClause) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		equalSlice(a.Columns, b.Columns, e.indexedColumn) &&
+		e.node(a.WhereExpr, b.WhereExpr) &&
+		a.DoNothing == b.DoNothing &&
+		a.DoUpdateSet == b.DoUpdateSet &&
+		equalSlice(a.Assignments, b.Assignments, e.assignment) &&
+		e.node(a.UpdateWhereExpr, b.UpdateWhereExpr)
+}
+
+func (e *equaler) updateStatement(a, b *UpdateStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.withClause(a.WithClause, b.WithClause) &&
+		a.UpdateOrReplace == b.UpdateOrReplace &&
+		a.UpdateOrRollback == b.UpdateOrRollback &&
+		a.UpdateOrAbort == b.UpdateOrAbort &&
+		a.UpdateOrFail == b.UpdateOrFail &&
+		a.UpdateOrIgnore == b.UpdateOrIgnore &&
+		e.qualifiedName(a.Table, b.Table) &&
+		equalSlice(a.Assignments, b.Assignments, e.assignment) &&
+		e.node(a.Source, b.Source) &&
+		e.node(a.WhereExpr, b.WhereExpr) &&
+		equalSlice(a.ReturningColumns, b.ReturningColumns, e.resultColumn) &&
+		equalSlice(a.OrderingTerms, b.OrderingTerms, e.orderingTerm) &&
+		e.node(a.LimitExpr, b.LimitExpr) &&
+		e.node(a.OffsetExpr, b.OffsetExpr) &&
+		a.LimitComma == b.LimitComma
+}
+
+func (e *equaler) deleteStatement(a, b *DeleteStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.withClause(a.WithClause, b.WithClause) &&
+		e.qualifiedName(a.Table, b.Table) &&
+		e.node(a.WhereExpr, b.WhereExpr) &&
+		equalSlice(a.ReturningColumns, b.ReturningColumns, e.resultColumn) &&
+		equalSlice(a.OrderingTerms, b.OrderingTerms, e.orderingTerm) &&
+		e.node(a.LimitExpr, b.LimitExpr) &&
+		e.node(a.OffsetExpr, b.OffsetExpr) &&
+		a.LimitComma == b.LimitComma
+}
+
+func (e *equaler) assignment(a, b *Assignment) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		equalSlice(a.Columns, b.Columns, e.ident) &&
+		e.node(a.Expr, b.Expr)
+}
+
+func (e *equaler) indexedColumn(a, b *IndexedColumn) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.X, b.X) &&
+		a.Asc == b.Asc &&
+		a.Desc == b.Desc
+}
+
+func (e *equaler) selectStatement(a, b *SelectStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.withClause(a.WithClause, b.WithClause) &&
+		equalSlice(a.ValueLists, b.ValueLists, e.exprList) &&
+		a.Distinct == b.Distinct &&
+		a.All == b.All &&
+		equalSlice(a.Columns, b.Columns, e.resultColumn) &&
+		e.node(a.Source, b.Source) &&
+		e.node(a.WhereExpr, b.WhereExpr) &&
+		equalSlice(a.GroupByExprs, b.GroupByExprs, e.expr) &&
+		e.node(a.HavingExpr, b.HavingExpr) &&
+		equalSlice(a.Windows, b.Windows, e.window) &&
+		a.UnionAll == b.UnionAll &&
+		a.Intersect == b.Intersect &&
+		a.Except == b.Except &&
+		e.selectStatement(a.Compound, b.Compound) &&
+		equalSlice(a.OrderingTerms, b.OrderingTerms, e.orderingTerm) &&
+		e.node(a.LimitExpr, b.LimitExpr) &&
+		e.node(a.OffsetExpr, b.OffsetExpr) &&
+		a.LimitComma == b.LimitComma
+}
+
+func (e *equaler) resultColumn(a, b *ResultColumn) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Star == b.Star &&
+		e.node(a.Expr, b.Expr) &&
+		e.ident(a.Alias, b.Alias)
+}
+
+func (e *equaler) qualifiedName(a, b *QualifiedName) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Schema, b.Schema) &&
+		e.ident(a.Name, b.Name) &&
+		a.FunctionCall == b.FunctionCall &&
+		a.FunctionStar == b.FunctionStar &&
+		a.FunctionDistinct == b.FunctionDistinct &&
+		equalSlice(a.FunctionArgs, b.FunctionArgs, e.functionArg) &&
+		e.ident(a.Alias, b.Alias) &&
+		a.NotIndexed == b.NotIndexed &&
+		e.ident(a.Index, b.Index)
+}
+
+func (e *equaler) parenSource(a, b *ParenSource) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.X, b.X) &&
+		e.ident(a.Alias, b.Alias)
+}
+
+func (e *equaler) joinClause(a, b *JoinClause) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.X, b.X) &&
+		e.joinOperator(a.Operator, b.Operator) &&
+		e.node(a.Y, b.Y) &&
+		e.node(a.Constraint, b.Constraint)
+}
+
+func (e *equaler) joinOperator(a, b *JoinOperator) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Natural == b.Natural &&
+		a.Left == b.Left &&
+		a.Right == b.Right &&
+		a.Full == b.Full &&
+		a.Outer == b.Outer &&
+		a.Inner == b.Inner &&
+		a.Cross == b.Cross
+}
+
+func (e *equaler) onConstraint(a, b *OnConstraint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.X, b.X)
+}
+
+func (e *equaler) usingConstraint(a, b *UsingConstraint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		equalSlice(a.Columns, b.Columns, e.ident)
+}
+
+func (e *equaler) withClause(a, b *WithClause) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Recursive == b.Recursive &&
+		equalSlice(a.CTEs, b.CTEs, e.cte)
+}
+
+func (e *equaler) cte(a, b *CTE) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.TableName, b.TableName) &&
+		equalSlice(a.Columns, b.Columns, e.ident) &&
+		a.Materialized == b.Materialized &&
+		a.NotMaterialized == b.NotMaterialized &&
+		e.selectStatement(a.Select, b.Select)
+}
+
+func (e *equaler) window(a, b *Window) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Name, b.Name) &&
+		e.windowDefinition(a.Definition, b.Definition)
+}
+
+func (e *equaler) windowDefinition(a, b *WindowDefinition) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Base, b.Base) &&
+		equalSlice(a.Partitions, b.Partitions, e.expr) &&
+		equalSlice(a.OrderingTerms, b.OrderingTerms, e.orderingTerm) &&
+		e.frameSpec(a.Frame, b.Frame)
+}
+
+func (e *equaler) pragmaStatement(a, b *PragmaStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Schema, b.Schema) &&
+		e.node(a.Expr, b.Expr)
+}
+
+func (e *equaler) attachStatement(a, b *AttachStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.Expr, b.Expr) &&
+		e.ident(a.Schema, b.Schema)
+}
+
+func (e *equaler) detachStatement(a, b *DetachStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Schema, b.Schema)
+}
+
+func (e *equaler) vacuumStatement(a, b *VacuumStatement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.ident(a.Schema, b.Schema) &&
+		e.node(a.Expr, b.Expr)
+}
+
+func (e *equaler) conflictClause(a, b *ConflictClause) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		a.Rollback == b.Rollback &&
+		a.Abort == b.Abort &&
+		a.Fail == b.Fail &&
+		a.Ignore == b.Ignore &&
+		a.Replace == b.Replace
+}
+
+func (e *equaler) functionArg(a, b *FunctionArg) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.Expr, b.Expr) &&
+		equalSlice(a.OrderingTerms, b.OrderingTerms, e.orderingTerm)
+}
+
+func (e *equaler) inExpr(a, b *InExpr) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.X, b.X) &&
+		a.Op == b.Op &&
+		e.selectStatement(a.Select, b.Select) &&
+		e.exprList(a.Values, b.Values) &&
+		e.qualifiedName(a.TableOrFunction, b.TableOrFunction)
+}
+
+func (e *equaler) parenExpr(a, b *ParenExpr) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return e.span(a.span, b.span) &&
+		e.node(a.Expr, b.Expr)
+}
@@ -0,0 +1,73 @@
+package sql_test
+
+import (
+	"testing"
+
+	"github.com/TcMits/sql"
+)
+
+func Test_Equal(t *testing.T) {
+	parse := func(s string) sql.Statement {
+		t.Helper()
+		stmt, err := sql.ParseStmtString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stmt
+	}
+
+	for _, tt := range []struct {
+		a, b string
+		opts []sql.EqualOption
+		want bool
+	}{
+		{`SELECT a FROM t`, `SELECT a FROM t`, nil, true},
+		{`SELECT a FROM t`, `SELECT  a  FROM  t`, nil, false},
+		{`SELECT a FROM t`, `SELECT  a  FROM  t`, []sql.EqualOption{sql.IgnorePositions()}, true},
+		{`SELECT a FROM t`, `SELECT b FROM t`, []sql.EqualOption{sql.IgnorePositions()}, false},
+		{`SELECT a FROM t`, `SELECT a FROM t WHERE 1`, []sql.EqualOption{sql.IgnorePositions()}, false},
+		{`SELECT a FROM t`, `DELETE FROM t`, []sql.EqualOption{sql.IgnorePositions()}, false},
+		{`SELECT "a" FROM t`, `SELECT a FROM t`, []sql.EqualOption{sql.IgnorePositions()}, false},
+		{`SELECT "a" FROM t`, `SELECT a FROM t`, []sql.EqualOption{sql.IgnorePositions(), sql.IgnoreQuoting()}, true},
+		{`SELECT A FROM T`, `SELECT a FROM t`, []sql.EqualOption{sql.IgnorePositions()}, false},
+		{`SELECT A FROM T`, `SELECT a FROM t`, []sql.EqualOption{sql.IgnorePositions(), sql.IgnoreCase()}, true},
+		{`SELECT 'A'`, `SELECT 'a'`, []sql.EqualOption{sql.IgnorePositions(), sql.IgnoreCase()}, false},
+		{`VALUES (1, 2), (3, 4)`, `VALUES (1, 2), (3, 4)`, nil, true},
+		{`VALUES (1, 2), (3, 4)`, `VALUES (1, 2), (3, 5)`, nil, false},
+		{`VALUES (1, 2), (3, 4)`, `VALUES (1, 2)`, []sql.EqualOption{sql.IgnorePositions()}, false},
+		{`SELECT a -- x` + "\n" + `FROM t`, `SELECT a FROM t`, []sql.EqualOption{sql.IgnorePositions()}, true},
+		{
+			`CREATE TABLE foo (id INTEGER PRIMARY KEY, Name TEXT NOT NULL)`,
+			`create table "FOO" ("id" integer primary key, "name" text not null)`,
+			[]sql.EqualOption{sql.IgnorePositions(), sql.IgnoreQuoting(), sql.IgnoreCase()},
+			true,
+		},
+		{
+			`CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL)`,
+			`CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT)`,
+			[]sql.EqualOption{sql.IgnorePositions(), sql.IgnoreQuoting(), sql.IgnoreCase()},
+			false,
+		},
+		{
+			`CREATE TRIGGER tr AFTER INSERT ON t BEGIN DELETE FROM a; END`,
+			`CREATE TRIGGER tr AFTER INSERT ON t BEGIN DELETE FROM b; END`,
+			nil,
+			false,
+		},
+	} {
+		if got := sql.Equal(parse(tt.a), parse(tt.b), tt.opts...); got != tt.want {
+			t.Errorf("Equal(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	stmt := parse(`SELECT a FROM t`)
+	if !sql.Equal(stmt, sql.Clone(stmt)) {
+		t.Error("expected clone to be equal")
+	}
+	if !sql.Equal(nil, nil) {
+		t.Error("expected nil nodes to be equal")
+	}
+	if sql.Equal(stmt, nil) {
+		t.Error("expected statement not to equal nil")
+	}
+}
@@ -21,11 +21,9 @@ LEFT JOIN derived USING (user_id)`
 		panic(err)
 	}
 
-	switch stmt.(type) {
-	case *sql.SelectStatement, *sql.ExplainStatement:
+	if effects := sql.Classify(stmt); effects.ReadOnly() {
 		fmt.Println("The statement is a readonly statement.")
-	default:
-		fmt.Println("The statement is not a readonly statement.")
+	} else {
+		fmt.Println("The statement is not a readonly statement:", effects)
 	}
 }
-
@@ -0,0 +1,106 @@
+package sql
+
+import (
+	"hash/fnv"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Fingerprint returns the normalized form of stmt and its 64-bit FNV-1a hash.
+// Statements that differ only in literal values, bind parameters, the number of
+// values of an IN list, the case of unquoted identifiers, comments or
+// whitespace have the same fingerprint.
+//
+// String, number & blob literals and bind parameters are replaced by "?".
+// Integers that select a result column in ORDER BY & GROUP BY are kept. IN
+// lists & VALUES rows that are equal after normalization are collapsed to
+// one. The normalized form is formatted on a single line with minimal quoting.
+func Fingerprint(stmt Statement) (normalized string, hash uint64) {
+	n := Apply(Clone(stmt), normalizePre, normalizePost)
+	normalized = Format(n, FormatOptions{MaxWidth: math.MaxInt, MinimalQuoting: true})
+	if normalized == "" {
+		// Never hash distinct statements to the same empty form.
+		normalized = n.String()
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(normalized))
+	return normalized, h.Sum64()
+}
+
+// commentSetter is implemented by nodes that hold comments.
+type commentSetter interface {
+	SetLeadingComments(*CommentGroup)
+	SetTrailingComments(*CommentGroup)
+}
+
+func normalizePre(c *Cursor) bool {
+	if n, ok := c.Node().(commentSetter); ok {
+		n.SetLeadingComments(nil)
+		n.SetTrailingComments(nil)
+	}
+
+	switch n := c.Node().(type) {
+	case *Ident:
+		if !n.Quoted {
+			n.Name = strings.ToLower(n.Name)
+		}
+	case *NumberLit:
+		if _, ok := c.Parent().(*OrderingTerm); ok || c.Name() == "GroupByExprs" {
+			return true
+		}
+		replacePlaceholder(c)
+	case *StringLit, *BlobLit, *BindExpr:
+		replacePlaceholder(c)
+	case *UnaryExpr:
+		// Signed numbers are a single placeholder.
+		if _, ok := n.X.(*NumberLit); ok && (n.Op == OP_MINUS || n.Op == OP_PLUS) {
+			replacePlaceholder(c)
+			return false
+		}
+	}
+	return true
+}
+
+func normalizePost(c *Cursor) bool {
+	switch n := c.Node().(type) {
+	case *InExpr:
+		if n.Values != nil && len(n.Values.Exprs) > 1 && allEqual(n.Values.Exprs) {
+			n.Values.Exprs = n.Values.Exprs[:1]
+		}
+	case *InsertStatement:
+		if len(n.ValueLists) > 1 && allEqual(n.ValueLists) {
+			n.ValueLists = n.ValueLists[:1]
+		}
+	case *SelectStatement:
+		if len(n.ValueLists) > 1 && allEqual(n.ValueLists) {
+			n.ValueLists = n.ValueLists[:1]
+		}
+	}
+	return true
+}
+
+// replacePlaceholder replaces the current node with a "?" bind parameter if
+// the parent field can hold an expression.
+func replacePlaceholder(c *Cursor) {
+	typ := c.field().Type()
+	if c.Index() >= 0 {
+		typ = typ.Elem()
+	}
+
+	placeholder := &BindExpr{Name: "?"}
+	if reflect.TypeOf(placeholder).AssignableTo(typ) {
+		c.Replace(placeholder)
+	}
+}
+
+// allEqual reports whether all nodes of a are equal, ignoring positions.
+func allEqual[T Node](a []T) bool {
+	for _, n := range a[1:] {
+		if !Equal(a[0], n, IgnorePositions()) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package sql_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TcMits/sql"
+)
+
+func Test_Fingerprint(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		a    []string
+		want string
+	}{
+		{"Literals", []string{
+			`SELECT * FROM t WHERE a = 'x' AND b = 1 AND c = x'00' AND d = -1.5 AND e = ?`,
+			`select * from T where A = 'yy' and B = 2e10 and C = X'FFFF' and D = +3 and E = :e`,
+			`SELECT * /* comment */ FROM t -- comment
+			 WHERE a = '' AND b = 0x10 AND c = x'' AND d = 1 AND e = $e`,
+		}, `SELECT * FROM t WHERE a = ? AND b = ? AND c = ? AND d = ? AND e = ?`},
+		{"InList", []string{
+			`SELECT * FROM t WHERE id IN (1)`,
+			`SELECT * FROM t WHERE id IN (1, 2, 3, 4)`,
+			`SELECT * FROM t WHERE id IN (?, ?)`,
+		}, `SELECT * FROM t WHERE id IN (?)`},
+		{"Values", []string{
+			`INSERT INTO t (a, b) VALUES (1, 'x')`,
+			`INSERT INTO t (a, b) VALUES (1, 'x'), (2, 'y'), (3, NULL)`,
+		}, ""},
+		{"ValuesCollapsed", []string{
+			`INSERT INTO t (a, b) VALUES (1, 'x')`,
+			`INSERT INTO t (a, b) VALUES (1, 'x'), (2, 'y'), (3, 'z')`,
+		}, `INSERT INTO t (a, b) VALUES (?, ?)`},
+		{"ResultColumnIndex", []string{
+			`SELECT a, b FROM t GROUP BY 1 ORDER BY 2 DESC LIMIT 10`,
+			`SELECT a, b FROM t GROUP BY 1 ORDER BY 2 DESC LIMIT 20`,
+		}, `SELECT a, b FROM t GROUP BY 1 ORDER BY 2 DESC LIMIT ?`},
+		{"QuotedCase", []string{
+			`SELECT "Name" FROM t`,
+		}, `SELECT Name FROM t`},
+		{"Begin", []string{
+			`BEGIN`,
+			`begin transaction`,
+		}, `BEGIN`},
+		{"BeginMode", []string{
+			`BEGIN IMMEDIATE TRANSACTION`,
+			`begin immediate`,
+		}, `BEGIN IMMEDIATE`},
+		{"End", []string{
+			`END`,
+			`END TRANSACTION`,
+			`end -- comment`,
+		}, `END`},
+		{"ExplainEnd", []string{
+			`EXPLAIN END`,
+			`explain end transaction`,
+		}, `EXPLAIN END`},
+		{"Transaction", []string{
+			`BEGIN`,
+			`BEGIN DEFERRED`,
+			`BEGIN EXCLUSIVE`,
+			`COMMIT`,
+			`END`,
+			`ROLLBACK`,
+		}, ""},
+		{"RaiseMessage", []string{
+			`CREATE TRIGGER tr BEFORE DELETE ON t BEGIN SELECT RAISE(ABORT, 'no'); END`,
+		}, `CREATE TRIGGER tr BEFORE DELETE ON t BEGIN SELECT RAISE(ABORT, 'no'); END`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var first string
+			var firstHash uint64
+			for i, s := range tt.a {
+				stmt, err := sql.ParseStmtString(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				normalized, hash := sql.Fingerprint(stmt)
+				if i == 0 {
+					first, firstHash = normalized, hash
+				}
+
+				// An empty want marks statements that must differ.
+				if tt.want == "" {
+					if i > 0 && (normalized == first || hash == firstHash) {
+						t.Fatalf("Fingerprint(%q)=%q, want different from %q", s, normalized, first)
+					}
+					continue
+				}
+				if normalized != tt.want {
+					t.Fatalf("Fingerprint(%q)=%q, want %q", s, normalized, tt.want)
+				} else if hash != firstHash {
+					t.Fatalf("Fingerprint(%q): hash %x, want %x", s, hash, firstHash)
+				}
+			}
+		})
+	}
+}
+
+// Ensure the statement is left unchanged.
+func Test_Fingerprint_Clone(t *testing.T) {
+	stmt, err := sql.ParseStmtString(`SELECT A FROM t WHERE b IN (1, 2)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql.Fingerprint(stmt)
+	if got, want := stmt.String(), `SELECT "A" FROM "t" WHERE "b" IN (1, 2)`; got != want {
+		t.Fatalf("String()=%s, want %s", got, want)
+	}
+}
+
+// Ensure the normalized form of the test data statements can be parsed.
+func Test_Fingerprint_TestData(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) > 500 {
+		files = files[:500]
+	}
+	for _, file := range files {
+		buf, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = sql.ParseMultiStmtString(string(buf), func(stmt sql.Statement) error {
+			normalized, _ := sql.Fingerprint(stmt)
+			if normalized == "" {
+				t.Errorf("%s: empty fingerprint for %q", file, stmt.String())
+			} else if _, err := sql.ParseStmtString(normalized); err != nil {
+				t.Errorf("%s: cannot parse %q: %s", file, normalized, err)
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,676 @@
+package sql
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// KeywordCase specifies how Format prints keywords.
+type KeywordCase int
+
+const (
+	KeywordUpper KeywordCase = iota // SELECT
+	KeywordLower                    // select
+)
+
+// FormatOptions configures the output of Format.
+type FormatOptions struct {
+	Indent         string      // indentation of nested lines, two spaces if empty
+	MaxWidth       int         // maximum line width, 80 if zero
+	ClausePerLine  bool        // start every clause on a new line, even if the statement fits
+	KeywordCase    KeywordCase // case of keywords
+	LeadingCommas  bool        // place commas at the start of continued list lines
+	MinimalQuoting bool        // quote identifiers only if required
+}
+
+// Format returns the formatted SQL text of n. Clauses start on a new line
+// and lists are broken one element per line when they do not fit within
+// opts.MaxWidth. Comments attached to the nodes are kept. Parsing the output
+// produces a tree equal to n, ignoring positions and identifier quoting.
+func Format(n Node, opts FormatOptions) string {
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	if opts.MaxWidth <= 0 {
+		opts.MaxWidth = 80
+	}
+
+	f := formatter{opts: opts, toks: formatTokens(n.String(), opts)}
+	f.trigger = isCreateTrigger(f.toks)
+	d := f.query()
+
+	p := docPrinter{indent: opts.Indent, width: opts.MaxWidth}
+	p.print(d)
+	return p.buf.String()
+}
+
+// formatToken is a token of the canonical string of a node.
+type formatToken struct {
+	tok   Token
+	text  string // text to print
+	space bool   // true if preceded by whitespace
+}
+
+// formatTokens scans src and returns its tokens with keyword case and
+// identifier quoting adjusted according to opts.
+func formatTokens(src string, opts FormatOptions) []formatToken {
+	var toks []formatToken
+	s := NewScanner(src)
+	end := 0
+	for {
+		pos, tok, _ := s.Scan()
+		if tok == EOF {
+			break
+		}
+
+		t := formatToken{
+			tok:   tok,
+			text:  src[pos.GetOffset():s.pos.GetOffset()],
+			space: pos.GetOffset() > end,
+		}
+		end = s.pos.GetOffset()
+
+		switch {
+		case tok == COMMENT:
+			t.text = strings.TrimRight(t.text, " \t\r\n")
+		case tok == QIDENT && opts.MinimalQuoting:
+			// An identifier named x followed by a string would scan as a blob.
+			if name, ok := unquoteIdent(t.text); ok && s.peek() != '\'' {
+				t.text = name
+			}
+		case isFormatKeyword(tok, t.text) && opts.KeywordCase == KeywordLower:
+			// NULL following a type name is part of the type name.
+			if tok != NULL || len(toks) == 0 || toks[len(toks)-1].tok != IDENT {
+				t.text = strings.ToLower(t.text)
+			}
+		}
+		toks = append(toks, t)
+	}
+	return toks
+}
+
+// isFormatKeyword returns true if text is a keyword whose case can be changed
+// without changing the parsed tree.
+func isFormatKeyword(tok Token, text string) bool {
+	switch tok {
+	case IDENT, QIDENT, STRING, BLOB, FLOAT, INTEGER, BIND, COMMENT, CURRENT_TIME, CURRENT_DATE, CURRENT_TIMESTAMP:
+		return false
+	}
+	return text != "" && isAlpha(text[0])
+}
+
+// unquoteIdent returns the name of the quoted identifier text if it can be
+// written without quotes.
+func unquoteIdent(text string) (string, bool) {
+	if len(text) < 3 {
+		return "", false
+	}
+	name := text[1 : len(text)-1]
+	if !isAlpha(name[0]) && name[0] != '_' && !isNonASCII(name[0]) {
+		return "", false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isUnquotedIdent(name[i]) {
+			return "", false
+		}
+	}
+	if keywordOrIdent(name) != IDENT {
+		return "", false
+	}
+	return name, true
+}
+
+// isCreateTrigger returns true if toks are the tokens of a CREATE TRIGGER
+// statement, possibly explained. Only such statements have a body between
+// BEGIN & END.
+func isCreateTrigger(toks []formatToken) bool {
+	for _, t := range toks {
+		switch t.tok {
+		case COMMENT, EXPLAIN, QUERY, PLAN, CREATE, TEMP, TEMPORARY:
+		case TRIGGER:
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// formatter builds a document from the tokens of a node.
+type formatter struct {
+	opts FormatOptions
+	toks []formatToken
+	i    int
+	head Token // first keyword of the current clause
+
+	trigger bool // true if formatting a CREATE TRIGGER statement
+	body    bool // true within the body of a trigger
+}
+
+func (f *formatter) peek(n int) Token {
+	if f.i+n < len(f.toks) {
+		return f.toks[f.i+n].tok
+	}
+	return EOF
+}
+
+func (f *formatter) prev() Token {
+	if f.i > 0 {
+		return f.toks[f.i-1].tok
+	}
+	return ILLEGAL
+}
+
+func (f *formatter) text() doc {
+	t := f.toks[f.i]
+	f.i++
+	if t.tok == COMMENT && isLineComment(t.text) {
+		return docs{docText(t.text), docLine{hard: true}}
+	}
+	return docText(t.text)
+}
+
+// query returns the document of a sequence of clauses that ends at the end of
+// input, a right paren, a semicolon or the END of a trigger body.
+func (f *formatter) query() doc {
+	saved := f.head
+	defer func() { f.head = saved }()
+
+	// Leading comments do not force the clauses onto separate lines.
+	lead := f.comments()
+	if len(lead) > 0 && f.atQueryEnd() {
+		return lead
+	}
+
+	sep := docLine{flat: " ", hard: f.opts.ClausePerLine}
+	var d docs
+	start := f.i
+	for !f.atQueryEnd() {
+		if f.i != start {
+			d = append(d, sep)
+		}
+		i := f.i
+		d = append(d, f.clause(start))
+		if f.i == i {
+			d = append(d, f.text()) // unexpected token
+		}
+	}
+	if len(lead) > 0 {
+		return docs{lead, docText(" "), docGroup{d}}
+	}
+	return docGroup{d}
+}
+
+// comments returns the document of the comments at the current token.
+func (f *formatter) comments() docs {
+	var d docs
+	for f.peek(0) == COMMENT {
+		if len(d) > 0 && f.toks[f.i].space {
+			d = append(d, docText(" "))
+		}
+		d = append(d, f.text())
+	}
+	return d
+}
+
+func (f *formatter) atQueryEnd() bool {
+	switch f.peek(0) {
+	case EOF, RP, SEMI:
+		return true
+	case END:
+		// END is otherwise a statement of its own.
+		return f.body
+	}
+	return false
+}
+
+// clause returns the document of a clause keyword followed by its items.
+// Tokens before the first clause keyword form a clause without keyword.
+func (f *formatter) clause(start int) doc {
+	if lead := f.comments(); len(lead) > 0 {
+		if f.atQueryEnd() {
+			return lead
+		}
+		return docs{lead, docText(" "), f.clause(start)}
+	}
+
+	var head docs
+	f.head = ILLEGAL
+	if n := f.clauseStart(start); n > 0 {
+		f.head = f.peek(0)
+		for j := 0; j < n; j++ {
+			if j > 0 {
+				head = append(head, docText(" "))
+			}
+			head = append(head, f.text())
+		}
+	}
+
+	var items []formatItem
+	for len(items) == 0 || f.peek(0) == COMMA {
+		if len(items) > 0 {
+			f.i++ // comma
+		}
+		if f.atQueryEnd() || f.clauseStart(start) > 0 {
+			break
+		}
+		items = append(items, f.item(start, true))
+	}
+
+	switch {
+	case len(head) == 0:
+		return docGroup{f.list(items)}
+	case len(items) == 0:
+		return head
+	case len(items) == 1:
+		return docGroup{docs{head, docText(" "), f.list(items)}}
+	default:
+		return docGroup{docs{head, docNest{docs{docLine{flat: " "}, f.list(items)}}}}
+	}
+}
+
+// list returns items separated by commas.
+func (f *formatter) list(items []formatItem) doc {
+	var d docs
+	for i, item := range items {
+		if i > 0 && f.opts.LeadingCommas {
+			d = append(d, docLine{}, docText(", "))
+		}
+		d = append(d, item.body)
+		if i < len(items)-1 && !f.opts.LeadingCommas {
+			d = append(d, docText(","))
+		}
+		if item.comment != nil {
+			d = append(d, item.comment)
+		}
+		if i < len(items)-1 && !f.opts.LeadingCommas {
+			d = append(d, docLine{flat: " "})
+		}
+	}
+	return d
+}
+
+// clauseStart returns the number of keyword tokens that start a clause at the
+// current token, or zero if no clause starts there. start is the index of the
+// first token of the query.
+func (f *formatter) clauseStart(start int) int {
+	switch f.peek(0) {
+	case WITH:
+		if f.peek(1) == RECURSIVE {
+			return 2
+		}
+		return 1
+	case SELECT:
+		if f.peek(1) == DISTINCT || f.peek(1) == ALL {
+			return 2
+		}
+		return 1
+	case VALUES:
+		if f.prev() == DEFAULT {
+			return 0
+		}
+		return 1
+	case FROM:
+		if f.prev() == DISTINCT {
+			return 0
+		}
+		return 1
+	case WHERE, HAVING, WINDOW, LIMIT, RETURNING:
+		return 1
+	case GROUP, ORDER:
+		return 2
+	case UNION:
+		if f.peek(1) == ALL {
+			return 2
+		}
+		return 1
+	case INTERSECT, EXCEPT:
+		return 1
+	case SET:
+		if f.head == UPDATE || f.prev() == UPDATE {
+			return 1
+		}
+	case ON:
+		if f.peek(1) == CONFLICT && f.head != ILLEGAL {
+			return 2
+		}
+	case DELETE:
+		if f.atStatementStart(start) {
+			return 2
+		}
+	case INSERT, REPLACE, UPDATE:
+		if f.atStatementStart(start) {
+			return 1
+		}
+	}
+	return 0
+}
+
+// atStatementStart returns true if a DML statement can start at the current
+// token, which is the case at the start of the query, after EXPLAIN and
+// after a WITH clause.
+func (f *formatter) atStatementStart(start int) bool {
+	switch {
+	case f.i == start:
+		return true
+	case f.prev() == EXPLAIN, f.prev() == PLAN:
+		return true
+	case f.prev() == RP && f.head == WITH:
+		return true
+	}
+	return false
+}
+
+// formatItem is a list item with its trailing comments kept apart, so that
+// a separating comma can be placed before them.
+type formatItem struct {
+	body    doc
+	comment doc
+}
+
+// item returns a list item. Lines are broken before AND & OR operators and
+// before join operators; the continued lines are indented. If query is true,
+// the item ends at the start of the next clause.
+func (f *formatter) item(start int, query bool) formatItem {
+	type part struct {
+		sep     doc // separator before the part, or nil
+		brk     bool
+		doc     doc
+		comment bool
+	}
+
+	var parts []part
+	var cases int
+	var between bool
+loop:
+	for {
+		tok := f.peek(0)
+		switch tok {
+		case EOF, COMMA, RP, SEMI:
+			break loop
+		case END:
+			if cases == 0 && f.body {
+				break loop
+			}
+		}
+		if query && cases == 0 && len(parts) > 0 && f.clauseStart(start) > 0 {
+			break
+		}
+
+		var p part
+		if len(parts) > 0 {
+			switch {
+			case (tok == AND && !between) || tok == OR:
+				p.sep, p.brk = docLine{flat: " "}, true
+			case f.head == FROM && isJoinStart(tok, f.prev()):
+				p.sep, p.brk = docLine{flat: " "}, true
+			case f.toks[f.i].space:
+				p.sep = docText(" ")
+			}
+		}
+
+		switch tok {
+		case CASE:
+			cases++
+		case END:
+			cases = max(cases-1, 0)
+		case BETWEEN:
+			between = true
+		case AND:
+			between = false
+		}
+
+		switch {
+		case tok == LP:
+			p.doc = f.paren()
+		case tok == BEGIN && f.trigger:
+			p.doc = f.triggerBody()
+		default:
+			p.comment = tok == COMMENT
+			p.doc = f.text()
+		}
+		parts = append(parts, p)
+	}
+
+	n := len(parts)
+	for n > 0 && parts[n-1].comment {
+		n--
+	}
+
+	var item formatItem
+	var body, cont, comment docs
+	for _, p := range parts[:n] {
+		if p.brk || len(cont) > 0 {
+			cont = appendDoc(cont, p.sep, p.doc)
+		} else {
+			body = appendDoc(body, p.sep, p.doc)
+		}
+	}
+	if len(cont) > 0 {
+		body = append(body, docNest{cont})
+	}
+	for _, p := range parts[n:] {
+		comment = appendDoc(comment, p.sep, p.doc)
+	}
+
+	item.body = body
+	if len(comment) > 0 {
+		item.comment = comment
+	}
+	return item
+}
+
+// appendDoc appends the non-nil documents to d.
+func appendDoc(d docs, a ...doc) docs {
+	for _, x := range a {
+		if x != nil {
+			d = append(d, x)
+		}
+	}
+	return d
+}
+
+// isJoinStart returns true if tok starts a join operator.
+func isJoinStart(tok, prev Token) bool {
+	switch tok {
+	case NATURAL:
+		return true
+	case LEFT, RIGHT, FULL, INNER, CROSS:
+		return prev != NATURAL
+	case JOIN:
+		switch prev {
+		case NATURAL, LEFT, RIGHT, FULL, OUTER, INNER, CROSS:
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// paren returns the document of a parenthesized query or list.
+func (f *formatter) paren() doc {
+	open := f.text()
+
+	var inner doc
+	switch f.peek(0) {
+	case SELECT, VALUES, WITH:
+		inner = docNest{docs{docLine{}, f.query()}}
+	default:
+		var items []formatItem
+		for {
+			items = append(items, f.item(f.i, false))
+			if f.peek(0) != COMMA {
+				break
+			}
+			f.i++
+		}
+		inner = docNest{docs{docLine{}, f.list(items)}}
+	}
+
+	d := docs{open, inner, docLine{}}
+	if f.peek(0) == RP {
+		d = append(d, f.text())
+	}
+	return docGroup{d}
+}
+
+// triggerBody returns the document of the statements between BEGIN & END.
+func (f *formatter) triggerBody() doc {
+	f.body = true
+	defer func() { f.body = false }()
+
+	d := docs{f.text()}
+	var body docs
+	for f.peek(0) != END && f.peek(0) != EOF {
+		i := f.i
+		body = append(body, docLine{flat: " "}, f.query())
+		if f.peek(0) == SEMI {
+			body = append(body, f.text())
+		} else if f.i == i {
+			break
+		}
+	}
+	d = append(d, docNest{body}, docLine{flat: " "})
+	if f.peek(0) == END {
+		d = append(d, f.text())
+	}
+	return docGroup{d}
+}
+
+// doc is a document laid out by docPrinter. It is one of docText, docLine,
+// docs, docNest or docGroup.
+type doc any
+
+// docText is printed as is.
+type docText string
+
+// docLine is printed as flat if its group fits on the line, otherwise as a
+// line break. Hard lines are always printed as a line break.
+type docLine struct {
+	flat string
+	hard bool
+}
+
+// docs is a concatenation of documents.
+type docs []doc
+
+// docNest indents the line breaks within its document by one level.
+type docNest struct{ doc doc }
+
+// docGroup is printed flat if it fits on the line.
+type docGroup struct{ doc doc }
+
+type docCmd struct {
+	level int
+	flat  bool
+	doc   doc
+}
+
+// docPrinter prints documents within a maximum line width.
+type docPrinter struct {
+	buf     strings.Builder
+	indent  string
+	width   int
+	col     int
+	pending int  // indentation level of a pending line start
+	newline bool // true if a line break was printed but no text since
+}
+
+func (p *docPrinter) print(d doc) {
+	stack := []docCmd{{doc: d}}
+	for len(stack) > 0 {
+		cmd := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch d := cmd.doc.(type) {
+		case docText:
+			p.text(string(d))
+		case docLine:
+			if cmd.flat && !d.hard {
+				p.text(d.flat)
+			} else {
+				p.lineBreak(cmd.level)
+			}
+		case docs:
+			for i := len(d) - 1; i >= 0; i-- {
+				stack = append(stack, docCmd{cmd.level, cmd.flat, d[i]})
+			}
+		case docNest:
+			stack = append(stack, docCmd{cmd.level + 1, cmd.flat, d.doc})
+		case docGroup:
+			flat := cmd.flat || p.fits(docCmd{cmd.level, true, d.doc}, stack)
+			stack = append(stack, docCmd{cmd.level, flat, d.doc})
+		}
+	}
+	if p.newline {
+		p.buf.WriteString("\n")
+	}
+}
+
+func (p *docPrinter) text(s string) {
+	if s == "" {
+		return
+	}
+	if p.newline {
+		p.buf.WriteString("\n")
+		p.buf.WriteString(strings.Repeat(p.indent, p.pending))
+		p.col = utf8.RuneCountInString(p.indent) * p.pending
+		p.newline = false
+		if s == " " {
+			return
+		}
+	}
+	p.buf.WriteString(s)
+	p.col += utf8.RuneCountInString(s)
+}
+
+// lineBreak starts a new line at the given indentation level. Consecutive
+// line breaks are merged so a line comment followed by a line break does not
+// produce an empty line.
+func (p *docPrinter) lineBreak(level int) {
+	p.newline = true
+	p.pending = level
+}
+
+// fits returns true if cmd printed flat fits on the rest of the current line,
+// followed by the documents on the stack up to their next line break.
+func (p *docPrinter) fits(cmd docCmd, stack []docCmd) bool {
+	rem := p.width - p.col
+	if p.newline {
+		rem = p.width - utf8.RuneCountInString(p.indent)*p.pending
+	}
+
+	cmds := []docCmd{cmd}
+	for rem >= 0 {
+		if len(cmds) == 0 {
+			if len(stack) == 0 {
+				return true
+			}
+			cmds = append(cmds, stack[len(stack)-1])
+			stack = stack[:len(stack)-1]
+		}
+
+		c := cmds[len(cmds)-1]
+		cmds = cmds[:len(cmds)-1]
+		switch d := c.doc.(type) {
+		case docText:
+			rem -= utf8.RuneCountInString(string(d))
+		case docLine:
+			if d.hard {
+				return !c.flat
+			} else if !c.flat {
+				return true
+			}
+			rem -= utf8.RuneCountInString(d.flat)
+		case docs:
+			for i := len(d) - 1; i >= 0; i-- {
+				cmds = append(cmds, docCmd{c.level, c.flat, d[i]})
+			}
+		case docNest:
+			cmds = append(cmds, docCmd{c.level + 1, c.flat, d.doc})
+		case docGroup:
+			cmds = append(cmds, docCmd{c.level, c.flat, d.doc})
+		}
+	}
+	return false
+}
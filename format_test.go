@@ -0,0 +1,159 @@
+package sql_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TcMits/sql"
+)
+
+func Test_Format(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		in   string
+		opts sql.FormatOptions
+		want string
+	}{
+		{
+			name: "Short",
+			in:   `SELECT a FROM t`,
+			want: `SELECT "a" FROM "t"`,
+		},
+		{
+			name: "Clauses",
+			in:   `WITH recent AS (SELECT id, name FROM users WHERE created_at > 100) SELECT r.id, r.name FROM recent AS r WHERE r.id > 10 ORDER BY r.name DESC LIMIT 10`,
+			want: "WITH \"recent\" AS (SELECT \"id\", \"name\" FROM \"users\" WHERE \"created_at\" > 100)\n" +
+				"SELECT \"r\".\"id\", \"r\".\"name\"\n" +
+				"FROM \"recent\" AS \"r\"\n" +
+				"WHERE \"r\".\"id\" > 10\n" +
+				"ORDER BY \"r\".\"name\" DESC\n" +
+				"LIMIT 10",
+		},
+		{
+			name: "Comments",
+			in:   "-- leading\nSELECT a, -- first\n b FROM t WHERE x = 1 -- trailing\n",
+			want: "-- leading\nSELECT\n  \"a\", -- first\n  \"b\"\nFROM \"t\"\nWHERE \"x\" = 1 -- trailing\n",
+		},
+		{
+			name: "CreateTable",
+			in:   `CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email TEXT UNIQUE, created_at INTEGER DEFAULT 0)`,
+			want: "CREATE TABLE \"users\" (\n" +
+				"  \"id\" INTEGER PRIMARY KEY,\n" +
+				"  \"name\" TEXT NOT NULL,\n" +
+				"  \"email\" TEXT UNIQUE,\n" +
+				"  \"created_at\" INTEGER DEFAULT 0\n" +
+				")",
+		},
+		{
+			name: "SingleQuotedIdent",
+			in:   `SELECT 'x'.a FROM x`,
+			want: `SELECT 'x'."a" FROM "x"`,
+		},
+		{
+			name: "Trigger",
+			in:   `CREATE TRIGGER tr AFTER INSERT ON t BEGIN DELETE FROM a WHERE x = new.x; UPDATE b SET y = 1; END`,
+			want: "CREATE TRIGGER \"tr\" AFTER INSERT ON \"t\" BEGIN\n" +
+				"  DELETE FROM \"a\" WHERE \"x\" = \"new\".\"x\";\n" +
+				"  UPDATE \"b\" SET \"y\" = 1;\n" +
+				"END",
+		},
+		{
+			name: "ClausePerLine",
+			in:   `SELECT a, b, c FROM t WHERE a = 1 AND b = 2`,
+			opts: sql.FormatOptions{ClausePerLine: true, MinimalQuoting: true},
+			want: "SELECT a, b, c\nFROM t\nWHERE a = 1 AND b = 2",
+		},
+		{
+			name: "LeadingCommas",
+			in:   `SELECT a, b, c FROM t WHERE a = 1 AND b = 2`,
+			opts: sql.FormatOptions{MaxWidth: 10, ClausePerLine: true, KeywordCase: sql.KeywordLower, LeadingCommas: true},
+			want: "select\n  \"a\"\n  , \"b\"\n  , \"c\"\nfrom \"t\"\nwhere \"a\" = 1\n  and \"b\" = 2",
+		},
+		{
+			name: "MinimalQuoting",
+			in:   `SELECT "a b", "select", "x" FROM "t"`,
+			opts: sql.FormatOptions{MinimalQuoting: true},
+			want: `SELECT "a b", "select", x FROM t`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := sql.ParseStmtString(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sql.Format(stmt, tt.opts); got != tt.want {
+				t.Fatalf("\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Format_Transaction(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want string
+	}{
+		{`BEGIN`, `BEGIN`},
+		{`BEGIN DEFERRED`, `BEGIN DEFERRED`},
+		{`BEGIN IMMEDIATE`, `BEGIN IMMEDIATE`},
+		{`BEGIN EXCLUSIVE TRANSACTION`, `BEGIN EXCLUSIVE`},
+		{`EXPLAIN BEGIN`, `EXPLAIN BEGIN`},
+		{`COMMIT`, `COMMIT`},
+		{`COMMIT TRANSACTION`, `COMMIT`},
+		{`END`, `END`},
+		{`END TRANSACTION`, `END`},
+		{`EXPLAIN END`, `EXPLAIN END`},
+		{`ROLLBACK`, `ROLLBACK`},
+		{`ROLLBACK TO SAVEPOINT sp`, `ROLLBACK TO "sp"`},
+	} {
+		t.Run(tt.in, func(t *testing.T) {
+			stmt, err := sql.ParseStmtString(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sql.Format(stmt, sql.FormatOptions{}); got != tt.want {
+				t.Fatalf("Format()=%q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_Format_TestData checks that formatting preserves the parsed tree and
+// is idempotent.
+func Test_Format_TestData(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) > 500 {
+		files = files[:500]
+	}
+
+	for _, opts := range []sql.FormatOptions{
+		{},
+		{MaxWidth: 20, ClausePerLine: true, LeadingCommas: true},
+		{KeywordCase: sql.KeywordLower, MinimalQuoting: true},
+	} {
+		for _, file := range files {
+			buf, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = sql.ParseMultiStmtString(string(buf), func(stmt sql.Statement) error {
+				out := sql.Format(stmt, opts)
+				other, err := sql.ParseStmtString(out)
+				if err != nil {
+					t.Fatalf("%s: %v\n%s", file, err, out)
+				}
+				if !sql.Equal(stmt, other, sql.IgnorePositions(), sql.IgnoreQuoting()) {
+					t.Fatalf("%s: tree changed\nin:  %s\nout: %s", file, stmt, out)
+				}
+				if again := sql.Format(other, opts); again != out {
+					t.Fatalf("%s: not idempotent\nfirst:\n%s\nsecond:\n%s", file, out, again)
+				}
+				return nil
+			})
+		}
+	}
+}
@@ -0,0 +1,849 @@
+package sql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Nodes are encoded as JSON objects holding the exported fields of the node
+// under their Go names. Fields with zero values are omitted. Every object also
+// holds a "type" member naming the node type, e.g. "SelectStatement", which is
+// used to rebuild Statement, Expr, Source, Constraint and JoinConstraint
+// fields. The source range of the node is stored in "pos" and "end" and its
+// comments in "leadingComments" and "trailingComments".
+
+// jsonComment is the JSON encoding of a Comment.
+type jsonComment struct {
+	Pos  Pos    `json:"pos"`
+	End  Pos    `json:"end"`
+	Text string `json:"text"`
+}
+
+// UnmarshalNode decodes a node encoded by MarshalJSON without knowing its type
+// in advance. It returns nil if data is the JSON null value.
+func UnmarshalNode(data []byte) (Node, error) {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil, nil
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	var typ string
+	if raw, ok := obj["type"]; !ok {
+		return nil, errors.New("sql: missing node type")
+	} else if err := json.Unmarshal(raw, &typ); err != nil {
+		return nil, err
+	}
+
+	n := newNode(typ)
+	if n == nil {
+		return nil, fmt.Errorf("sql: unknown node type %q", typ)
+	}
+	if err := n.(json.Unmarshaler).UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func marshalNode(n Node) ([]byte, error) {
+	v := reflect.ValueOf(n).Elem()
+	t := v.Type()
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"type":"`)
+	buf.WriteString(t.Name())
+	buf.WriteString(`"`)
+
+	var zero Pos
+	if n.Pos() != zero || n.End() != zero {
+		if err := writeJSONMember(&buf, "pos", n.Pos()); err != nil {
+			return nil, err
+		}
+		if err := writeJSONMember(&buf, "end", n.End()); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); !f.IsExported() || v.Field(i).IsZero() {
+			continue
+		}
+		if err := writeJSONMember(&buf, t.Field(i).Name, v.Field(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+
+	if g := n.LeadingComments(); g != nil {
+		if err := writeJSONMember(&buf, "leadingComments", marshalComments(g)); err != nil {
+			return nil, err
+		}
+	}
+	if g := n.TrailingComments(); g != nil {
+		if err := writeJSONMember(&buf, "trailingComments", marshalComments(g)); err != nil {
+			return nil, err
+		}
+	}
+
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+func writeJSONMember(buf *bytes.Buffer, name string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.WriteString(`,"`)
+	buf.WriteString(name)
+	buf.WriteString(`":`)
+	buf.Write(b)
+	return nil
+}
+
+func marshalComments(g *CommentGroup) []jsonComment {
+	list := make([]jsonComment, len(g.List))
+	for i, c := range g.List {
+		list[i] = jsonComment{Pos: c.Pos(), End: c.End(), Text: c.Text}
+	}
+	return list
+}
+
+func unmarshalComments(data []byte) (*CommentGroup, error) {
+	var list []jsonComment
+	if err := json.Unmarshal(data, &list); err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	g := &CommentGroup{List: make([]*Comment, len(list))}
+	for i, c := range list {
+		g.List[i] = &Comment{span: span{pos: c.Pos, end: c.End}, Text: c.Text}
+	}
+	return g, nil
+}
+
+func unmarshalNode(data []byte, n Node) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(n).Elem()
+	t := v.Type()
+	if raw, ok := obj["type"]; ok {
+		var typ string
+		if err := json.Unmarshal(raw, &typ); err != nil {
+			return err
+		} else if typ != t.Name() {
+			return fmt.Errorf("sql: cannot unmarshal %s into %s", typ, t.Name())
+		}
+	}
+	v.SetZero()
+
+	var pos, end Pos
+	if raw, ok := obj["pos"]; ok {
+		if err := json.Unmarshal(raw, &pos); err != nil {
+			return err
+		}
+	}
+	if raw, ok := obj["end"]; ok {
+		if err := json.Unmarshal(raw, &end); err != nil {
+			return err
+		}
+	}
+	n.(interface{ setSpan(Pos, Pos) }).setSpan(pos, end)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		raw, ok := obj[f.Name]
+		if !ok || !f.IsExported() {
+			continue
+		}
+		if err := unmarshalField(raw, v.Field(i)); err != nil {
+			return fmt.Errorf("sql: %s.%s: %w", t.Name(), f.Name, err)
+		}
+	}
+
+	c := n.(interface {
+		SetLeadingComments(*CommentGroup)
+		SetTrailingComments(*CommentGroup)
+	})
+	if raw, ok := obj["leadingComments"]; ok {
+		g, err := unmarshalComments(raw)
+		if err != nil {
+			return err
+		}
+		c.SetLeadingComments(g)
+	}
+	if raw, ok := obj["trailingComments"]; ok {
+		g, err := unmarshalComments(raw)
+		if err != nil {
+			return err
+		}
+		c.SetTrailingComments(g)
+	}
+	return nil
+}
+
+// unmarshalField decodes data into v. Interface fields and slices of
+// interfaces are decoded using the "type" member of each node.
+func unmarshalField(data []byte, v reflect.Value) error {
+	switch {
+	case v.Kind() == reflect.Interface:
+		n, err := UnmarshalNode(data)
+		if err != nil || n == nil {
+			return err
+		}
+		if !reflect.TypeOf(n).AssignableTo(v.Type()) {
+			return fmt.Errorf("%T is not a %s", n, v.Type().Name())
+		}
+		v.Set(reflect.ValueOf(n))
+		return nil
+
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Interface:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil || elems == nil {
+			return err
+		}
+		s := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+		for i, elem := range elems {
+			if err := unmarshalField(elem, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+
+	default:
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+}
+
+// newNode returns a new zero node of the named type, or nil if the type is unknown.
+func newNode(typ string) Node {
+	switch typ {
+	case "ExplainStatement":
+		return &ExplainStatement{}
+	case "BeginStatement":
+		return &BeginStatement{}
+	case "CommitStatement":
+		return &CommitStatement{}
+	case "RollbackStatement":
+		return &RollbackStatement{}
+	case "SavepointStatement":
+		return &SavepointStatement{}
+	case "ReleaseStatement":
+		return &ReleaseStatement{}
+	case "CreateTableStatement":
+		return &CreateTableStatement{}
+	case "ColumnDefinition":
+		return &ColumnDefinition{}
+	case "PrimaryKeyConstraint":
+		return &PrimaryKeyConstraint{}
+	case "NotNullConstraint":
+		return &NotNullConstraint{}
+	case "UniqueConstraint":
+		return &UniqueConstraint{}
+	case "CheckConstraint":
+		return &CheckConstraint{}
+	case "DefaultConstraint":
+		return &DefaultConstraint{}
+	case "GeneratedConstraint":
+		return &GeneratedConstraint{}
+	case "CollateConstraint":
+		return &CollateConstraint{}
+	case "ForeignKeyConstraint":
+		return &ForeignKeyConstraint{}
+	case "ForeignKeyArg":
+		return &ForeignKeyArg{}
+	case "CreateVirtualTableStatement":
+		return &CreateVirtualTableStatement{}
+	case "ModuleArgument":
+		return &ModuleArgument{}
+	case "AnalyzeStatement":
+		return &AnalyzeStatement{}
+	case "ReindexStatement":
+		return &ReindexStatement{}
+	case "AlterTableStatement":
+		return &AlterTableStatement{}
+	case "Ident":
+		return &Ident{}
+	case "Type":
+		return &Type{}
+	case "StringLit":
+		return &StringLit{}
+	case "TimestampLit":
+		return &TimestampLit{}
+	case "BlobLit":
+		return &BlobLit{}
+	case "NumberLit":
+		return &NumberLit{}
+	case "NullLit":
+		return &NullLit{}
+	case "BoolLit":
+		return &BoolLit{}
+	case "BindExpr":
+		return &BindExpr{}
+	case "UnaryExpr":
+		return &UnaryExpr{}
+	case "BinaryExpr":
+		return &BinaryExpr{}
+	case "CastExpr":
+		return &CastExpr{}
+	case "CaseExpr":
+		return &CaseExpr{}
+	case "CaseBlock":
+		return &CaseBlock{}
+	case "Raise":
+		return &Raise{}
+	case "Exists":
+		return &Exists{}
+	case "Null":
+		return &Null{}
+	case "ExprList":
+		return &ExprList{}
+	case "QualifiedRef":
+		return &QualifiedRef{}
+	case "Call":
+		return &Call{}
+	case "OrderingTerm":
+		return &OrderingTerm{}
+	case "FrameSpec":
+		return &FrameSpec{}
+	case "DropTableStatement":
+		return &DropTableStatement{}
+	case "CreateViewStatement":
+		return &CreateViewStatement{}
+	case "DropViewStatement":
+		return &DropViewStatement{}
+	case "CreateIndexStatement":
+		return &CreateIndexStatement{}
+	case "DropIndexStatement":
+		return &DropIndexStatement{}
+	case "CreateTriggerStatement":
+		return &CreateTriggerStatement{}
+	case "DropTriggerStatement":
+		return &DropTriggerStatement{}
+	case "InsertStatement":
+		return &InsertStatement{}
+	case "UpsertClause":
+		return &UpsertClause{}
+	case "UpdateStatement":
+		return &UpdateStatement{}
+	case "DeleteStatement":
+		return &DeleteStatement{}
+	case "Assignment":
+		return &Assignment{}
+	case "IndexedColumn":
+		return &IndexedColumn{}
+	case "SelectStatement":
+		return &SelectStatement{}
+	case "ResultColumn":
+		return &ResultColumn{}
+	case "QualifiedName":
+		return &QualifiedName{}
+	case "ParenSource":
+		return &ParenSource{}
+	case "JoinClause":
+		return &JoinClause{}
+	case "JoinOperator":
+		return &JoinOperator{}
+	case "OnConstraint":
+		return &OnConstraint{}
+	case "UsingConstraint":
+		return &UsingConstraint{}
+	case "WithClause":
+		return &WithClause{}
+	case "CTE":
+		return &CTE{}
+	case "Window":
+		return &Window{}
+	case "WindowDefinition":
+		return &WindowDefinition{}
+	case "PragmaStatement":
+		return &PragmaStatement{}
+	case "AttachStatement":
+		return &AttachStatement{}
+	case "DetachStatement":
+		return &DetachStatement{}
+	case "VacuumStatement":
+		return &VacuumStatement{}
+	case "ConflictClause":
+		return &ConflictClause{}
+	case "FunctionArg":
+		return &FunctionArg{}
+	case "InExpr":
+		return &InExpr{}
+	case "ParenExpr":
+		return &ParenExpr{}
+	default:
+		return nil
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *ExplainStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *ExplainStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *BeginStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *BeginStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *CommitStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *CommitStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *RollbackStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *RollbackStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *SavepointStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *SavepointStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *ReleaseStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *ReleaseStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *CreateTableStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *CreateTableStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *ColumnDefinition) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *ColumnDefinition) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *PrimaryKeyConstraint) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *PrimaryKeyConstraint) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *NotNullConstraint) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *NotNullConstraint) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *UniqueConstraint) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *UniqueConstraint) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *CheckConstraint) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *CheckConstraint) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *DefaultConstraint) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *DefaultConstraint) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *GeneratedConstraint) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *GeneratedConstraint) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *CollateConstraint) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *CollateConstraint) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *ForeignKeyConstraint) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *ForeignKeyConstraint) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *ForeignKeyArg) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *ForeignKeyArg) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *CreateVirtualTableStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *CreateVirtualTableStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (a *ModuleArgument) MarshalJSON() ([]byte, error) { return marshalNode(a) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (a *ModuleArgument) UnmarshalJSON(data []byte) error { return unmarshalNode(data, a) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *AnalyzeStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *AnalyzeStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *ReindexStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *ReindexStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *AlterTableStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *AlterTableStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (i *Ident) MarshalJSON() ([]byte, error) { return marshalNode(i) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (i *Ident) UnmarshalJSON(data []byte) error { return unmarshalNode(data, i) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t *Type) MarshalJSON() ([]byte, error) { return marshalNode(t) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *Type) UnmarshalJSON(data []byte) error { return unmarshalNode(data, t) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (lit *StringLit) MarshalJSON() ([]byte, error) { return marshalNode(lit) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (lit *StringLit) UnmarshalJSON(data []byte) error { return unmarshalNode(data, lit) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (lit *TimestampLit) MarshalJSON() ([]byte, error) { return marshalNode(lit) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (lit *TimestampLit) UnmarshalJSON(data []byte) error { return unmarshalNode(data, lit) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (lit *BlobLit) MarshalJSON() ([]byte, error) { return marshalNode(lit) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (lit *BlobLit) UnmarshalJSON(data []byte) error { return unmarshalNode(data, lit) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (lit *NumberLit) MarshalJSON() ([]byte, error) { return marshalNode(lit) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (lit *NumberLit) UnmarshalJSON(data []byte) error { return unmarshalNode(data, lit) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (lit *NullLit) MarshalJSON() ([]byte, error) { return marshalNode(lit) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (lit *NullLit) UnmarshalJSON(data []byte) error { return unmarshalNode(data, lit) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (lit *BoolLit) MarshalJSON() ([]byte, error) { return marshalNode(lit) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (lit *BoolLit) UnmarshalJSON(data []byte) error { return unmarshalNode(data, lit) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (expr *BindExpr) MarshalJSON() ([]byte, error) { return marshalNode(expr) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (expr *BindExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, expr) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (expr *UnaryExpr) MarshalJSON() ([]byte, error) { return marshalNode(expr) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (expr *UnaryExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, expr) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (expr *BinaryExpr) MarshalJSON() ([]byte, error) { return marshalNode(expr) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (expr *BinaryExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, expr) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (expr *CastExpr) MarshalJSON() ([]byte, error) { return marshalNode(expr) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (expr *CastExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, expr) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (expr *CaseExpr) MarshalJSON() ([]byte, error) { return marshalNode(expr) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (expr *CaseExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, expr) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b *CaseBlock) MarshalJSON() ([]byte, error) { return marshalNode(b) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *CaseBlock) UnmarshalJSON(data []byte) error { return unmarshalNode(data, b) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (r *Raise) MarshalJSON() ([]byte, error) { return marshalNode(r) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *Raise) UnmarshalJSON(data []byte) error { return unmarshalNode(data, r) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (expr *Exists) MarshalJSON() ([]byte, error) { return marshalNode(expr) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (expr *Exists) UnmarshalJSON(data []byte) error { return unmarshalNode(data, expr) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (expr *Null) MarshalJSON() ([]byte, error) { return marshalNode(expr) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (expr *Null) UnmarshalJSON(data []byte) error { return unmarshalNode(data, expr) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (l *ExprList) MarshalJSON() ([]byte, error) { return marshalNode(l) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (l *ExprList) UnmarshalJSON(data []byte) error { return unmarshalNode(data, l) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (r *QualifiedRef) MarshalJSON() ([]byte, error) { return marshalNode(r) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *QualifiedRef) UnmarshalJSON(data []byte) error { return unmarshalNode(data, r) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *Call) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *Call) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t *OrderingTerm) MarshalJSON() ([]byte, error) { return marshalNode(t) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *OrderingTerm) UnmarshalJSON(data []byte) error { return unmarshalNode(data, t) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *FrameSpec) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *FrameSpec) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *DropTableStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *DropTableStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *CreateViewStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *CreateViewStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *DropViewStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *DropViewStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *CreateIndexStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *CreateIndexStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *DropIndexStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *DropIndexStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *CreateTriggerStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *CreateTriggerStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *DropTriggerStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *DropTriggerStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *InsertStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *InsertStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *UpsertClause) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *UpsertClause) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *UpdateStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *UpdateStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *DeleteStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *DeleteStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (a *Assignment) MarshalJSON() ([]byte, error) { return marshalNode(a) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (a *Assignment) UnmarshalJSON(data []byte) error { return unmarshalNode(data, a) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *IndexedColumn) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *IndexedColumn) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *SelectStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *SelectStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *ResultColumn) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *ResultColumn) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (n *QualifiedName) MarshalJSON() ([]byte, error) { return marshalNode(n) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (n *QualifiedName) UnmarshalJSON(data []byte) error { return unmarshalNode(data, n) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *ParenSource) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *ParenSource) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *JoinClause) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *JoinClause) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (op *JoinOperator) MarshalJSON() ([]byte, error) { return marshalNode(op) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (op *JoinOperator) UnmarshalJSON(data []byte) error { return unmarshalNode(data, op) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *OnConstraint) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *OnConstraint) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *UsingConstraint) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *UsingConstraint) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *WithClause) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *WithClause) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (cte *CTE) MarshalJSON() ([]byte, error) { return marshalNode(cte) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (cte *CTE) UnmarshalJSON(data []byte) error { return unmarshalNode(data, cte) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (w *Window) MarshalJSON() ([]byte, error) { return marshalNode(w) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (w *Window) UnmarshalJSON(data []byte) error { return unmarshalNode(data, w) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d *WindowDefinition) MarshalJSON() ([]byte, error) { return marshalNode(d) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *WindowDefinition) UnmarshalJSON(data []byte) error { return unmarshalNode(data, d) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *PragmaStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *PragmaStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *AttachStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *AttachStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *DetachStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *DetachStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *VacuumStatement) MarshalJSON() ([]byte, error) { return marshalNode(s) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *VacuumStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(data, s) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c *ConflictClause) MarshalJSON() ([]byte, error) { return marshalNode(c) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *ConflictClause) UnmarshalJSON(data []byte) error { return unmarshalNode(data, c) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (a *FunctionArg) MarshalJSON() ([]byte, error) { return marshalNode(a) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (a *FunctionArg) UnmarshalJSON(data []byte) error { return unmarshalNode(data, a) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e *InExpr) MarshalJSON() ([]byte, error) { return marshalNode(e) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *InExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, e) }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e *ParenExpr) MarshalJSON() ([]byte, error) { return marshalNode(e) }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *ParenExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, e) }
@@ -0,0 +1,113 @@
+package sql_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TcMits/sql"
+	"github.com/go-test/deep"
+)
+
+func Test_JSON(t *testing.T) {
+	for _, s := range []string{
+		`WITH x AS MATERIALIZED (SELECT a FROM t) SELECT DISTINCT x.a, count(*) FILTER (WHERE b > 1) OVER w FROM x LEFT JOIN y USING (a) WHERE a NOT IN (1, 2) GROUP BY a HAVING count(*) > 1 WINDOW w AS (PARTITION BY a) ORDER BY 1 DESC LIMIT 10 OFFSET 2`,
+		`VALUES (1, 'a'), (2, x'00') UNION ALL SELECT 3, NULL`,
+		`INSERT INTO t (a, b) VALUES (1, 2) ON CONFLICT (a) DO UPDATE SET b = excluded.b RETURNING *`,
+		`UPDATE t SET a = -s.a FROM s WHERE t.id = s.id AND s.b BETWEEN 1 AND 2`,
+		`CREATE TABLE t (id INTEGER PRIMARY KEY, name TEXT NOT NULL DEFAULT 'x' CHECK (name <> ''), FOREIGN KEY (id) REFERENCES p (id) ON DELETE CASCADE)`,
+		`CREATE TRIGGER tr AFTER INSERT ON t BEGIN DELETE FROM a WHERE x = new.x; END`,
+		"-- leading\nSELECT CAST(a AS INTEGER) /* trailing */",
+	} {
+		stmt, err := sql.ParseStmtString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := json.Marshal(stmt)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+
+		got, err := sql.UnmarshalNode(data)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if diff := deep.Equal(got, stmt); diff != nil {
+			t.Fatalf("%s: %v", s, diff)
+		}
+		if !sql.Equal(got, stmt) {
+			t.Fatalf("%s: decoded statement not equal", s)
+		}
+		if got.String() != stmt.String() {
+			t.Fatalf("%s: got %s", s, got.String())
+		}
+	}
+}
+
+func Test_JSON_Encoding(t *testing.T) {
+	expr := &sql.BinaryExpr{
+		X:  &sql.Ident{Name: "a", Quoted: true, Quote: "`"},
+		Op: sql.OP_NOT_IN,
+		Y:  &sql.ExprList{Exprs: []sql.Expr{&sql.NumberLit{Value: "1"}}},
+	}
+
+	data, err := json.Marshal(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"BinaryExpr","X":{"type":"Ident","Quoted":true,"Quote":"` + "`" + `","Name":"a"},"Op":"NOT_IN","Y":{"type":"ExprList","Exprs":[{"type":"NumberLit","Value":"1"}]}}`
+	if string(data) != want {
+		t.Fatalf("got %s, want %s", data, want)
+	}
+
+	var other sql.BinaryExpr
+	if err := json.Unmarshal(data, &other); err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(&other, expr); diff != nil {
+		t.Fatal(diff)
+	}
+
+	if err := json.Unmarshal([]byte(`{"type":"Ident","Name":"a"}`), &other); err == nil {
+		t.Fatal("expected type mismatch error")
+	}
+	if _, err := sql.UnmarshalNode([]byte(`{"type":"Bogus"}`)); err == nil {
+		t.Fatal("expected unknown type error")
+	}
+	if _, err := sql.UnmarshalNode([]byte(`{"type":"BinaryExpr","X":{"type":"QualifiedName"}}`)); err == nil {
+		t.Fatal("expected interface mismatch error")
+	}
+}
+
+func Test_JSON_TestData(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) > 500 {
+		files = files[:500]
+	}
+
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = sql.ParseMultiStmtString(string(b), func(stmt sql.Statement) error {
+			data, err := json.Marshal(stmt)
+			if err != nil {
+				t.Fatalf("%s: %v", file, err)
+			}
+			got, err := sql.UnmarshalNode(data)
+			if err != nil {
+				t.Fatalf("%s: %v", file, err)
+			}
+			if !sql.Equal(got, stmt) {
+				t.Fatalf("%s: decoded statement not equal: %s", file, stmt)
+			}
+			return nil
+		})
+	}
+}
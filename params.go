@@ -0,0 +1,89 @@
+package sql
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// maxVariableNumber is the largest index of a bind parameter, the default
+// SQLITE_MAX_VARIABLE_NUMBER.
+const maxVariableNumber = 32766
+
+// Param is a bind parameter of a statement.
+type Param struct {
+	Index int         // index used to bind the parameter, starting at 1
+	Name  string      // name with its bind character, such as ":foo" or "?2", empty for "?"
+	Exprs []*BindExpr // occurrences of the parameter in source order
+}
+
+// Params returns the bind parameters within n ordered by index.
+//
+// Parameters are numbered like SQLite does, in source order: "?" takes the
+// index following the largest index assigned so far, "?NNN" takes index NNN,
+// and ":name", "@name" & "$name" take the index of the first occurrence of
+// the same name, or else the index following the largest one. Indexes that no
+// parameter refers to, such as 1 in "SELECT ?2", are omitted.
+//
+// Indexes out of range are returned as an ErrorList, along with the parameters
+// that could be numbered.
+func Params(n Node) ([]*Param, error) {
+	var exprs []*BindExpr
+	Walk(n, func(n Node) bool {
+		if expr, ok := n.(*BindExpr); ok {
+			exprs = append(exprs, expr)
+		}
+		return true
+	})
+	slices.SortStableFunc(exprs, func(a, b *BindExpr) int {
+		return a.Pos().GetOffset() - b.Pos().GetOffset()
+	})
+
+	var (
+		params []*Param
+		errs   ErrorList
+		last   int // largest index assigned so far
+	)
+	byIndex := make(map[int]*Param)
+	byName := make(map[string]int)
+	for _, expr := range exprs {
+		name := expr.name()
+
+		var index int
+		switch {
+		case name == "?":
+			index, name = last+1, ""
+			if index > maxVariableNumber {
+				errs = append(errs, &Error{Pos: expr.Pos(), Msg: "too many SQL variables"})
+				continue
+			}
+		case name[0] == '?':
+			i, err := strconv.Atoi(name[1:])
+			if err != nil || i < 1 || i > maxVariableNumber {
+				errs = append(errs, &Error{Pos: expr.Pos(), Msg: fmt.Sprintf("variable number must be between ?1 and ?%d", maxVariableNumber)})
+				continue
+			}
+			index = i
+		default:
+			if i, ok := byName[name]; ok {
+				index = i
+			} else if index = last + 1; index > maxVariableNumber {
+				errs = append(errs, &Error{Pos: expr.Pos(), Msg: "too many SQL variables"})
+				continue
+			}
+			byName[name] = index
+		}
+
+		last = max(last, index)
+		if p := byIndex[index]; p != nil {
+			p.Exprs = append(p.Exprs, expr)
+			continue
+		}
+		p := &Param{Index: index, Name: name, Exprs: []*BindExpr{expr}}
+		byIndex[index] = p
+		params = append(params, p)
+	}
+
+	slices.SortFunc(params, func(a, b *Param) int { return a.Index - b.Index })
+	return params, errs.Err()
+}
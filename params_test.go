@@ -0,0 +1,94 @@
+package sql_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TcMits/sql"
+	"github.com/go-test/deep"
+)
+
+func Test_Params(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want []string // index, name & number of occurrences
+	}{
+		{`SELECT 1`, nil},
+		{`SELECT ?, ?, ?`, []string{`1 "" 1`, `2 "" 1`, `3 "" 1`}},
+		{`SELECT ?2, ?, ?1`, []string{`1 "?1" 1`, `2 "?2" 1`, `3 "" 1`}},
+		{`SELECT ?3`, []string{`3 "?3" 1`}},
+		{`SELECT :a, @b, $c, :a, @a`, []string{`1 ":a" 2`, `2 "@b" 1`, `3 "$c" 1`, `4 "@a" 1`}},
+		{`SELECT :a, ?1, ?, :a`, []string{`1 ":a" 3`, `2 "" 1`}},
+		{`SELECT ?5, :a, ?5`, []string{`5 "?5" 2`, `6 ":a" 1`}},
+		{`SELECT * FROM t WHERE x IN (SELECT :a FROM u WHERE y = ?) AND z = :a`, []string{`1 ":a" 2`, `2 "" 1`}},
+		{`SELECT * FROM t LIMIT ? OFFSET ?`, []string{`1 "" 1`, `2 "" 1`}},
+		{`SELECT * FROM t LIMIT :offset, :limit`, []string{`1 ":offset" 1`, `2 ":limit" 1`}},
+		{`INSERT INTO t (a, b) VALUES (?, :b) ON CONFLICT (a) DO UPDATE SET b = :b RETURNING ?`, []string{`1 "" 1`, `2 ":b" 2`, `3 "" 1`}},
+	} {
+		t.Run(tt.s, func(t *testing.T) {
+			stmt, err := sql.ParseStmtString(tt.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			params, err := sql.Params(stmt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, p := range params {
+				got = append(got, fmt.Sprintf("%d %q %d", p.Index, p.Name, len(p.Exprs)))
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_Params_Error(t *testing.T) {
+	for _, tt := range []struct {
+		s      string
+		want   string
+		offset int
+		n      int // number of parameters returned
+	}{
+		{`SELECT ?0, ?`, "variable number must be between ?1 and ?32766", 7, 1},
+		{`SELECT 1, ?32767`, "variable number must be between ?1 and ?32766", 10, 0},
+		{`SELECT ?32766, ?`, "too many SQL variables", 15, 1},
+		{`SELECT ?32766, :a`, "too many SQL variables", 15, 1},
+	} {
+		t.Run(tt.s, func(t *testing.T) {
+			stmt, err := sql.ParseStmtString(tt.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			params, err := sql.Params(stmt)
+			errs, ok := err.(sql.ErrorList)
+			if !ok || len(errs) != 1 {
+				t.Fatalf("Params()=%v, want %q", err, tt.want)
+			} else if errs[0].Msg != tt.want || errs[0].Pos.GetOffset() != tt.offset {
+				t.Fatalf("Params()=%q at %d, want %q at %d", errs[0].Msg, errs[0].Pos.GetOffset(), tt.want, tt.offset)
+			} else if len(params) != tt.n {
+				t.Fatalf("len(Params())=%d, want %d", len(params), tt.n)
+			}
+		})
+	}
+}
+
+// Ensure parameters of nodes without positions are numbered in tree order.
+func Test_Params_NoPos(t *testing.T) {
+	stmt := &sql.SelectStatement{
+		Columns: []*sql.ResultColumn{
+			{Expr: &sql.BindExpr{Name: "a"}},
+			{Expr: &sql.BindExpr{}},
+			{Expr: &sql.BindExpr{Name: ":a"}},
+		},
+	}
+	params, err := sql.Params(stmt)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(params) != 2 || params[0].Name != ":a" || len(params[0].Exprs) != 2 || params[1].Index != 2 {
+		t.Fatalf("unexpected params: %v", params)
+	}
+}
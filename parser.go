@@ -2,6 +2,8 @@ package sql
 
 import (
 	"io"
+	"strconv"
+	"strings"
 )
 
 // Parser represents a SQL parser.
@@ -9,21 +11,82 @@ type Parser struct {
 	s Scanner
 
 	pos  Pos    // current position
+	end  Pos    // end position of current token
 	tok  Token  // current token
 	lit  string // current literal value
 	full bool   // buffer full
+
+	prevEnd Pos // end position of the token before the current token
+
+	comments    []*CommentGroup // comment groups not yet attached to a node
+	lastComment bool            // last scanned token was a comment
+
+	depth int // current nesting depth
+
+	discardComments bool // do not attach comments to nodes
+	continueOnError bool // continue after syntax errors in ParseMultiStatements
+	maxDepth        int  // maximum nesting depth, zero if unlimited
+}
+
+// Option configures a Parser.
+type Option func(*Parser)
+
+// KeepComments sets whether comments are attached to the parsed nodes.
+// Comments are kept by default.
+func KeepComments(keep bool) Option {
+	return func(p *Parser) { p.discardComments = !keep }
+}
+
+// ContinueOnError sets whether ParseMultiStatements continues after a syntax
+// error. When set, the statement containing the error is skipped and all
+// syntax errors are returned as an ErrorList once the input is exhausted.
+func ContinueOnError(cont bool) Option {
+	return func(p *Parser) { p.continueOnError = cont }
+}
+
+// MaxDepth limits the nesting depth of expressions & subqueries. Input nested
+// deeper than n fails with an error instead of exhausting the stack. A value of
+// zero, the default, means no limit.
+func MaxDepth(n int) Option {
+	return func(p *Parser) { p.maxDepth = n }
+}
+
+// NewParser returns a new Parser for src configured by opts.
+func NewParser(src string, opts ...Option) *Parser {
+	p := &Parser{s: NewScanner(src)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Reset prepares p to parse src. Options are retained.
+func (p *Parser) Reset(src string) {
+	*p = Parser{
+		s:               NewScanner(src),
+		discardComments: p.discardComments,
+		continueOnError: p.continueOnError,
+		maxDepth:        p.maxDepth,
+	}
 }
 
 // ParseStmtString parses s into a single statement.
 func ParseStmtString(s string) (Statement, error) {
-	p := Parser{s: NewScanner(s)}
-	return p.ParseStatement()
+	return NewParser(s).ParseStatement()
 }
 
 // ParseMultiStmtString parses s into multiple statements, yielding each
 func ParseMultiStmtString(s string, yield func(Statement) error) error {
-	p := Parser{s: NewScanner(s)}
-	return p.ParseMultiStatements(s, yield)
+	return NewParser(s).ParseMultiStatements(s, yield)
+}
+
+// ParseMultiStmtStringAllErrors parses s into multiple statements like
+// ParseMultiStmtString but does not stop at the first syntax error. Instead
+// the parser skips to the start of the next statement and continues. The
+// statements that parsed successfully are yielded and all syntax errors are
+// returned as an ErrorList.
+func ParseMultiStmtStringAllErrors(s string, yield func(Statement) error) error {
+	return NewParser(s, ContinueOnError(true)).ParseMultiStatements(s, yield)
 }
 
 // ParseExprString parses s into an expression. Returns nil if s is blank.
@@ -31,21 +94,132 @@ func ParseExprString(s string) (Expr, error) {
 	if s == "" {
 		return nil, nil
 	}
-	p := Parser{s: NewScanner(s)}
-	return p.ParseExpr()
+	p := NewParser(s)
+	expr, err := p.ParseExpr()
+	if err != nil {
+		return expr, err
+	}
+
+	// Comments can only trail the expression at this point.
+	p.peek()
+	attachComments(p.s.s, expr, p.comments)
+	p.comments = nil
+	return expr, nil
 }
 
+// ParseMultiStatements yields each statement of s. If s is empty, the
+// remaining statements of the source p was created or reset with are parsed
+// instead.
 func (p *Parser) ParseMultiStatements(s string, yield func(Statement) error) error {
+	if s != "" {
+		p.Reset(s)
+	}
+
+	var errs ErrorList
 	for p.peek() != EOF {
+		start := p.pos
 		stmt, err := p.ParseStatement()
 		if err != nil {
+			if e, ok := err.(*Error); ok && p.continueOnError {
+				errs = append(errs, e)
+				p.skipStatement(start)
+				continue
+			}
 			return err
 		}
 		if err := yield(stmt); err != nil {
 			return err
 		}
 	}
-	return nil
+	return errs.Err()
+}
+
+// skipStatement skips the remaining tokens of a statement beginning at start
+// that failed to parse. It stops after the next semicolon or before a token
+// that starts a statement on a new line, whichever comes first. Semicolons &
+// statements within a trigger body or CASE expression are skipped.
+func (p *Parser) skipStatement(start Pos) {
+	// Replay the tokens consumed so far to find the enclosing blocks.
+	var blk blocks
+	end := p.end
+	if p.full {
+		end = p.pos
+	}
+	if end.GetOffset() > start.GetOffset() {
+		s := NewScanner(p.s.s[start.GetOffset():end.GetOffset()])
+		for _, tok, _ := s.Scan(); tok != EOF; _, tok, _ = s.Scan() {
+			blk.next(tok)
+		}
+	}
+
+	// The failing token may be the semicolon ending the statement.
+	done := !p.full && p.tok == SEMI && !blk.nested()
+
+	// Always consume at least one token so the parser makes progress.
+	if !done && p.peek() != EOF && p.pos == start {
+		_, tok, _ := p.scan()
+		blk.next(tok)
+		done = tok == SEMI && !blk.nested()
+	}
+
+	for !done {
+		tok := p.peek()
+		if tok == EOF {
+			break
+		} else if blk.nested() {
+			// Skip the whole block.
+		} else if tok == SEMI {
+			p.scan()
+			break
+		} else if isStatementStartToken(tok) && !(tok == BEGIN && blk.trigger) &&
+			strings.Contains(p.s.s[p.prevEnd.GetOffset():p.pos.GetOffset()], "\n") {
+			break
+		}
+		p.scan()
+		blk.next(tok)
+	}
+
+	// Drop the comments of the skipped statement but keep the ones which
+	// precede the next statement.
+	p.peek()
+	for len(p.comments) > 0 && p.comments[0].Pos().GetOffset() < p.prevEnd.GetOffset() {
+		p.comments = p.comments[1:]
+	}
+}
+
+// blocks tracks the trigger body & CASE expressions enclosing a token of a
+// statement, from the tokens preceding it.
+type blocks struct {
+	started bool // true once past the leading keywords of the statement
+	trigger bool // within a CREATE TRIGGER statement, before the end of its body
+	body    bool // within the trigger body
+	cases   int  // number of enclosing CASE expressions
+}
+
+func (b *blocks) next(tok Token) {
+	switch tok {
+	case COMMENT, EXPLAIN, QUERY, PLAN, CREATE, TEMP, TEMPORARY:
+		return
+	case TRIGGER:
+		b.trigger = b.trigger || !b.started
+	case BEGIN:
+		b.body = b.body || b.trigger
+	case CASE:
+		b.cases++
+	case END:
+		if b.cases > 0 {
+			b.cases--
+		} else if b.body {
+			b.trigger, b.body = false, false
+		}
+	}
+	b.started = true
+}
+
+// nested returns true if the next token is within a trigger body or a CASE
+// expression.
+func (b *blocks) nested() bool {
+	return b.body || b.cases > 0
 }
 
 func (p *Parser) ParseStatement() (stmt Statement, err error) {
@@ -66,15 +240,38 @@ func (p *Parser) ParseStatement() (stmt Statement, err error) {
 	if tok := p.peek(); tok != EOF && tok != SEMI {
 		return stmt, p.errorExpected(p.pos, p.tok, "semicolon or EOF")
 	}
-	p.scan()
+	semi, _, _ := p.scan()
 
+	p.attachStatementComments(stmt, semi)
 	return stmt, nil
 }
 
+// attachStatementComments attaches the pending comments which belong to stmt.
+// These are the comments before the terminating semicolon at semi, and the
+// comments after it which are on the same line or are the last in the input.
+func (p *Parser) attachStatementComments(stmt Statement, semi Pos) {
+	next := p.peek()
+
+	var groups []*CommentGroup
+	i := 0
+	for ; i < len(p.comments); i++ {
+		g := p.comments[i]
+		if g.Pos().GetOffset() > semi.GetOffset() && next != EOF &&
+			strings.Contains(p.s.s[stmt.End().GetOffset():g.Pos().GetOffset()], "\n") {
+			break
+		}
+		groups = append(groups, g)
+	}
+	p.comments = p.comments[i:]
+
+	attachComments(p.s.s, stmt, groups)
+}
+
 // parseExplain parses EXPLAIN [QUERY PLAN] STMT.
 func (p *Parser) parseExplainStatement() (_ *ExplainStatement, err error) {
 	// Parse initial "EXPLAIN" token.
 	var stmt ExplainStatement
+	start := p.peekPos()
 	stmt.Explain = p.scanExpectedTok(EXPLAIN)
 
 	// Parse optional "QUERY PLAN" tokens.
@@ -91,6 +288,7 @@ func (p *Parser) parseExplainStatement() (_ *ExplainStatement, err error) {
 	if stmt.Stmt, err = p.parseNonExplainStatement(); err != nil {
 		return &stmt, err
 	}
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -166,7 +364,7 @@ func (p *Parser) parseBeginStatement() (*BeginStatement, error) {
 	assert(p.peek() == BEGIN)
 
 	var stmt BeginStatement
-	p.scan()
+	start, _, _ := p.scan()
 
 	// Parse transaction type.
 	switch p.peek() {
@@ -182,6 +380,7 @@ func (p *Parser) parseBeginStatement() (*BeginStatement, error) {
 	if p.peek() == TRANSACTION {
 		p.scan()
 	}
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -189,11 +388,13 @@ func (p *Parser) parseCommitStatement() (*CommitStatement, error) {
 	assert(p.peek() == COMMIT || p.peek() == END)
 
 	var stmt CommitStatement
-	p.scan()
+	start, tok, _ := p.scan()
+	stmt.EndKeyword = tok == END
 
 	if p.peek() == TRANSACTION {
 		p.scan()
 	}
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -201,7 +402,7 @@ func (p *Parser) parseRollbackStatement() (_ *RollbackStatement, err error) {
 	assert(p.peek() == ROLLBACK)
 
 	var stmt RollbackStatement
-	p.scan()
+	start, _, _ := p.scan()
 
 	// Parse optional "TRANSACTION".
 	if p.peek() == TRANSACTION {
@@ -219,6 +420,7 @@ func (p *Parser) parseRollbackStatement() (_ *RollbackStatement, err error) {
 			return &stmt, err
 		}
 	}
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -226,10 +428,11 @@ func (p *Parser) parseSavepointStatement() (_ *SavepointStatement, err error) {
 	assert(p.peek() == SAVEPOINT)
 
 	var stmt SavepointStatement
-	p.scan()
+	start, _, _ := p.scan()
 	if stmt.Name, err = p.parseIdent("savepoint name"); err != nil {
 		return &stmt, err
 	}
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -237,7 +440,7 @@ func (p *Parser) parseReleaseStatement() (_ *ReleaseStatement, err error) {
 	assert(p.peek() == RELEASE)
 
 	var stmt ReleaseStatement
-	p.scan()
+	start, _, _ := p.scan()
 
 	if p.peek() == SAVEPOINT {
 		p.scan()
@@ -246,34 +449,36 @@ func (p *Parser) parseReleaseStatement() (_ *ReleaseStatement, err error) {
 	if stmt.Name, err = p.parseIdent("savepoint name"); err != nil {
 		return &stmt, err
 	}
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
 func (p *Parser) parseCreateStatement() (Statement, error) {
 	assert(p.peek() == CREATE)
 	pos, tok, _ := p.scan()
+	start := pos
 
 	switch p.peek() {
 	case TABLE:
-		return p.parseCreateTableStatement(false)
+		return p.parseCreateTableStatement(start, false)
 	case VIRTUAL:
-		return p.parseCreateVirtualTableStatement()
+		return p.parseCreateVirtualTableStatement(start)
 	case VIEW:
-		return p.parseCreateViewStatement(false)
+		return p.parseCreateViewStatement(start, false)
 	case INDEX, UNIQUE:
-		return p.parseCreateIndexStatement()
+		return p.parseCreateIndexStatement(start)
 	case TRIGGER:
-		return p.parseCreateTriggerStatement(false)
+		return p.parseCreateTriggerStatement(start, false)
 	case TEMP, TEMPORARY:
 		pos, tok, _ := p.scan()
 
 		switch p.peek() {
 		case TABLE:
-			return p.parseCreateTableStatement(true)
+			return p.parseCreateTableStatement(start, true)
 		case VIEW:
-			return p.parseCreateViewStatement(true)
+			return p.parseCreateViewStatement(start, true)
 		case TRIGGER:
-			return p.parseCreateTriggerStatement(true)
+			return p.parseCreateTriggerStatement(start, true)
 		default:
 			return nil, p.errorExpected(pos, tok, "TABLE, VIEW, or TRIGGER")
 		}
@@ -288,19 +493,19 @@ func (p *Parser) parseDropStatement() (Statement, error) {
 
 	switch p.peek() {
 	case TABLE:
-		return p.parseDropTableStatement()
+		return p.parseDropTableStatement(pos)
 	case VIEW:
-		return p.parseDropViewStatement()
+		return p.parseDropViewStatement(pos)
 	case INDEX:
-		return p.parseDropIndexStatement()
+		return p.parseDropIndexStatement(pos)
 	case TRIGGER:
-		return p.parseDropTriggerStatement()
+		return p.parseDropTriggerStatement(pos)
 	default:
 		return nil, p.errorExpected(pos, tok, "TABLE, VIEW, INDEX, or TRIGGER")
 	}
 }
 
-func (p *Parser) parseCreateTableStatement(temp bool) (_ *CreateTableStatement, err error) {
+func (p *Parser) parseCreateTableStatement(start Pos, temp bool) (_ *CreateTableStatement, err error) {
 	assert(p.peek() == TABLE)
 
 	var stmt CreateTableStatement
@@ -368,12 +573,14 @@ func (p *Parser) parseCreateTableStatement(temp bool) (_ *CreateTableStatement,
 			}
 		}
 
+		stmt.span = p.spanFrom(start)
 		return &stmt, nil
 	case AS:
 		p.scan()
 		if stmt.Select, err = p.parseSelectStatement(false, nil); err != nil {
 			return &stmt, err
 		}
+		stmt.span = p.spanFrom(start)
 		return &stmt, nil
 	default:
 		return &stmt, p.errorExpected(p.pos, p.tok, "AS or left paren")
@@ -402,6 +609,7 @@ func (p *Parser) parseColumnDefinitions() (_ []*ColumnDefinition, err error) {
 
 func (p *Parser) parseColumnDefinition() (_ *ColumnDefinition, err error) {
 	var col ColumnDefinition
+	start := p.peekPos()
 	if col.Name, err = p.parseIdent("column name"); err != nil {
 		return &col, err
 	}
@@ -415,6 +623,7 @@ func (p *Parser) parseColumnDefinition() (_ *ColumnDefinition, err error) {
 	if col.Constraints, err = p.parseColumnConstraints(); err != nil {
 		return &col, err
 	}
+	col.span = p.spanFrom(start)
 	return &col, nil
 }
 
@@ -459,6 +668,7 @@ func (p *Parser) parseConstraint(isTable bool) (_ Constraint, err error) {
 	assert(isConstraintStartToken(p.peek(), isTable))
 
 	var name *Ident
+	start := p.peekPos()
 
 	// Parse constraint name, if specified.
 	if p.peek() == CONSTRAINT {
@@ -473,40 +683,40 @@ func (p *Parser) parseConstraint(isTable bool) (_ Constraint, err error) {
 	if isTable {
 		switch p.peek() {
 		case PRIMARY:
-			return p.parsePrimaryKeyConstraint(name, isTable)
+			return p.parsePrimaryKeyConstraint(start, name, isTable)
 		case UNIQUE:
-			return p.parseUniqueConstraint(name, isTable)
+			return p.parseUniqueConstraint(start, name, isTable)
 		case CHECK:
-			return p.parseCheckConstraint(name)
+			return p.parseCheckConstraint(start, name)
 		default:
 			assert(p.peek() == FOREIGN)
-			return p.parseForeignKeyConstraint(name, isTable)
+			return p.parseForeignKeyConstraint(start, name, isTable)
 		}
 	}
 
 	// Parse column constraints.
 	switch p.peek() {
 	case PRIMARY:
-		return p.parsePrimaryKeyConstraint(name, isTable)
+		return p.parsePrimaryKeyConstraint(start, name, isTable)
 	case NOT:
-		return p.parseNotNullConstraint(name)
+		return p.parseNotNullConstraint(start, name)
 	case UNIQUE:
-		return p.parseUniqueConstraint(name, isTable)
+		return p.parseUniqueConstraint(start, name, isTable)
 	case CHECK:
-		return p.parseCheckConstraint(name)
+		return p.parseCheckConstraint(start, name)
 	case DEFAULT:
-		return p.parseDefaultConstraint(name)
+		return p.parseDefaultConstraint(start, name)
 	case GENERATED, AS:
-		return p.parseGeneratedConstraint(name)
+		return p.parseGeneratedConstraint(start, name)
 	case COLLATE:
-		return p.parseCollateConstraint(name)
+		return p.parseCollateConstraint(start, name)
 	default:
 		assert(p.peek() == REFERENCES)
-		return p.parseForeignKeyConstraint(name, isTable)
+		return p.parseForeignKeyConstraint(start, name, isTable)
 	}
 }
 
-func (p *Parser) parsePrimaryKeyConstraint(name *Ident, isTable bool) (_ *PrimaryKeyConstraint, err error) {
+func (p *Parser) parsePrimaryKeyConstraint(start Pos, name *Ident, isTable bool) (_ *PrimaryKeyConstraint, err error) {
 	assert(p.peek() == PRIMARY)
 
 	var cons PrimaryKeyConstraint
@@ -562,10 +772,11 @@ func (p *Parser) parsePrimaryKeyConstraint(name *Ident, isTable bool) (_ *Primar
 			cons.Autoincrement = p.scanExpectedTok(AUTOINCREMENT)
 		}
 	}
+	cons.span = p.spanFrom(start)
 	return &cons, nil
 }
 
-func (p *Parser) parseNotNullConstraint(name *Ident) (_ *NotNullConstraint, err error) {
+func (p *Parser) parseNotNullConstraint(start Pos, name *Ident) (_ *NotNullConstraint, err error) {
 	assert(p.peek() == NOT)
 
 	var cons NotNullConstraint
@@ -584,10 +795,11 @@ func (p *Parser) parseNotNullConstraint(name *Ident) (_ *NotNullConstraint, err
 		}
 	}
 
+	cons.span = p.spanFrom(start)
 	return &cons, nil
 }
 
-func (p *Parser) parseUniqueConstraint(name *Ident, isTable bool) (_ *UniqueConstraint, err error) {
+func (p *Parser) parseUniqueConstraint(start Pos, name *Ident, isTable bool) (_ *UniqueConstraint, err error) {
 	assert(p.peek() == UNIQUE)
 
 	var cons UniqueConstraint
@@ -624,10 +836,11 @@ func (p *Parser) parseUniqueConstraint(name *Ident, isTable bool) (_ *UniqueCons
 		}
 	}
 
+	cons.span = p.spanFrom(start)
 	return &cons, nil
 }
 
-func (p *Parser) parseCheckConstraint(name *Ident) (_ *CheckConstraint, err error) {
+func (p *Parser) parseCheckConstraint(start Pos, name *Ident) (_ *CheckConstraint, err error) {
 	assert(p.peek() == CHECK)
 
 	var cons CheckConstraint
@@ -648,10 +861,11 @@ func (p *Parser) parseCheckConstraint(name *Ident) (_ *CheckConstraint, err erro
 	}
 	p.scan()
 
+	cons.span = p.spanFrom(start)
 	return &cons, nil
 }
 
-func (p *Parser) parseDefaultConstraint(name *Ident) (_ *DefaultConstraint, err error) {
+func (p *Parser) parseDefaultConstraint(start Pos, name *Ident) (_ *DefaultConstraint, err error) {
 	assert(p.peek() == DEFAULT)
 
 	var cons DefaultConstraint
@@ -664,8 +878,8 @@ func (p *Parser) parseDefaultConstraint(name *Ident) (_ *DefaultConstraint, err
 	//
 	// See: https://github.com/rqlite/sql/issues/18
 	if p.peek() == QIDENT {
-		_, _, lit := p.scan()
-		cons.Expr = &StringLit{Value: lit}
+		pos, _, lit := p.scan()
+		cons.Expr = &StringLit{span: p.spanFrom(pos), Value: lit}
 	} else if isLiteralToken(p.peek()) {
 		cons.Expr = p.mustParseLiteral()
 	} else if p.peek() == PLUS || p.peek() == MINUS {
@@ -687,10 +901,11 @@ func (p *Parser) parseDefaultConstraint(name *Ident) (_ *DefaultConstraint, err
 		}
 		p.scan()
 	}
+	cons.span = p.spanFrom(start)
 	return &cons, nil
 }
 
-func (p *Parser) parseGeneratedConstraint(name *Ident) (_ *GeneratedConstraint, err error) {
+func (p *Parser) parseGeneratedConstraint(start Pos, name *Ident) (_ *GeneratedConstraint, err error) {
 	assert(p.peek() == GENERATED || p.peek() == AS)
 
 	var cons GeneratedConstraint
@@ -731,10 +946,11 @@ func (p *Parser) parseGeneratedConstraint(name *Ident) (_ *GeneratedConstraint,
 		cons.Virtual = p.scanExpectedTok(VIRTUAL)
 	}
 
+	cons.span = p.spanFrom(start)
 	return &cons, nil
 }
 
-func (p *Parser) parseCollateConstraint(name *Ident) (_ *CollateConstraint, err error) {
+func (p *Parser) parseCollateConstraint(start Pos, name *Ident) (_ *CollateConstraint, err error) {
 	assert(p.peek() == COLLATE)
 
 	var cons CollateConstraint
@@ -751,10 +967,11 @@ func (p *Parser) parseCollateConstraint(name *Ident) (_ *CollateConstraint, err
 	}
 	cons.Collation = collation
 
+	cons.span = p.spanFrom(start)
 	return &cons, nil
 }
 
-func (p *Parser) parseForeignKeyConstraint(name *Ident, isTable bool) (_ *ForeignKeyConstraint, err error) {
+func (p *Parser) parseForeignKeyConstraint(start Pos, name *Ident, isTable bool) (_ *ForeignKeyConstraint, err error) {
 	var cons ForeignKeyConstraint
 	cons.Name = name
 
@@ -824,7 +1041,7 @@ func (p *Parser) parseForeignKeyConstraint(name *Ident, isTable bool) (_ *Foreig
 	// Parse foreign key args.
 	for p.peek() == ON {
 		var arg ForeignKeyArg
-		p.scan()
+		argStart, _, _ := p.scan()
 
 		// Parse foreign key type.
 		if p.peek() == UPDATE {
@@ -860,6 +1077,7 @@ func (p *Parser) parseForeignKeyConstraint(name *Ident, isTable bool) (_ *Foreig
 			return &cons, p.errorExpected(p.pos, p.tok, "SET NULL, SET DEFAULT, CASCADE, RESTRICT, or NO ACTION")
 		}
 
+		arg.span = p.spanFrom(argStart)
 		cons.Args = append(cons.Args, &arg)
 	}
 
@@ -885,10 +1103,11 @@ func (p *Parser) parseForeignKeyConstraint(name *Ident, isTable bool) (_ *Foreig
 		}
 	}
 
+	cons.span = p.spanFrom(start)
 	return &cons, nil
 }
 
-func (p *Parser) parseCreateVirtualTableStatement() (_ *CreateVirtualTableStatement, err error) {
+func (p *Parser) parseCreateVirtualTableStatement(start Pos) (_ *CreateVirtualTableStatement, err error) {
 	assert(p.peek() == VIRTUAL)
 
 	var stmt CreateVirtualTableStatement
@@ -926,6 +1145,7 @@ func (p *Parser) parseCreateVirtualTableStatement() (_ *CreateVirtualTableStatem
 	}
 	// Module arguments can be optional
 	if p.peek() != LP {
+		stmt.span = p.spanFrom(start)
 		return &stmt, nil
 	}
 
@@ -933,6 +1153,7 @@ func (p *Parser) parseCreateVirtualTableStatement() (_ *CreateVirtualTableStatem
 		return &stmt, err
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -965,17 +1186,18 @@ func (p *Parser) parseModuleArguments() (_ []*ModuleArgument, err error) {
 
 func (p *Parser) parseModuleArgument() (_ *ModuleArgument, err error) {
 	var arg ModuleArgument
+	start := p.peekPos()
 
 	if tok := p.peek(); isIdentToken(tok) {
 		if arg.Name, err = p.parseIdent("module argument name"); err != nil {
 			return &arg, err
 		}
 	} else if isLiteralToken(tok) { // arg name allow literals
-		_, _, lit := p.scan()
-		arg.Name = &Ident{Name: lit}
+		pos, _, lit := p.scan()
+		arg.Name = &Ident{span: p.spanFrom(pos), Name: lit}
 	} else if keywordOrIdent(p.lit) != IDENT { // arg name allow keywords
-		_, _, lit := p.scan()
-		arg.Name = &Ident{Name: lit}
+		pos, _, lit := p.scan()
+		arg.Name = &Ident{span: p.spanFrom(pos), Name: lit}
 	} else {
 		return &arg, p.errorExpected(p.pos, p.tok, "module argument name")
 	}
@@ -992,10 +1214,11 @@ func (p *Parser) parseModuleArgument() (_ *ModuleArgument, err error) {
 		}
 	}
 
+	arg.span = p.spanFrom(start)
 	return &arg, nil
 }
 
-func (p *Parser) parseDropTableStatement() (_ *DropTableStatement, err error) {
+func (p *Parser) parseDropTableStatement(start Pos) (_ *DropTableStatement, err error) {
 	assert(p.peek() == TABLE)
 
 	var stmt DropTableStatement
@@ -1015,10 +1238,11 @@ func (p *Parser) parseDropTableStatement() (_ *DropTableStatement, err error) {
 		return &stmt, err
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
-func (p *Parser) parseCreateViewStatement(temp bool) (_ *CreateViewStatement, err error) {
+func (p *Parser) parseCreateViewStatement(start Pos, temp bool) (_ *CreateViewStatement, err error) {
 	assert(p.peek() == VIEW)
 
 	var stmt CreateViewStatement
@@ -1073,10 +1297,11 @@ func (p *Parser) parseCreateViewStatement(temp bool) (_ *CreateViewStatement, er
 	if stmt.Select, err = p.parseSelectStatement(false, nil); err != nil {
 		return &stmt, err
 	}
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
-func (p *Parser) parseDropViewStatement() (_ *DropViewStatement, err error) {
+func (p *Parser) parseDropViewStatement(start Pos) (_ *DropViewStatement, err error) {
 	assert(p.peek() == VIEW)
 
 	var stmt DropViewStatement
@@ -1096,10 +1321,11 @@ func (p *Parser) parseDropViewStatement() (_ *DropViewStatement, err error) {
 		return &stmt, err
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
-func (p *Parser) parseCreateIndexStatement() (_ *CreateIndexStatement, err error) {
+func (p *Parser) parseCreateIndexStatement(start Pos) (_ *CreateIndexStatement, err error) {
 	assert(p.peek() == INDEX || p.peek() == UNIQUE)
 
 	var stmt CreateIndexStatement
@@ -1171,10 +1397,11 @@ func (p *Parser) parseCreateIndexStatement() (_ *CreateIndexStatement, err error
 			return &stmt, err
 		}
 	}
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
-func (p *Parser) parseDropIndexStatement() (_ *DropIndexStatement, err error) {
+func (p *Parser) parseDropIndexStatement(start Pos) (_ *DropIndexStatement, err error) {
 	assert(p.peek() == INDEX)
 
 	var stmt DropIndexStatement
@@ -1194,10 +1421,11 @@ func (p *Parser) parseDropIndexStatement() (_ *DropIndexStatement, err error) {
 		return &stmt, err
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
-func (p *Parser) parseCreateTriggerStatement(temp bool) (_ *CreateTriggerStatement, err error) {
+func (p *Parser) parseCreateTriggerStatement(start Pos, temp bool) (_ *CreateTriggerStatement, err error) {
 	assert(p.peek() == TRIGGER)
 
 	var stmt CreateTriggerStatement
@@ -1314,6 +1542,7 @@ func (p *Parser) parseCreateTriggerStatement(temp bool) (_ *CreateTriggerStateme
 	}
 	p.scan()
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -1345,7 +1574,7 @@ func (p *Parser) parseTriggerBodyStatement() (stmt Statement, err error) {
 	return stmt, nil
 }
 
-func (p *Parser) parseDropTriggerStatement() (_ *DropTriggerStatement, err error) {
+func (p *Parser) parseDropTriggerStatement(start Pos) (_ *DropTriggerStatement, err error) {
 	assert(p.peek() == TRIGGER)
 
 	var stmt DropTriggerStatement
@@ -1365,6 +1594,7 @@ func (p *Parser) parseDropTriggerStatement() (_ *DropTriggerStatement, err error
 		return &stmt, err
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -1372,14 +1602,14 @@ func (p *Parser) parseIdent(desc string) (*Ident, error) {
 	pos, tok, lit := p.scan()
 	switch tok {
 	case IDENT, QIDENT:
-		return &Ident{Name: lit, Quoted: tok == QIDENT}, nil
+		return &Ident{span: p.spanFrom(pos), Name: lit, Quoted: tok == QIDENT, Quote: p.quote(pos, tok)}, nil
 	case NULL:
-		return &Ident{Name: lit}, nil
+		return &Ident{span: p.spanFrom(pos), Name: lit}, nil
 	case STRING:
-		return &Ident{Name: lit, Quoted: true}, nil
+		return &Ident{span: p.spanFrom(pos), Name: lit, Quoted: true, Quote: p.quote(pos, tok)}, nil
 	default:
 		if isBareToken(tok) {
-			return &Ident{Name: lit}, nil
+			return &Ident{span: p.spanFrom(pos), Name: lit}, nil
 		}
 		return nil, p.errorExpected(pos, tok, desc)
 	}
@@ -1400,6 +1630,7 @@ func (p *Parser) parseType() (_ *Type, err error) {
 			typ.Name = typeName
 		} else {
 			typ.Name.Name += " " + typeName.Name
+			typ.Name.end = typeName.end
 		}
 	}
 
@@ -1427,6 +1658,7 @@ func (p *Parser) parseType() (_ *Type, err error) {
 		p.scan()
 	}
 
+	typ.span = p.spanFrom(typ.Name.pos)
 	return &typ, nil
 }
 
@@ -1435,6 +1667,10 @@ func (p *Parser) parseInsertStatement(inTrigger bool, withClause *WithClause) (_
 
 	var stmt InsertStatement
 	stmt.WithClause = withClause
+	start := p.peekPos()
+	if withClause != nil {
+		start = withClause.pos
+	}
 
 	if p.peek() == INSERT {
 		p.scan()
@@ -1499,7 +1735,7 @@ func (p *Parser) parseInsertStatement(inTrigger bool, withClause *WithClause) (_
 			if p.peek() != LP {
 				return &stmt, p.errorExpected(p.pos, p.tok, "left paren")
 			}
-			p.scan()
+			listStart, _, _ := p.scan()
 
 			for {
 				expr, err := p.ParseExpr()
@@ -1516,6 +1752,7 @@ func (p *Parser) parseInsertStatement(inTrigger bool, withClause *WithClause) (_
 				p.scan()
 			}
 			p.scan()
+			list.span = p.spanFrom(listStart)
 			stmt.ValueLists = append(stmt.ValueLists, &list)
 
 			if p.peek() != COMMA {
@@ -1555,6 +1792,7 @@ func (p *Parser) parseInsertStatement(inTrigger bool, withClause *WithClause) (_
 		}
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -1564,7 +1802,7 @@ func (p *Parser) parseUpsertClause() (_ *UpsertClause, err error) {
 	var clause UpsertClause
 
 	// Parse "ON CONFLICT"
-	p.scan()
+	start, _, _ := p.scan()
 	if p.peek() != CONFLICT {
 		return &clause, p.errorExpected(p.pos, p.tok, "CONFLICT")
 	}
@@ -1606,6 +1844,7 @@ func (p *Parser) parseUpsertClause() (_ *UpsertClause, err error) {
 	// If next token is NOTHING, then read it and exit immediately.
 	if p.peek() == NOTHING {
 		clause.DoNothing = p.scanExpectedTok(NOTHING)
+		clause.span = p.spanFrom(start)
 		return &clause, nil
 	} else if p.peek() != UPDATE {
 		return &clause, p.errorExpected(p.pos, p.tok, "NOTHING or UPDATE SET")
@@ -1640,6 +1879,7 @@ func (p *Parser) parseUpsertClause() (_ *UpsertClause, err error) {
 		}
 	}
 
+	clause.span = p.spanFrom(start)
 	return &clause, nil
 }
 
@@ -1683,6 +1923,7 @@ func (p *Parser) parseIndexedColumn() (_ *IndexedColumn, err error) {
 		col.Desc = p.scanExpectedTok(DESC)
 	}
 
+	col.span = p.spanFrom(col.X.Pos())
 	return &col, nil
 }
 
@@ -1692,7 +1933,10 @@ func (p *Parser) parseUpdateStatement(inTrigger bool, withClause *WithClause) (_
 	var stmt UpdateStatement
 	stmt.WithClause = withClause
 
-	p.scan()
+	start, _, _ := p.scan()
+	if withClause != nil {
+		start = withClause.pos
+	}
 	if p.peek() == OR {
 		p.scan()
 
@@ -1735,6 +1979,18 @@ func (p *Parser) parseUpdateStatement(inTrigger bool, withClause *WithClause) (_
 		p.scan()
 	}
 
+	// Parse optional FROM clause. It is not supported within triggers.
+	if p.peek() == FROM {
+		if inTrigger {
+			return &stmt, &Error{Pos: p.pos, Msg: "FROM clause not allowed in UPDATE within a trigger"}
+		}
+
+		p.scan()
+		if stmt.Source, err = p.parseSource(); err != nil {
+			return &stmt, err
+		}
+	}
+
 	// Parse WHERE clause.
 	if p.peek() == WHERE {
 		p.scan()
@@ -1787,18 +2043,23 @@ func (p *Parser) parseUpdateStatement(inTrigger bool, withClause *WithClause) (_
 			return &stmt, err
 		}
 
-		if tok := p.peek(); tok == OFFSET || tok == COMMA {
-			if tok == OFFSET {
-				p.scan()
-			} else {
-				p.scan()
-			}
+		switch p.peek() {
+		case OFFSET:
+			p.scan()
 			if stmt.OffsetExpr, err = p.ParseExpr(); err != nil {
 				return &stmt, err
 			}
+		case COMMA:
+			// "LIMIT x, y" is "LIMIT y OFFSET x".
+			p.scan()
+			stmt.OffsetExpr, stmt.LimitComma = stmt.LimitExpr, true
+			if stmt.LimitExpr, err = p.ParseExpr(); err != nil {
+				return &stmt, err
+			}
 		}
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -1809,7 +2070,10 @@ func (p *Parser) parseDeleteStatement(inTrigger bool, withClause *WithClause) (_
 	stmt.WithClause = withClause
 
 	// Parse "DELETE FROM tbl"
-	p.scan()
+	start, _, _ := p.scan()
+	if withClause != nil {
+		start = withClause.pos
+	}
 	if p.peek() != FROM {
 		return &stmt, p.errorExpected(p.pos, p.tok, "FROM")
 	}
@@ -1871,23 +2135,29 @@ func (p *Parser) parseDeleteStatement(inTrigger bool, withClause *WithClause) (_
 			return &stmt, err
 		}
 
-		if tok := p.peek(); tok == OFFSET || tok == COMMA {
-			if tok == OFFSET {
-				p.scan()
-			} else {
-				p.scan()
-			}
+		switch p.peek() {
+		case OFFSET:
+			p.scan()
 			if stmt.OffsetExpr, err = p.ParseExpr(); err != nil {
 				return &stmt, err
 			}
+		case COMMA:
+			// "LIMIT x, y" is "LIMIT y OFFSET x".
+			p.scan()
+			stmt.OffsetExpr, stmt.LimitComma = stmt.LimitExpr, true
+			if stmt.LimitExpr, err = p.ParseExpr(); err != nil {
+				return &stmt, err
+			}
 		}
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
 func (p *Parser) parseAssignment() (_ *Assignment, err error) {
 	var assignment Assignment
+	start := p.peekPos()
 
 	// Parse either a single column (IDENT) or a column list (LP IDENT COMMA IDENT RP)
 	if isIdentToken(p.peek()) {
@@ -1923,6 +2193,7 @@ func (p *Parser) parseAssignment() (_ *Assignment, err error) {
 		return &assignment, err
 	}
 
+	assignment.span = p.spanFrom(start)
 	return &assignment, nil
 }
 
@@ -1930,7 +2201,16 @@ func (p *Parser) parseAssignment() (_ *Assignment, err error) {
 // If compounded is true, WITH, ORDER BY, & LIMIT/OFFSET are skipped.
 func (p *Parser) parseSelectStatement(compounded bool, withClause *WithClause) (_ *SelectStatement, err error) {
 	var stmt SelectStatement
+	if err := p.enter(); err != nil {
+		return &stmt, err
+	}
+	defer p.leave()
+
 	stmt.WithClause = withClause
+	start := p.peekPos()
+	if withClause != nil {
+		start = withClause.pos
+	}
 
 	// Parse optional "WITH [RECURSIVE} cte, cte..."
 	// This is only called here if this method is called directly. Generic
@@ -1950,7 +2230,7 @@ func (p *Parser) parseSelectStatement(compounded bool, withClause *WithClause) (
 			if p.peek() != LP {
 				return &stmt, p.errorExpected(p.pos, p.tok, "left paren")
 			}
-			p.scan()
+			listStart, _, _ := p.scan()
 
 			for {
 				expr, err := p.ParseExpr()
@@ -1967,6 +2247,7 @@ func (p *Parser) parseSelectStatement(compounded bool, withClause *WithClause) (
 				p.scan()
 			}
 			p.scan()
+			list.span = p.spanFrom(listStart)
 			stmt.ValueLists = append(stmt.ValueLists, &list)
 
 			if p.peek() != COMMA {
@@ -2052,6 +2333,7 @@ func (p *Parser) parseSelectStatement(compounded bool, withClause *WithClause) (
 
 			for {
 				var window Window
+				windowStart := p.peekPos()
 				if window.Name, err = p.parseIdent("window name"); err != nil {
 					return &stmt, err
 				}
@@ -2064,6 +2346,7 @@ func (p *Parser) parseSelectStatement(compounded bool, withClause *WithClause) (
 				if window.Definition, err = p.parseWindowDefinition(); err != nil {
 					return &stmt, err
 				}
+				window.span = p.spanFrom(windowStart)
 
 				stmt.Windows = append(stmt.Windows, &window)
 
@@ -2127,27 +2410,34 @@ func (p *Parser) parseSelectStatement(compounded bool, withClause *WithClause) (
 			return &stmt, err
 		}
 
-		if tok := p.peek(); tok == OFFSET || tok == COMMA {
-			if tok == OFFSET {
-				p.scan()
-			} else {
-				p.scan()
-			}
+		switch p.peek() {
+		case OFFSET:
+			p.scan()
 			if stmt.OffsetExpr, err = p.ParseExpr(); err != nil {
 				return &stmt, err
 			}
+		case COMMA:
+			// "LIMIT x, y" is "LIMIT y OFFSET x".
+			p.scan()
+			stmt.OffsetExpr, stmt.LimitComma = stmt.LimitExpr, true
+			if stmt.LimitExpr, err = p.ParseExpr(); err != nil {
+				return &stmt, err
+			}
 		}
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
 func (p *Parser) parseResultColumn() (_ *ResultColumn, err error) {
 	var col ResultColumn
+	start := p.peekPos()
 
 	// An initial "*" returns all columns.
 	if p.peek() == STAR {
 		col.Star = p.scanExpectedTok(STAR)
+		col.span = p.spanFrom(start)
 		return &col, nil
 	}
 
@@ -2159,6 +2449,7 @@ func (p *Parser) parseResultColumn() (_ *ResultColumn, err error) {
 
 	// If we have a qualified ref w/ a star, don't allow an alias.
 	if ref, ok := col.Expr.(*QualifiedRef); ok && ref.Star {
+		col.span = p.spanFrom(start)
 		return &col, nil
 	}
 
@@ -2174,6 +2465,7 @@ func (p *Parser) parseResultColumn() (_ *ResultColumn, err error) {
 		col.Alias, _ = p.parseIdent("column alias")
 	}
 
+	col.span = p.spanFrom(start)
 	return &col, nil
 }
 
@@ -2208,9 +2500,11 @@ func (p *Parser) parseSource() (source Source, err error) {
 		// Rewrite last source to nest next join on right side.
 		if lhs, ok := source.(*JoinClause); ok {
 			source = &JoinClause{
+				span:     p.spanFrom(lhs.pos),
 				X:        lhs.X,
 				Operator: lhs.Operator,
 				Y: &JoinClause{
+					span:       p.spanFrom(lhs.Y.Pos()),
 					X:          lhs.Y,
 					Operator:   operator,
 					Y:          y,
@@ -2219,7 +2513,7 @@ func (p *Parser) parseSource() (source Source, err error) {
 				Constraint: lhs.Constraint,
 			}
 		} else {
-			source = &JoinClause{X: source, Operator: operator, Y: y, Constraint: constraint}
+			source = &JoinClause{span: p.spanFrom(source.Pos()), X: source, Operator: operator, Y: y, Constraint: constraint}
 		}
 	}
 }
@@ -2238,10 +2532,12 @@ func (p *Parser) parseUnarySource() (source Source, err error) {
 
 func (p *Parser) parseJoinOperator() (*JoinOperator, error) {
 	var op JoinOperator
+	start := p.peekPos()
 
 	// Handle single comma join.
 	if p.peek() == COMMA {
 		p.scan()
+		op.span = p.spanFrom(start)
 		return &op, nil
 	}
 
@@ -2277,6 +2573,7 @@ func (p *Parser) parseJoinOperator() (*JoinOperator, error) {
 	}
 	p.scan()
 
+	op.span = p.spanFrom(start)
 	return &op, nil
 }
 
@@ -2295,10 +2592,11 @@ func (p *Parser) parseOnConstraint() (_ *OnConstraint, err error) {
 	assert(p.peek() == ON)
 
 	var con OnConstraint
-	p.scan()
+	start, _, _ := p.scan()
 	if con.X, err = p.ParseExpr(); err != nil {
 		return &con, err
 	}
+	con.span = p.spanFrom(start)
 	return &con, nil
 }
 
@@ -2306,7 +2604,7 @@ func (p *Parser) parseUsingConstraint() (*UsingConstraint, error) {
 	assert(p.peek() == USING)
 
 	var con UsingConstraint
-	p.scan()
+	start, _, _ := p.scan()
 
 	if p.peek() != LP {
 		return &con, p.errorExpected(p.pos, p.tok, "left paren")
@@ -2329,6 +2627,7 @@ func (p *Parser) parseUsingConstraint() (*UsingConstraint, error) {
 	}
 	p.scan()
 
+	con.span = p.spanFrom(start)
 	return &con, nil
 }
 
@@ -2336,14 +2635,20 @@ func (p *Parser) parseParenSource() (_ *ParenSource, err error) {
 	assert(p.peek() == LP)
 
 	var source ParenSource
-	p.scan()
+	start, _, _ := p.scan()
 
 	if p.peek() == SELECT {
 		if source.X, err = p.parseSelectStatement(false, nil); err != nil {
 			return &source, err
 		}
 	} else {
-		if source.X, err = p.parseSource(); err != nil {
+		// Subqueries count towards the depth on their own.
+		if err := p.enter(); err != nil {
+			return &source, err
+		}
+		source.X, err = p.parseSource()
+		p.leave()
+		if err != nil {
 			return &source, err
 		}
 	}
@@ -2362,6 +2667,7 @@ func (p *Parser) parseParenSource() (_ *ParenSource, err error) {
 		}
 	}
 
+	source.span = p.spanFrom(start)
 	return &source, nil
 }
 
@@ -2435,6 +2741,7 @@ func (p *Parser) parseQualifiedNameFromIdent(ident *Ident, schemaOK, aliasOK, in
 	switch p.peek() {
 	case INDEXED:
 		if !indexedOK {
+			tbl.span = p.spanFrom(ident.pos)
 			return &tbl, nil
 		}
 
@@ -2449,6 +2756,7 @@ func (p *Parser) parseQualifiedNameFromIdent(ident *Ident, schemaOK, aliasOK, in
 		}
 	case NOT:
 		if !indexedOK {
+			tbl.span = p.spanFrom(ident.pos)
 			return &tbl, nil
 		}
 
@@ -2459,6 +2767,7 @@ func (p *Parser) parseQualifiedNameFromIdent(ident *Ident, schemaOK, aliasOK, in
 		tbl.NotIndexed = p.scanExpectedTok(INDEXED)
 	}
 
+	tbl.span = p.spanFrom(ident.pos)
 	return &tbl, nil
 }
 
@@ -2466,7 +2775,7 @@ func (p *Parser) parseWithClause() (*WithClause, error) {
 	assert(p.peek() == WITH)
 
 	var clause WithClause
-	p.scan()
+	start, _, _ := p.scan()
 	if p.peek() == RECURSIVE {
 		clause.Recursive = p.scanExpectedTok(RECURSIVE)
 	}
@@ -2484,6 +2793,7 @@ func (p *Parser) parseWithClause() (*WithClause, error) {
 		}
 		p.scan()
 	}
+	clause.span = p.spanFrom(start)
 	return &clause, nil
 }
 
@@ -2519,6 +2829,18 @@ func (p *Parser) parseCTE() (_ *CTE, err error) {
 	}
 	p.scan()
 
+	// Parse optional "MATERIALIZED" or "NOT MATERIALIZED" hint.
+	switch p.peek() {
+	case MATERIALIZED:
+		cte.Materialized = p.scanExpectedTok(MATERIALIZED)
+	case NOT:
+		p.scan()
+		if p.peek() != MATERIALIZED {
+			return nil, p.errorExpected(p.pos, p.tok, "MATERIALIZED")
+		}
+		cte.NotMaterialized = p.scanExpectedTok(MATERIALIZED)
+	}
+
 	// Parse select statement.
 	if p.peek() != LP {
 		return nil, p.errorExpected(p.pos, p.tok, "left paren")
@@ -2534,26 +2856,28 @@ func (p *Parser) parseCTE() (_ *CTE, err error) {
 	}
 	p.scan()
 
+	cte.span = p.spanFrom(cte.TableName.pos)
 	return &cte, nil
 }
 
 func (p *Parser) mustParseLiteral() Expr {
 	assert(isLiteralToken(p.tok))
-	_, tok, lit := p.scan()
+	pos, tok, lit := p.scan()
+	sp := p.spanFrom(pos)
 	switch tok {
 	case STRING:
-		return &StringLit{Value: lit}
+		return &StringLit{span: sp, Value: lit}
 	case CURRENT_TIME, CURRENT_DATE, CURRENT_TIMESTAMP:
-		return &TimestampLit{Value: lit}
+		return &TimestampLit{span: sp, Value: lit}
 	case BLOB:
-		return &BlobLit{Value: lit}
+		return &BlobLit{span: sp, Value: lit}
 	case FLOAT, INTEGER:
-		return &NumberLit{Value: lit}
+		return &NumberLit{span: sp, Value: lit}
 	case TRUE, FALSE:
-		return &BoolLit{Value: tok == TRUE}
+		return &BoolLit{span: sp, Value: tok == TRUE}
 	default:
 		assert(tok == NULL)
-		return &NullLit{}
+		return &NullLit{span: sp}
 	}
 }
 
@@ -2562,7 +2886,12 @@ func (p *Parser) ParseExpr() (expr Expr, err error) {
 }
 
 func (p *Parser) parseOperand() (expr Expr, err error) {
-	_, tok, lit := p.scan()
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	pos, tok, lit := p.scan()
 	switch {
 	case tok == CAST:
 		p.unscan()
@@ -2575,7 +2904,7 @@ func (p *Parser) parseOperand() (expr Expr, err error) {
 		return p.parseRaise()
 	case tok == NOT:
 		if p.peek() == EXISTS {
-			return p.parseExists(true)
+			return p.parseExists(pos, true)
 		}
 
 		expr, err = p.parseOperand()
@@ -2583,22 +2912,22 @@ func (p *Parser) parseOperand() (expr Expr, err error) {
 			return nil, err
 		}
 
-		return &UnaryExpr{Op: OP_NOT, X: expr}, nil
+		return &UnaryExpr{span: p.spanFrom(pos), Op: OP_NOT, X: expr}, nil
 	case tok == EXISTS:
 		p.unscan()
-		return p.parseExists(false)
+		return p.parseExists(pos, false)
 	case tok == SELECT || tok == WITH:
 		p.unscan()
 		sel, err := p.parseSelectStatement(false, nil)
 		return sel, err
 	case tok == STRING:
 		if p.peek() != DOT && p.peek() != LP {
-			return &StringLit{Value: lit}, nil
+			return &StringLit{span: p.spanFrom(pos), Value: lit}, nil
 		}
 
 		fallthrough
 	case isExprIdentToken(tok):
-		ident := &Ident{Name: lit, Quoted: tok == QIDENT || tok == STRING}
+		ident := &Ident{span: p.spanFrom(pos), Name: lit, Quoted: tok == QIDENT || tok == STRING, Quote: p.quote(pos, tok)}
 		switch p.peek() {
 		case DOT:
 			qr, err := p.parseQualifiedRef(ident)
@@ -2613,15 +2942,15 @@ func (p *Parser) parseOperand() (expr Expr, err error) {
 
 		return ident, nil
 	case tok == BLOB:
-		return &BlobLit{Value: lit}, nil
+		return &BlobLit{span: p.spanFrom(pos), Value: lit}, nil
 	case tok == FLOAT, tok == INTEGER:
-		return &NumberLit{Value: lit}, nil
+		return &NumberLit{span: p.spanFrom(pos), Value: lit}, nil
 	case tok == NULL:
-		return &NullLit{}, nil
+		return &NullLit{span: p.spanFrom(pos)}, nil
 	case tok == TRUE, tok == FALSE:
-		return &BoolLit{Value: tok == TRUE}, nil
+		return &BoolLit{span: p.spanFrom(pos), Value: tok == TRUE}, nil
 	case tok == BIND:
-		return &BindExpr{Name: lit}, nil
+		return &BindExpr{span: p.spanFrom(pos), Name: lit}, nil
 	case tok == PLUS, tok == MINUS, tok == BITNOT:
 		expr, err = p.parseOperand()
 		if err != nil {
@@ -2630,11 +2959,11 @@ func (p *Parser) parseOperand() (expr Expr, err error) {
 
 		switch tok {
 		case PLUS:
-			return &UnaryExpr{Op: OP_MINUS, X: expr}, nil
+			return &UnaryExpr{span: p.spanFrom(pos), Op: OP_PLUS, X: expr}, nil
 		case MINUS:
-			return &UnaryExpr{Op: OP_MINUS, X: expr}, nil
+			return &UnaryExpr{span: p.spanFrom(pos), Op: OP_MINUS, X: expr}, nil
 		case BITNOT:
-			return &UnaryExpr{Op: OP_BITNOT, X: expr}, nil
+			return &UnaryExpr{span: p.spanFrom(pos), Op: OP_BITNOT, X: expr}, nil
 		}
 
 		panic("unreachable")
@@ -2664,7 +2993,7 @@ func (p *Parser) parseBinaryExpr(prec1 int) (expr Expr, err error) {
 
 		switch op {
 		case OP_NOTNULL, OP_ISNULL:
-			x = &Null{X: x, Op: op}
+			x = &Null{span: p.spanFrom(x.Pos()), X: x, Op: op}
 		case OP_IN, OP_NOT_IN:
 			var y InExpr
 			y.X = x
@@ -2672,7 +3001,7 @@ func (p *Parser) parseBinaryExpr(prec1 int) (expr Expr, err error) {
 
 			switch p.peek() {
 			case LP:
-				p.scan()
+				listStart, _, _ := p.scan()
 
 				switch p.peek() {
 				case SELECT, WITH:
@@ -2681,7 +3010,7 @@ func (p *Parser) parseBinaryExpr(prec1 int) (expr Expr, err error) {
 						return x, err
 					}
 				default:
-					y.Values = &ExprList{}
+					y.Values = &ExprList{span: span{pos: listStart}}
 					for p.peek() != RP {
 						x, err := p.ParseExpr()
 						if err != nil {
@@ -2702,6 +3031,9 @@ func (p *Parser) parseBinaryExpr(prec1 int) (expr Expr, err error) {
 					return x, p.errorExpected(p.pos, p.tok, "right paren")
 				}
 				p.scan()
+				if y.Values != nil {
+					y.Values.end = p.lastEnd()
+				}
 			default:
 				y.TableOrFunction, err = p.parseQualifiedName(true, false, false, true, false)
 				if err != nil {
@@ -2709,6 +3041,7 @@ func (p *Parser) parseBinaryExpr(prec1 int) (expr Expr, err error) {
 				}
 			}
 
+			y.span = p.spanFrom(x.Pos())
 			x = &y
 		case OP_BETWEEN, OP_NOT_BETWEEN:
 			lhs, err := p.parseBinaryExpr(op.Precedence() + 1)
@@ -2727,9 +3060,10 @@ func (p *Parser) parseBinaryExpr(prec1 int) (expr Expr, err error) {
 			}
 
 			x = &BinaryExpr{
-				X:  x,
-				Op: op,
-				Y:  &BinaryExpr{X: lhs, Op: OP_AND, Y: rhs},
+				span: p.spanFrom(x.Pos()),
+				X:    x,
+				Op:   op,
+				Y:    &BinaryExpr{span: p.spanFrom(lhs.Pos()), X: lhs, Op: OP_AND, Y: rhs},
 			}
 		case OP_LIKE, OP_NOT_LIKE:
 			y, err := p.parseBinaryExpr(OP_ESCAPE.Precedence() + 1) // make sure we not consume the ESCAPE token
@@ -2750,12 +3084,13 @@ func (p *Parser) parseBinaryExpr(prec1 int) (expr Expr, err error) {
 				}
 
 				x = &BinaryExpr{
-					X:  &BinaryExpr{X: x, Op: op, Y: y},
-					Op: OP_ESCAPE,
-					Y:  escapeExpr,
+					span: p.spanFrom(x.Pos()),
+					X:    &BinaryExpr{span: span{pos: x.Pos(), end: y.End()}, X: x, Op: op, Y: y},
+					Op:   OP_ESCAPE,
+					Y:    escapeExpr,
 				}
 			} else {
-				x = &BinaryExpr{X: x, Op: op, Y: y}
+				x = &BinaryExpr{span: p.spanFrom(x.Pos()), X: x, Op: op, Y: y}
 			}
 		case OP_ESCAPE:
 			return x, p.errorExpected(p.pos, p.tok, "op ESCAPE can not be used without LIKE")
@@ -2766,11 +3101,12 @@ func (p *Parser) parseBinaryExpr(prec1 int) (expr Expr, err error) {
 
 			fallthrough
 		default:
+			altOp := (op == OP_EQ && p.lit == "==") || (op == OP_NE && p.lit == "<>")
 			y, err := p.parseBinaryExpr(op.Precedence() + 1)
 			if err != nil {
 				return nil, err
 			}
-			x = &BinaryExpr{X: x, Op: op, Y: y}
+			x = &BinaryExpr{span: p.spanFrom(x.Pos()), X: x, Op: op, AltOp: altOp, Y: y}
 		}
 	}
 }
@@ -2779,7 +3115,7 @@ func (p *Parser) parseQualifiedRef(table *Ident) (_ *QualifiedRef, err error) {
 	assert(p.peek() == DOT)
 
 	var expr QualifiedRef
-	expr.Table = &QualifiedName{Name: table}
+	expr.Table = &QualifiedName{span: table.span, Name: table}
 	p.scan()
 
 	if p.peek() == STAR {
@@ -2800,11 +3136,13 @@ func (p *Parser) parseQualifiedRef(table *Ident) (_ *QualifiedRef, err error) {
 		p.scan()
 		expr.Table.Schema = expr.Table.Name
 		expr.Table.Name = expr.Column
+		expr.Table.end = expr.Column.end
 		if expr.Column, err = p.parseIdent("column name"); err != nil {
 			return &expr, err
 		}
 	}
 
+	expr.span = p.spanFrom(table.pos)
 	return &expr, nil
 }
 
@@ -2859,6 +3197,7 @@ func (p *Parser) parseCall(name *Ident) (_ *Call, err error) {
 		}
 	}
 
+	expr.span = p.spanFrom(name.pos)
 	return &expr, nil
 }
 
@@ -2869,12 +3208,12 @@ func (p *Parser) parseWindowDefinition() (_ *WindowDefinition, err error) {
 	if p.peek() != LP {
 		return &def, p.errorExpected(p.pos, p.tok, "left paren")
 	}
-	p.scan()
+	start, _, _ := p.scan()
 
 	// Read base window name.
 	if tok := p.peek(); isIdentToken(tok) && tok != PARTITION && tok != ORDER && tok != RANGE && tok != ROWS && tok != GROUPS {
-		_, tok, lit := p.scan()
-		def.Base = &Ident{Name: lit, Quoted: tok == QIDENT}
+		pos, tok, lit := p.scan()
+		def.Base = &Ident{span: p.spanFrom(pos), Name: lit, Quoted: tok == QIDENT, Quote: p.quote(pos, tok)}
 	}
 
 	// Parse "PARTITION BY expr, expr..."
@@ -2934,6 +3273,7 @@ func (p *Parser) parseWindowDefinition() (_ *WindowDefinition, err error) {
 	}
 	p.scan()
 
+	def.span = p.spanFrom(start)
 	return &def, nil
 }
 
@@ -2964,6 +3304,7 @@ func (p *Parser) parseOrderingTerm() (_ *OrderingTerm, err error) {
 		}
 	}
 
+	term.span = p.spanFrom(term.X.Pos())
 	return &term, nil
 }
 
@@ -2971,6 +3312,7 @@ func (p *Parser) parseFrameSpec() (_ *FrameSpec, err error) {
 	assert(p.peek() == RANGE || p.peek() == ROWS || p.peek() == GROUPS)
 
 	var spec FrameSpec
+	start := p.peekPos()
 
 	switch p.peek() {
 	case RANGE:
@@ -3075,11 +3417,12 @@ func (p *Parser) parseFrameSpec() (_ *FrameSpec, err error) {
 		}
 	}
 
+	spec.span = p.spanFrom(start)
 	return &spec, nil
 }
 
 func (p *Parser) parseParenExpr() (Expr, error) {
-	p.scan()
+	start, _, _ := p.scan()
 
 	// Parse the first expression
 	x, err := p.ParseExpr()
@@ -3090,7 +3433,7 @@ func (p *Parser) parseParenExpr() (Expr, error) {
 	// If there's no comma after the first expression, treat it as a normal parenthesized expression
 	if p.peek() != COMMA {
 		p.scan()
-		return &ParenExpr{Expr: x}, nil
+		return &ParenExpr{span: p.spanFrom(start), Expr: x}, nil
 	}
 
 	// If there's a comma, we're dealing with an expression list
@@ -3112,6 +3455,7 @@ func (p *Parser) parseParenExpr() (Expr, error) {
 	}
 	p.scan()
 
+	list.span = p.spanFrom(start)
 	return &list, nil
 }
 
@@ -3119,7 +3463,7 @@ func (p *Parser) parseCastExpr() (_ *CastExpr, err error) {
 	assert(p.peek() == CAST)
 
 	var expr CastExpr
-	p.scan()
+	start, _, _ := p.scan()
 
 	if p.peek() != LP {
 		return &expr, p.errorExpected(p.pos, p.tok, "left paren")
@@ -3143,6 +3487,7 @@ func (p *Parser) parseCastExpr() (_ *CastExpr, err error) {
 		return &expr, p.errorExpected(p.pos, p.tok, "right paren")
 	}
 	p.scan()
+	expr.span = p.spanFrom(start)
 	return &expr, nil
 }
 
@@ -3150,7 +3495,7 @@ func (p *Parser) parseCaseExpr() (_ *CaseExpr, err error) {
 	assert(p.peek() == CASE)
 
 	var expr CaseExpr
-	p.scan()
+	start, _, _ := p.scan()
 
 	// Parse optional expression if WHEN is not next.
 	if p.peek() != WHEN {
@@ -3165,7 +3510,7 @@ func (p *Parser) parseCaseExpr() (_ *CaseExpr, err error) {
 		if p.peek() != WHEN {
 			return &expr, p.errorExpected(p.pos, p.tok, "WHEN")
 		}
-		p.scan()
+		blkStart, _, _ := p.scan()
 
 		if blk.Condition, err = p.ParseExpr(); err != nil {
 			return &expr, err
@@ -3180,6 +3525,7 @@ func (p *Parser) parseCaseExpr() (_ *CaseExpr, err error) {
 			return &expr, err
 		}
 
+		blk.span = p.spanFrom(blkStart)
 		expr.Blocks = append(expr.Blocks, &blk)
 
 		if tok := p.peek(); tok == ELSE || tok == END {
@@ -3202,10 +3548,11 @@ func (p *Parser) parseCaseExpr() (_ *CaseExpr, err error) {
 	}
 	p.scan()
 
+	expr.span = p.spanFrom(start)
 	return &expr, nil
 }
 
-func (p *Parser) parseExists(not bool) (_ *Exists, err error) {
+func (p *Parser) parseExists(start Pos, not bool) (_ *Exists, err error) {
 	assert(p.peek() == EXISTS)
 
 	var expr Exists
@@ -3230,6 +3577,7 @@ func (p *Parser) parseExists(not bool) (_ *Exists, err error) {
 	}
 	p.scan()
 
+	expr.span = p.spanFrom(start)
 	return &expr, nil
 }
 
@@ -3237,7 +3585,7 @@ func (p *Parser) parseRaise() (_ *Raise, err error) {
 	assert(p.peek() == RAISE)
 
 	var expr Raise
-	p.scan()
+	start, _, _ := p.scan()
 
 	if p.peek() != LP {
 		return &expr, p.errorExpected(p.pos, p.tok, "left paren")
@@ -3268,8 +3616,8 @@ func (p *Parser) parseRaise() (_ *Raise, err error) {
 		if p.peek() != STRING {
 			return &expr, p.errorExpected(p.pos, p.tok, "error message")
 		}
-		_, _, lit := p.scan()
-		expr.Error = &StringLit{Value: lit}
+		pos, _, lit := p.scan()
+		expr.Error = &StringLit{span: p.spanFrom(pos), Value: lit}
 	}
 
 	if p.peek() != RP {
@@ -3277,11 +3625,12 @@ func (p *Parser) parseRaise() (_ *Raise, err error) {
 	}
 	p.scan()
 
+	expr.span = p.spanFrom(start)
 	return &expr, nil
 }
 
 func (p *Parser) parseSignedNumber(desc string) (*NumberLit, error) {
-	_, tok, lit := p.scan()
+	pos, tok, lit := p.scan()
 
 	// Prepend "+" or "-" to the next number value.
 	if tok == PLUS || tok == MINUS {
@@ -3292,7 +3641,7 @@ func (p *Parser) parseSignedNumber(desc string) (*NumberLit, error) {
 
 	switch tok {
 	case FLOAT, INTEGER:
-		return &NumberLit{Value: lit}, nil
+		return &NumberLit{span: p.spanFrom(pos), Value: lit}, nil
 	default:
 		return nil, p.errorExpected(p.pos, p.tok, desc)
 	}
@@ -3302,7 +3651,7 @@ func (p *Parser) parseAlterTableStatement() (_ *AlterTableStatement, err error)
 	assert(p.peek() == ALTER)
 
 	var stmt AlterTableStatement
-	p.scan()
+	start, _, _ := p.scan()
 	if p.peek() != TABLE {
 		return &stmt, p.errorExpected(p.pos, p.tok, "TABLE")
 	}
@@ -3323,6 +3672,7 @@ func (p *Parser) parseAlterTableStatement() (_ *AlterTableStatement, err error)
 			if stmt.NewName, err = p.parseIdent("new table name"); err != nil {
 				return &stmt, err
 			}
+			stmt.span = p.spanFrom(start)
 			return &stmt, nil
 		}
 
@@ -3343,6 +3693,7 @@ func (p *Parser) parseAlterTableStatement() (_ *AlterTableStatement, err error)
 			return &stmt, err
 		}
 
+		stmt.span = p.spanFrom(start)
 		return &stmt, nil
 	case ADD:
 		p.scan()
@@ -3354,9 +3705,22 @@ func (p *Parser) parseAlterTableStatement() (_ *AlterTableStatement, err error)
 		if stmt.ColumnDef, err = p.parseColumnDefinition(); err != nil {
 			return &stmt, err
 		}
+		stmt.span = p.spanFrom(start)
+		return &stmt, nil
+	case DROP:
+		p.scan()
+		desc := "COLUMN keyword or column name"
+		if p.peek() == COLUMN {
+			p.scan()
+			desc = "column name"
+		}
+		if stmt.DropColumnName, err = p.parseIdent(desc); err != nil {
+			return &stmt, err
+		}
+		stmt.span = p.spanFrom(start)
 		return &stmt, nil
 	default:
-		return &stmt, p.errorExpected(p.pos, p.tok, "ADD or RENAME")
+		return &stmt, p.errorExpected(p.pos, p.tok, "ADD, DROP or RENAME")
 	}
 }
 
@@ -3364,7 +3728,7 @@ func (p *Parser) parsePragmaStatement() (_ *PragmaStatement, err error) {
 	assert(p.peek() == PRAGMA)
 
 	var stmt PragmaStatement
-	p.scan()
+	start, _, _ := p.scan()
 
 	lit, err := p.parseIdent("schema name")
 	if err != nil {
@@ -3391,9 +3755,10 @@ func (p *Parser) parsePragmaStatement() (_ *PragmaStatement, err error) {
 		}
 
 		stmt.Expr = &BinaryExpr{
-			X:  lit,
-			Op: OP_EQ,
-			Y:  rhs,
+			span: p.spanFrom(lit.pos),
+			X:    lit,
+			Op:   OP_EQ,
+			Y:    rhs,
 		}
 	case LP:
 		// Parse as function call: pragma-name(args)
@@ -3406,6 +3771,7 @@ func (p *Parser) parsePragmaStatement() (_ *PragmaStatement, err error) {
 		stmt.Expr = lit
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -3413,7 +3779,7 @@ func (p *Parser) parseAnalyzeStatement() (_ *AnalyzeStatement, err error) {
 	assert(p.peek() == ANALYZE)
 
 	var stmt AnalyzeStatement
-	p.scan()
+	start, _, _ := p.scan()
 
 	if isIdentToken(p.peek()) {
 		stmt.Name, err = p.parseQualifiedName(true, false, false, false, false)
@@ -3422,6 +3788,7 @@ func (p *Parser) parseAnalyzeStatement() (_ *AnalyzeStatement, err error) {
 		}
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -3429,7 +3796,7 @@ func (p *Parser) parseReindexStatement() (_ *ReindexStatement, err error) {
 	assert(p.peek() == REINDEX)
 
 	var stmt ReindexStatement
-	p.scan()
+	start, _, _ := p.scan()
 
 	// handle case with index, table or collation name
 	if tok := p.peek(); isIdentToken(tok) {
@@ -3439,6 +3806,7 @@ func (p *Parser) parseReindexStatement() (_ *ReindexStatement, err error) {
 		}
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
@@ -3455,12 +3823,89 @@ func (p *Parser) scan() (Pos, Token, string) {
 	}
 
 	// Continue scanning until we find a non-comment token.
+	p.prevEnd = p.end
 	for {
-		if pos, tok, lit := p.s.Scan(); tok != COMMENT {
-			p.pos, p.tok, p.lit = pos, tok, lit
-			return p.pos, p.tok, p.lit
+		pos, tok, lit := p.s.Scan()
+		if tok == COMMENT {
+			if !p.discardComments {
+				p.addComment(pos, lit)
+			}
+			continue
+		}
+
+		p.lastComment = false
+		p.pos, p.tok, p.lit, p.end = pos, tok, lit, p.s.pos
+		return p.pos, p.tok, p.lit
+	}
+}
+
+// addComment records a scanned comment. Consecutive comments are grouped
+// together unless separated by an empty line.
+func (p *Parser) addComment(pos Pos, lit string) {
+	// A line comment consumes its terminating newline, which is not part
+	// of the comment.
+	end := p.s.pos
+	if end.GetOffset() > pos.GetOffset()+len(lit) {
+		end = p.s.prev
+	}
+
+	c := &Comment{span: span{pos: pos, end: end}, Text: lit}
+	if n := len(p.comments); n > 0 && p.lastComment &&
+		strings.Count(p.s.s[p.comments[n-1].End().GetOffset():pos.GetOffset()], "\n") < 2 {
+		p.comments[n-1].List = append(p.comments[n-1].List, c)
+	} else {
+		p.comments = append(p.comments, &CommentGroup{List: []*Comment{c}})
+	}
+	p.lastComment = true
+}
+
+// enter increases the nesting depth. Returns an error, leaving the depth
+// unchanged, if the depth would exceed the configured maximum.
+func (p *Parser) enter() error {
+	if p.maxDepth > 0 && p.depth >= p.maxDepth {
+		return &Error{Pos: p.peekPos(), Msg: "maximum nesting depth exceeded"}
+	}
+	p.depth++
+	return nil
+}
+
+// leave decreases the nesting depth.
+func (p *Parser) leave() {
+	p.depth--
+}
+
+// lastEnd returns the end position of the last consumed token.
+func (p *Parser) lastEnd() Pos {
+	if p.full {
+		return p.prevEnd
+	}
+	return p.end
+}
+
+// quote returns the quote character of the identifier token at pos if it is
+// a backtick, a bracket or a single quote. Double quotes are the default and
+// return an empty string.
+func (p *Parser) quote(pos Pos, tok Token) string {
+	switch tok {
+	case STRING:
+		return "'"
+	case QIDENT:
+		if ch := p.s.s[pos.GetOffset()]; ch == '`' || ch == '[' {
+			return string(ch)
 		}
 	}
+	return ""
+}
+
+// peekPos returns the position of the next token without consuming it.
+func (p *Parser) peekPos() Pos {
+	p.peek()
+	return p.pos
+}
+
+// spanFrom returns the span from pos to the end of the last consumed token.
+func (p *Parser) spanFrom(pos Pos) span {
+	return span{pos: pos, end: p.lastEnd()}
 }
 
 // scanBinaryOp performs a scan but combines multi-word operations into a single token.
@@ -3592,12 +4037,12 @@ func (p *Parser) parseAttachStatement() (_ *AttachStatement, err error) {
 	assert(p.peek() == ATTACH)
 	var stmt AttachStatement
 
-	p.scan()
+	start, _, _ := p.scan()
 	if p.peek() == DATABASE {
 		p.scan()
 	}
 
-	if stmt.Expr, err = p.parseIdent("attach expr"); err != nil {
+	if stmt.Expr, err = p.ParseExpr(); err != nil {
 		return &stmt, err
 	}
 
@@ -3610,13 +4055,14 @@ func (p *Parser) parseAttachStatement() (_ *AttachStatement, err error) {
 		return &stmt, err
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
 func (p *Parser) parseDetachStatement() (_ *DetachStatement, err error) {
 	assert(p.peek() == DETACH)
 	var stmt DetachStatement
-	p.scan()
+	start, _, _ := p.scan()
 	if p.peek() == DATABASE {
 		p.scan()
 	}
@@ -3625,17 +4071,19 @@ func (p *Parser) parseDetachStatement() (_ *DetachStatement, err error) {
 		return &stmt, err
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
 func (p *Parser) parseVacuumStatement() (_ *VacuumStatement, err error) {
 	assert(p.peek() == VACUUM)
 	var stmt VacuumStatement
-	p.scan()
+	start, _, _ := p.scan()
 
 	switch p.peek() {
 	case INTO:
 	case EOF, SEMI:
+		stmt.span = p.spanFrom(start)
 		return &stmt, nil
 	default:
 		if stmt.Schema, err = p.parseIdent("schema name"); err != nil {
@@ -3646,18 +4094,19 @@ func (p *Parser) parseVacuumStatement() (_ *VacuumStatement, err error) {
 	// If the next token is "INTO", parse it.
 	if p.peek() == INTO {
 		p.scan()
-		if stmt.Expr, err = p.parseIdent("vacuum expr"); err != nil {
+		if stmt.Expr, err = p.ParseExpr(); err != nil {
 			return &stmt, err
 		}
 	}
 
+	stmt.span = p.spanFrom(start)
 	return &stmt, nil
 }
 
 func (p *Parser) parseConflictClause() (_ *ConflictClause, err error) {
 	assert(p.peek() == ON)
 	var clause ConflictClause
-	p.scan()
+	start, _, _ := p.scan()
 
 	if p.peek() != CONFLICT {
 		return &clause, p.errorExpected(p.pos, p.tok, "CONFLICT")
@@ -3679,6 +4128,7 @@ func (p *Parser) parseConflictClause() (_ *ConflictClause, err error) {
 	default:
 		return &clause, p.errorExpected(p.pos, p.tok, "ROLLBACK, ABORT, FAIL, IGNORE or REPLACE")
 	}
+	clause.span = p.spanFrom(start)
 	return &clause, nil
 }
 
@@ -3710,6 +4160,7 @@ func (p *Parser) parseFunctionArg() (_ *FunctionArg, err error) {
 		}
 	}
 
+	arg.span = p.spanFrom(arg.Expr.Pos())
 	return &arg, nil
 }
 
@@ -3749,6 +4200,50 @@ func (e Error) Error() string {
 	return e.Pos.String() + ": " + e.Msg
 }
 
+// ErrorList is a list of parse errors. It implements the error interface.
+type ErrorList []*Error
+
+// Error implements the error interface.
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return l[0].Error() + " (and " + strconv.Itoa(len(l)-1) + " more errors)"
+}
+
+// Err returns an error equivalent to this error list.
+// If the list is empty, Err returns nil.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+// Unwrap returns the errors of the list.
+func (l ErrorList) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, e := range l {
+		errs[i] = e
+	}
+	return errs
+}
+
+// isStatementStartToken returns true if tok is the initial token of a statement.
+func isStatementStartToken(tok Token) bool {
+	switch tok {
+	case EXPLAIN, PRAGMA, ANALYZE, REINDEX, ALTER, BEGIN, COMMIT, END, ROLLBACK,
+		SAVEPOINT, RELEASE, CREATE, DROP, SELECT, VALUES, INSERT, REPLACE, UPDATE,
+		DELETE, WITH, ATTACH, DETACH, VACUUM:
+		return true
+	default:
+		return false
+	}
+}
+
 // isConstraintStartToken returns true if tok is the initial token of a constraint.
 func isConstraintStartToken(tok Token, isTable bool) bool {
 	switch tok {
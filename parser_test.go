@@ -4,6 +4,7 @@
 package sql_test
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -106,10 +107,10 @@ func TestParser_ParseStatement(t *testing.T) {
 
 	t.Run("End", func(t *testing.T) {
 		t.Run("", func(t *testing.T) {
-			AssertParseStatement(t, `END`, &sql.CommitStatement{})
+			AssertParseStatement(t, `END`, &sql.CommitStatement{EndKeyword: true})
 		})
 		t.Run("Transaction", func(t *testing.T) {
-			AssertParseStatement(t, `END TRANSACTION`, &sql.CommitStatement{})
+			AssertParseStatement(t, `END TRANSACTION`, &sql.CommitStatement{EndKeyword: true})
 		})
 	})
 
@@ -1407,6 +1408,7 @@ func TestParser_ParseStatement(t *testing.T) {
 		AssertParseStatementError(t, `CREATE TRIGGER trig AFTER INSERT ON foo BEGIN UPDATE baz AS b SET x = 1 WHERE NEW.id = 1; END;;`, `1:58: expected SET, found 'AS'`)
 		AssertParseStatementError(t, `CREATE TRIGGER trig AFTER INSERT ON foo BEGIN UPDATE baz b SET x = 1 WHERE NEW.id = 1; END;;`, `1:58: expected SET, found b`)
 		AssertParseStatementError(t, `CREATE TRIGGER trig AFTER INSERT ON foo BEGIN UPDATE baz INDEXED BY id SET x = 1 WHERE NEW.id = 1; END;;`, `1:58: expected SET, found 'INDEXED'`)
+		AssertParseStatementError(t, `CREATE TRIGGER trig AFTER INSERT ON foo BEGIN UPDATE baz SET x = 1 FROM bar WHERE NEW.id = 1; END;;`, `1:68: FROM clause not allowed in UPDATE within a trigger`)
 		AssertParseStatementError(t, `CREATE TRIGGER trig AFTER INSERT ON foo BEGIN DELETE FROM baz AS b WHERE NEW.id = 1; END;;`, `1:63: expected semicolon, found 'AS'`)
 		AssertParseStatementError(t, `CREATE TRIGGER trig AFTER INSERT ON foo BEGIN DELETE FROM baz b WHERE NEW.id = 1; END;;`, `1:63: expected semicolon, found b`)
 	})
@@ -1972,6 +1974,33 @@ func TestParser_ParseStatement(t *testing.T) {
 				{Expr: &sql.Ident{Name: "bat"}},
 			},
 		})
+		AssertParseStatement(t, `WITH x AS MATERIALIZED (SELECT foo), y AS NOT MATERIALIZED (SELECT bar) SELECT baz`, &sql.SelectStatement{
+			WithClause: &sql.WithClause{
+				CTEs: []*sql.CTE{
+					{
+						TableName:    &sql.Ident{Name: "x"},
+						Materialized: pos(10),
+						Select: &sql.SelectStatement{
+							Columns: []*sql.ResultColumn{
+								{Expr: &sql.Ident{Name: "foo"}},
+							},
+						},
+					},
+					{
+						TableName:       &sql.Ident{Name: "y"},
+						NotMaterialized: pos(46),
+						Select: &sql.SelectStatement{
+							Columns: []*sql.ResultColumn{
+								{Expr: &sql.Ident{Name: "bar"}},
+							},
+						},
+					},
+				},
+			},
+			Columns: []*sql.ResultColumn{
+				{Expr: &sql.Ident{Name: "baz"}},
+			},
+		})
 		AssertParseStatement(t, `WITH RECURSIVE cte AS (SELECT foo) SELECT bar`, &sql.SelectStatement{
 			WithClause: &sql.WithClause{
 				Recursive: pos(5),
@@ -1991,6 +2020,12 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		})
 
+		AssertParseStatement(t, `SELECT prix_unitaire_€ FROM 商品`, &sql.SelectStatement{
+			Columns: []*sql.ResultColumn{
+				{Expr: &sql.Ident{Name: "prix_unitaire_€"}},
+			},
+			Source: &sql.QualifiedName{Name: &sql.Ident{Name: "商品"}},
+		})
 		AssertParseStatement(t, `SELECT * WHERE true`, &sql.SelectStatement{
 			Columns:   []*sql.ResultColumn{{Star: pos(7)}},
 			WhereExpr: &sql.BoolLit{Value: true},
@@ -2125,12 +2160,14 @@ func TestParser_ParseStatement(t *testing.T) {
 			LimitExpr:  &sql.NumberLit{Value: "1"},
 			OffsetExpr: &sql.NumberLit{Value: "2"},
 		})
+		// Like SQLite, "LIMIT x, y" skips x rows and returns at most y.
 		AssertParseStatement(t, `SELECT * LIMIT 1, 2`, &sql.SelectStatement{
 			Columns: []*sql.ResultColumn{
 				{Star: pos(7)},
 			},
-			LimitExpr:  &sql.NumberLit{Value: "1"},
-			OffsetExpr: &sql.NumberLit{Value: "2"},
+			LimitExpr:  &sql.NumberLit{Value: "2"},
+			OffsetExpr: &sql.NumberLit{Value: "1"},
+			LimitComma: true,
 		})
 		AssertParseStatement(t, `SELECT * UNION SELECT * ORDER BY foo`, &sql.SelectStatement{
 			Columns: []*sql.ResultColumn{
@@ -2320,6 +2357,7 @@ func TestParser_ParseStatement(t *testing.T) {
 		AssertParseStatementError(t, `WITH cte AS`, `1:12: expected left paren, found 'EOF'`)
 		AssertParseStatementError(t, `WITH cte AS (`, `1:14: expected SELECT or VALUES, found 'EOF'`)
 		AssertParseStatementError(t, `WITH cte AS (SELECT foo`, `1:24: expected right paren, found 'EOF'`)
+		AssertParseStatementError(t, `WITH cte AS NOT (SELECT foo) SELECT bar`, `1:17: expected MATERIALIZED, found '('`)
 		AssertParseStatementError(t, `WITH cte AS (SELECT foo)`, `1:25: expected SELECT, VALUES, INSERT, REPLACE, UPDATE, or DELETE, found 'EOF'`)
 		AssertParseStatementError(t, `SELECT `, `1:8: expected expression, found 'EOF'`)
 		AssertParseStatementError(t, `SELECT 1+`, `1:10: expected expression, found 'EOF'`)
@@ -2831,6 +2869,18 @@ func TestParser_ParseStatement(t *testing.T) {
 			LimitExpr:  &sql.NumberLit{Value: "7"},
 			OffsetExpr: &sql.NumberLit{Value: "0"},
 		})
+		AssertParseStatement(t, `UPDATE tbl SET x = 1 LIMIT 7, 1`, &sql.UpdateStatement{
+			Table: &sql.QualifiedName{
+				Name: &sql.Ident{Name: "tbl"},
+			},
+			Assignments: []*sql.Assignment{{
+				Columns: []*sql.Ident{{Name: "x"}},
+				Expr:    &sql.NumberLit{Value: "1"},
+			}},
+			LimitExpr:  &sql.NumberLit{Value: "1"},
+			OffsetExpr: &sql.NumberLit{Value: "7"},
+			LimitComma: true,
+		})
 		AssertParseStatement(t, `UPDATE tbl SET x = 1 WHERE y = 2`, &sql.UpdateStatement{
 			Table: &sql.QualifiedName{
 				Name: &sql.Ident{Name: "tbl"},
@@ -2895,6 +2945,60 @@ func TestParser_ParseStatement(t *testing.T) {
 				Expr:    &sql.NumberLit{Value: "1"},
 			}},
 		})
+		AssertParseStatement(t, `UPDATE inventory SET qty = d.qty FROM daily d WHERE inventory.id = d.id`, &sql.UpdateStatement{
+			Table: &sql.QualifiedName{
+				Name: &sql.Ident{Name: "inventory"},
+			},
+			Assignments: []*sql.Assignment{{
+				Columns: []*sql.Ident{{Name: "qty"}},
+				Expr: &sql.QualifiedRef{
+					Table:  &sql.QualifiedName{Name: &sql.Ident{Name: "d"}},
+					Column: &sql.Ident{Name: "qty"},
+				},
+			}},
+			Source: &sql.QualifiedName{
+				Name:  &sql.Ident{Name: "daily"},
+				Alias: &sql.Ident{Name: "d"},
+			},
+			WhereExpr: &sql.BinaryExpr{
+				X: &sql.QualifiedRef{
+					Table:  &sql.QualifiedName{Name: &sql.Ident{Name: "inventory"}},
+					Column: &sql.Ident{Name: "id"},
+				},
+				Op: sql.OP_EQ,
+				Y: &sql.QualifiedRef{
+					Table:  &sql.QualifiedName{Name: &sql.Ident{Name: "d"}},
+					Column: &sql.Ident{Name: "id"},
+				},
+			},
+		})
+		AssertParseStatement(t, `UPDATE tbl SET x = 1 FROM a JOIN b ON a.id = b.id`, &sql.UpdateStatement{
+			Table: &sql.QualifiedName{
+				Name: &sql.Ident{Name: "tbl"},
+			},
+			Assignments: []*sql.Assignment{{
+				Columns: []*sql.Ident{{Name: "x"}},
+				Expr:    &sql.NumberLit{Value: "1"},
+			}},
+			Source: &sql.JoinClause{
+				X:        &sql.QualifiedName{Name: &sql.Ident{Name: "a"}},
+				Operator: &sql.JoinOperator{},
+				Y:        &sql.QualifiedName{Name: &sql.Ident{Name: "b"}},
+				Constraint: &sql.OnConstraint{
+					X: &sql.BinaryExpr{
+						X: &sql.QualifiedRef{
+							Table:  &sql.QualifiedName{Name: &sql.Ident{Name: "a"}},
+							Column: &sql.Ident{Name: "id"},
+						},
+						Op: sql.OP_EQ,
+						Y: &sql.QualifiedRef{
+							Table:  &sql.QualifiedName{Name: &sql.Ident{Name: "b"}},
+							Column: &sql.Ident{Name: "id"},
+						},
+					},
+				},
+			},
+		})
 		AssertParseStatement(t, `WITH cte (x) AS (SELECT y) UPDATE tbl SET x = 1`, &sql.UpdateStatement{
 			WithClause: &sql.WithClause{
 				CTEs: []*sql.CTE{
@@ -2927,6 +3031,7 @@ func TestParser_ParseStatement(t *testing.T) {
 		AssertParseStatementError(t, `UPDATE tbl SET x = `, `1:20: expected expression, found 'EOF'`)
 		AssertParseStatementError(t, `UPDATE tbl SET x = 1 WHERE`, `1:27: expected expression, found 'EOF'`)
 		AssertParseStatementError(t, `UPDATE tbl SET x = 1 WHERE y =`, `1:31: expected expression, found 'EOF'`)
+		AssertParseStatementError(t, `UPDATE tbl SET x = 1 FROM`, `1:26: expected qualified name, found 'EOF'`)
 	})
 
 	t.Run("Delete", func(t *testing.T) {
@@ -2993,12 +3098,14 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 			LimitExpr: &sql.NumberLit{Value: "1"},
 		})
+		// Like SQLite, "LIMIT x, y" skips x rows and returns at most y.
 		AssertParseStatement(t, `DELETE FROM tbl LIMIT 1, 2`, &sql.DeleteStatement{
 			Table: &sql.QualifiedName{
 				Name: &sql.Ident{Name: "tbl"},
 			},
-			LimitExpr:  &sql.NumberLit{Value: "1"},
-			OffsetExpr: &sql.NumberLit{Value: "2"},
+			LimitExpr:  &sql.NumberLit{Value: "2"},
+			OffsetExpr: &sql.NumberLit{Value: "1"},
+			LimitComma: true,
 		})
 
 		AssertParseStatement(t, `DELETE FROM tbl1 WHERE id IN (SELECT tbl1_id FROM tbl2 WHERE foo = 'bar')`, &sql.DeleteStatement{
@@ -3073,10 +3180,29 @@ func TestParser_ParseStatement(t *testing.T) {
 				},
 			},
 		})
+		AssertParseStatement(t, `ALTER TABLE tbl DROP COLUMN col`, &sql.AlterTableStatement{
+			Name:           &sql.QualifiedName{Name: &sql.Ident{Name: "tbl"}},
+			DropColumnName: &sql.Ident{Name: "col"},
+		})
+		AssertParseStatement(t, `ALTER TABLE main.tbl DROP col`, &sql.AlterTableStatement{
+			Name: &sql.QualifiedName{
+				Schema: &sql.Ident{Name: "main"},
+				Name:   &sql.Ident{Name: "tbl"},
+			},
+			DropColumnName: &sql.Ident{Name: "col"},
+		})
+		AssertParseStatement(t, `ALTER TABLE tbl DROP key`, &sql.AlterTableStatement{
+			Name:           &sql.QualifiedName{Name: &sql.Ident{Name: "tbl"}},
+			DropColumnName: &sql.Ident{Name: "key"},
+		})
+		AssertParseStatement(t, `ALTER TABLE tbl DROP null`, &sql.AlterTableStatement{
+			Name:           &sql.QualifiedName{Name: &sql.Ident{Name: "tbl"}},
+			DropColumnName: &sql.Ident{Name: "null"},
+		})
 
 		AssertParseStatementError(t, `ALTER`, `1:6: expected TABLE, found 'EOF'`)
 		AssertParseStatementError(t, `ALTER TABLE`, `1:12: expected qualified name, found 'EOF'`)
-		AssertParseStatementError(t, `ALTER TABLE tbl`, `1:16: expected ADD or RENAME, found 'EOF'`)
+		AssertParseStatementError(t, `ALTER TABLE tbl`, `1:16: expected ADD, DROP or RENAME, found 'EOF'`)
 		AssertParseStatementError(t, `ALTER TABLE tbl RENAME`, `1:23: expected COLUMN keyword or column name, found 'EOF'`)
 		AssertParseStatementError(t, `ALTER TABLE tbl RENAME TO`, `1:26: expected new table name, found 'EOF'`)
 		AssertParseStatementError(t, `ALTER TABLE tbl RENAME COLUMN`, `1:30: expected column name, found 'EOF'`)
@@ -3084,6 +3210,9 @@ func TestParser_ParseStatement(t *testing.T) {
 		AssertParseStatementError(t, `ALTER TABLE tbl RENAME COLUMN col TO`, `1:37: expected new column name, found 'EOF'`)
 		AssertParseStatementError(t, `ALTER TABLE tbl ADD`, `1:20: expected COLUMN keyword or column name, found 'EOF'`)
 		AssertParseStatementError(t, `ALTER TABLE tbl ADD COLUMN`, `1:27: expected column name, found 'EOF'`)
+		AssertParseStatementError(t, `ALTER TABLE tbl DROP`, `1:21: expected COLUMN keyword or column name, found 'EOF'`)
+		AssertParseStatementError(t, `ALTER TABLE tbl DROP ;`, `1:22: expected COLUMN keyword or column name, found ';'`)
+		AssertParseStatementError(t, `ALTER TABLE tbl DROP COLUMN`, `1:28: expected column name, found 'EOF'`)
 	})
 
 	t.Run("Analyze", func(t *testing.T) {
@@ -3092,6 +3221,47 @@ func TestParser_ParseStatement(t *testing.T) {
 			Name: &sql.QualifiedName{Name: &sql.Ident{Name: "tbl"}},
 		})
 	})
+	t.Run("Attach", func(t *testing.T) {
+		AssertParseStatement(t, `ATTACH 'foo.db' AS foo`, &sql.AttachStatement{
+			Expr:   &sql.StringLit{Value: "foo.db"},
+			Schema: &sql.Ident{Name: "foo"},
+		})
+		AssertParseStatement(t, `ATTACH DATABASE 'file:' || :name || '?mode=ro' AS aux`, &sql.AttachStatement{
+			Expr: &sql.BinaryExpr{
+				X: &sql.BinaryExpr{
+					X:  &sql.StringLit{Value: "file:"},
+					Op: sql.OP_CONCAT,
+					Y:  &sql.BindExpr{Name: ":name"},
+				},
+				Op: sql.OP_CONCAT,
+				Y:  &sql.StringLit{Value: "?mode=ro"},
+			},
+			Schema: &sql.Ident{Name: "aux"},
+		})
+
+		AssertParseStatementError(t, `ATTACH`, `1:7: expected expression, found 'EOF'`)
+		AssertParseStatementError(t, `ATTACH 'foo.db'`, `1:16: expected AS, found 'EOF'`)
+	})
+	t.Run("Vacuum", func(t *testing.T) {
+		AssertParseStatement(t, `VACUUM`, &sql.VacuumStatement{})
+		AssertParseStatement(t, `VACUUM main INTO ?`, &sql.VacuumStatement{
+			Schema: &sql.Ident{Name: "main"},
+			Expr:   &sql.BindExpr{Name: "?"},
+		})
+		AssertParseStatement(t, `VACUUM INTO 'backup-' || ?1 || '.db'`, &sql.VacuumStatement{
+			Expr: &sql.BinaryExpr{
+				X: &sql.BinaryExpr{
+					X:  &sql.StringLit{Value: "backup-"},
+					Op: sql.OP_CONCAT,
+					Y:  &sql.BindExpr{Name: "?1"},
+				},
+				Op: sql.OP_CONCAT,
+				Y:  &sql.StringLit{Value: ".db"},
+			},
+		})
+
+		AssertParseStatementError(t, `VACUUM INTO`, `1:12: expected expression, found 'EOF'`)
+	})
 	t.Run("Reindex", func(t *testing.T) {
 		AssertParseStatement(t, `REINDEX`, &sql.ReindexStatement{})
 		AssertParseStatement(t, `REINDEX tbl`, &sql.ReindexStatement{
@@ -3134,6 +3304,8 @@ func TestParser_ParseExpr(t *testing.T) {
 	})
 	t.Run("UnaryExpr", func(t *testing.T) {
 		AssertParseExpr(t, `-123`, &sql.UnaryExpr{Op: sql.OP_MINUS, X: &sql.NumberLit{Value: `123`}})
+		AssertParseExpr(t, `+123`, &sql.UnaryExpr{Op: sql.OP_PLUS, X: &sql.NumberLit{Value: `123`}})
+		AssertParseExpr(t, `- -1`, &sql.UnaryExpr{Op: sql.OP_MINUS, X: &sql.UnaryExpr{Op: sql.OP_MINUS, X: &sql.NumberLit{Value: `1`}}})
 		AssertParseExpr(t, `NOT foo`, &sql.UnaryExpr{Op: sql.OP_NOT, X: &sql.Ident{Name: "foo"}})
 		AssertParseExpr(t, `~1`, &sql.UnaryExpr{Op: sql.OP_BITNOT, X: &sql.NumberLit{Value: "1"}})
 		AssertParseExprError(t, `-`, `1:2: expected expression, found 'EOF'`)
@@ -3147,6 +3319,10 @@ func TestParser_ParseExpr(t *testing.T) {
 			Table:  &sql.QualifiedName{Name: &sql.Ident{Name: "tbl", Quoted: true}},
 			Column: &sql.Ident{Name: "col", Quoted: true},
 		})
+		AssertParseExpr(t, "`tbl`.[col]", &sql.QualifiedRef{
+			Table:  &sql.QualifiedName{Name: &sql.Ident{Name: "tbl", Quoted: true, Quote: "`"}},
+			Column: &sql.Ident{Name: "col", Quoted: true, Quote: "["},
+		})
 		AssertParseExprError(t, `tbl.`, `1:5: expected column name, found 'EOF'`)
 	})
 	t.Run("Exists", func(t *testing.T) {
@@ -3252,6 +3428,18 @@ func TestParser_ParseExpr(t *testing.T) {
 			Op: sql.OP_NE,
 			Y:  &sql.NumberLit{Value: "2"},
 		})
+		AssertParseExpr(t, `1 <> 2'`, &sql.BinaryExpr{
+			X:     &sql.NumberLit{Value: "1"},
+			Op:    sql.OP_NE,
+			AltOp: true,
+			Y:     &sql.NumberLit{Value: "2"},
+		})
+		AssertParseExpr(t, `1 == 2'`, &sql.BinaryExpr{
+			X:     &sql.NumberLit{Value: "1"},
+			Op:    sql.OP_EQ,
+			AltOp: true,
+			Y:     &sql.NumberLit{Value: "2"},
+		})
 		AssertParseExpr(t, `(1 + 2)'`, &sql.ParenExpr{Expr: &sql.BinaryExpr{
 			X:  &sql.NumberLit{Value: "1"},
 			Op: sql.OP_PLUS,
@@ -3790,6 +3978,203 @@ func Test_ParseMultiStmtString(t *testing.T) {
 	}
 }
 
+func Test_ParseMultiStmtStringAllErrors(t *testing.T) {
+	s := `SELECT 1;
+SELECT FROM;
+CREATE TABLE t (a INTEGER
+CREATE TABLE u (b TEXT);
+INSERT INTO t VALUES (;
+DELETE FROM t`
+
+	var got []string
+	err := sql.ParseMultiStmtStringAllErrors(s, func(stmt sql.Statement) error {
+		got = append(got, stmt.String())
+		return nil
+	})
+
+	expected := []string{
+		`SELECT 1`,
+		`CREATE TABLE "u" ("b" TEXT)`,
+		`DELETE FROM "t"`,
+	}
+	if diff := deep.Equal(got, expected); diff != nil {
+		t.Fatal(diff)
+	}
+
+	var errs sql.ErrorList
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ErrorList, got %T", err)
+	}
+	if got, want := errs.Error(), `2:8: expected expression, found 'FROM' (and 2 more errors)`; got != want {
+		t.Fatalf("Error()=%s, want %s", got, want)
+	}
+	if got, want := errs[1].Error(), `4:1: expected column name, CONSTRAINT, or right paren, found 'CREATE'`; got != want {
+		t.Fatalf("Error()=%s, want %s", got, want)
+	}
+	if got, want := errs[2].Error(), `5:23: expected expression, found ';'`; got != want {
+		t.Fatalf("Error()=%s, want %s", got, want)
+	}
+}
+
+// Ensure recovery stops at a semicolon consumed by the failing statement.
+func Test_ParseMultiStmtStringAllErrors_Semicolon(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want []string
+	}{
+		{`SELECT 1 +; SELECT 2;`, []string{`SELECT 2`}},
+		{`DROP TABLE ; SELECT 1; SELECT 2`, []string{`SELECT 1`, `SELECT 2`}},
+		{`; SELECT 1`, []string{`SELECT 1`}},
+		{"CREATE TRIGGER tr AFTER INSERT ON t BEGIN\n UPDATE u SET x = ;\n DELETE FROM v;\nEND;\nSELECT 1;", []string{`SELECT 1`}},
+		{"CREATE TRIGGER tr AFTER INSERT ON t BEGIN\n SELECT CASE x WHEN 1 THEN 2 END FROM u WHERE ;\n DELETE FROM v;\nEND;\nSELECT 1;", []string{`SELECT 1`}},
+		{"CREATE TRIGGER tr AFTER BOGUS ON t\nBEGIN\n DELETE FROM v;\nEND;\nSELECT 1;", []string{`SELECT 1`}},
+		{"SELECT CASE WHEN ; THEN 1 END;\nSELECT 1;", []string{`SELECT 1`}},
+	} {
+		t.Run(tt.s, func(t *testing.T) {
+			var got []string
+			err := sql.ParseMultiStmtStringAllErrors(tt.s, func(stmt sql.Statement) error {
+				got = append(got, stmt.String())
+				return nil
+			})
+			if errs, ok := err.(sql.ErrorList); !ok || len(errs) != 1 {
+				t.Fatalf("unexpected error: %v", err)
+			} else if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestNewParser(t *testing.T) {
+	t.Run("KeepComments", func(t *testing.T) {
+		stmt, err := sql.NewParser(`-- foo
+SELECT 1`).ParseStatement()
+		if err != nil {
+			t.Fatal(err)
+		} else if got, want := stmt.LeadingComments().Text(), "foo"; got != want {
+			t.Fatalf("LeadingComments()=%q, want %q", got, want)
+		}
+
+		stmt, err = sql.NewParser(`-- foo
+SELECT 1`, sql.KeepComments(false)).ParseStatement()
+		if err != nil {
+			t.Fatal(err)
+		} else if stmt.LeadingComments() != nil {
+			t.Fatalf("unexpected comments: %q", stmt.LeadingComments().Text())
+		}
+	})
+
+	t.Run("ContinueOnError", func(t *testing.T) {
+		var n int
+		yield := func(sql.Statement) error { n++; return nil }
+
+		s := `SELECT 1; SELECT FROM; SELECT 2`
+		if err := sql.NewParser(s).ParseMultiStatements(s, yield); err == nil || err.Error() != `1:18: expected expression, found 'FROM'` {
+			t.Fatalf("unexpected error: %v", err)
+		} else if n != 1 {
+			t.Fatalf("expected 1 statement, got %d", n)
+		}
+
+		n = 0
+		if err := sql.NewParser(s, sql.ContinueOnError(true)).ParseMultiStatements(s, yield); err == nil || err.Error() != `1:18: expected expression, found 'FROM'` {
+			t.Fatalf("unexpected error: %v", err)
+		} else if _, ok := err.(sql.ErrorList); !ok {
+			t.Fatalf("expected ErrorList, got %T", err)
+		} else if n != 2 {
+			t.Fatalf("expected 2 statements, got %d", n)
+		}
+	})
+
+	t.Run("MaxDepth", func(t *testing.T) {
+		p := sql.NewParser(`SELECT ((1))`, sql.MaxDepth(4))
+		if _, err := p.ParseStatement(); err != nil {
+			t.Fatal(err)
+		}
+
+		p.Reset(`SELECT (((1)))`)
+		if _, err := p.ParseStatement(); err == nil || err.Error() != `1:11: maximum nesting depth exceeded` {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		p.Reset(`SELECT * FROM (SELECT * FROM (SELECT * FROM (SELECT 1)))`)
+		if _, err := p.ParseStatement(); err == nil || err.Error() != `1:53: maximum nesting depth exceeded` {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		p.Reset(`SELECT * FROM (((t)))`)
+		if _, err := p.ParseStatement(); err != nil {
+			t.Fatal(err)
+		}
+
+		p.Reset(`SELECT * FROM ((((t))))`)
+		if _, err := p.ParseStatement(); err == nil || err.Error() != `1:19: maximum nesting depth exceeded` {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		p := sql.NewParser(`SELECT 1`)
+		if _, err := p.ParseStatement(); err != nil {
+			t.Fatal(err)
+		}
+
+		p.Reset(`SELECT 2`)
+		if stmt, err := p.ParseStatement(); err != nil {
+			t.Fatal(err)
+		} else if got, want := stmt.String(), `SELECT 2`; got != want {
+			t.Fatalf("String()=%s, want %s", got, want)
+		}
+	})
+
+	t.Run("ParseMultiStatements", func(t *testing.T) {
+		var got []string
+		yield := func(stmt sql.Statement) error {
+			got = append(got, stmt.String())
+			return nil
+		}
+
+		// An empty string parses the source of the parser.
+		if err := sql.NewParser(`SELECT 1; SELECT 2`).ParseMultiStatements("", yield); err != nil {
+			t.Fatal(err)
+		} else if diff := deep.Equal(got, []string{`SELECT 1`, `SELECT 2`}); diff != nil {
+			t.Fatal(diff)
+		}
+
+		got = nil
+		if err := sql.NewParser(`SELECT 1`).ParseMultiStatements(`SELECT 3`, yield); err != nil {
+			t.Fatal(err)
+		} else if diff := deep.Equal(got, []string{`SELECT 3`}); diff != nil {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("ZeroValue", func(t *testing.T) {
+		var p sql.Parser
+		var got []string
+		if err := p.ParseMultiStatements(`SELECT 1; SELECT 2`, func(stmt sql.Statement) error {
+			got = append(got, stmt.String())
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if diff := deep.Equal(got, []string{`SELECT 1`, `SELECT 2`}); diff != nil {
+			t.Fatal(diff)
+		}
+	})
+}
+
+func TestErrorList_Err(t *testing.T) {
+	var errs sql.ErrorList
+	if err := errs.Err(); err != nil {
+		t.Fatalf("Err()=%v, want nil", err)
+	}
+
+	errs = append(errs, &sql.Error{Msg: "test"})
+	if err := errs.Err(); err == nil || err.Error() != `-: test` {
+		t.Fatalf("Err()=%v, want -: test", err)
+	}
+}
+
 func TestError_Error(t *testing.T) {
 	err := &sql.Error{Msg: "test"}
 	if got, want := err.Error(), `-: test`; got != want {
@@ -26,3 +26,9 @@ func (p Pos) Increase(_ byte) Pos {
 func (p Pos) GetOffset() int {
 	return int(p)
 }
+
+// Position returns the line & column of p within src, the string that was
+// scanned to produce p.
+func (p Pos) Position(src string) Position {
+	return positionFor(src, int(p))
+}
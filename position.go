@@ -0,0 +1,41 @@
+package sql
+
+import "strconv"
+
+// Position describes a source position including line and column.
+type Position struct {
+	Offset int // offset, starting at 0 (byte offset)
+	Line   int // line number, starting at 1
+	Column int // column number, starting at 1 (byte count)
+}
+
+// IsValid reports whether the position is valid.
+func (p Position) IsValid() bool {
+	return p.Line > 0 && p.Column > 0 && p.Offset >= 0
+}
+
+// String returns a string representation of the position in the form "line:column".
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	return strconv.Itoa(p.Line) + ":" + strconv.Itoa(p.Column)
+}
+
+// positionFor computes the line & column of offset in src.
+func positionFor(src string, offset int) Position {
+	if offset > len(src) {
+		offset = len(src)
+	}
+
+	pos := Position{Offset: offset, Line: 1, Column: 1}
+	for i := 0; i < offset; i++ {
+		if src[i] == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+	}
+	return pos
+}
@@ -0,0 +1,81 @@
+package sql_test
+
+import (
+	"testing"
+
+	"github.com/TcMits/sql"
+)
+
+func spanString(src string, n sql.Node) string {
+	return n.Pos().Position(src).String() + "-" + n.End().Position(src).String()
+}
+
+func Test_Span_Statement(t *testing.T) {
+	src := "SELECT a, b + 1 AS c\nFROM foo f\nJOIN bar USING (id)\nWHERE x IS NULL;"
+	stmt, err := sql.ParseStmtString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sel := stmt.(*sql.SelectStatement)
+	for _, tt := range []struct {
+		node sql.Node
+		want string
+	}{
+		{sel, "1:1-4:16"},
+		{sel.Columns[0], "1:8-1:9"},
+		{sel.Columns[1], "1:11-1:21"},
+		{sel.Columns[1].Expr, "1:11-1:16"},
+		{sel.Columns[1].Alias, "1:20-1:21"},
+		{sel.Source, "2:6-3:20"},
+		{sel.Source.(*sql.JoinClause).X, "2:6-2:11"},
+		{sel.Source.(*sql.JoinClause).Operator, "3:1-3:5"},
+		{sel.Source.(*sql.JoinClause).Constraint, "3:10-3:20"},
+		{sel.WhereExpr, "4:7-4:16"},
+	} {
+		if got := spanString(src, tt.node); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.node, got, tt.want)
+		}
+	}
+
+	if got, want := sel.End().Position(src).Offset, len(src)-1; got != want {
+		t.Errorf("statement end offset: got %d, want %d", got, want)
+	}
+}
+
+func Test_Span_Expr(t *testing.T) {
+	for _, tt := range []struct {
+		src  string
+		want string
+	}{
+		{"foo", "1:1-1:4"},
+		{"'foo'", "1:1-1:6"},
+		{"-1", "1:1-1:3"},
+		{"NOT EXISTS (SELECT 1)", "1:1-1:22"},
+		{"a BETWEEN 1 AND 2", "1:1-1:18"},
+		{"t.col", "1:1-1:6"},
+		{"count(*) OVER (PARTITION BY a)", "1:1-1:31"},
+		{"CAST(x AS INTEGER)", "1:1-1:19"},
+		{"CASE WHEN a THEN b END", "1:1-1:23"},
+		{"(1, 2)", "1:1-1:7"},
+		{"x IN (1, 2)", "1:1-1:12"},
+	} {
+		expr, err := sql.ParseExprString(tt.src)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+
+		if got := spanString(tt.src, expr); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func Test_Position_String(t *testing.T) {
+	if got := (sql.Position{}).String(); got != "-" {
+		t.Errorf("got %s, want -", got)
+	}
+	if got := (sql.Position{Offset: 3, Line: 2, Column: 1}).String(); got != "2:1" {
+		t.Errorf("got %s, want 2:1", got)
+	}
+}
@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"iter"
 	"strings"
 	"unsafe"
 )
@@ -36,7 +37,7 @@ func (s *Scanner) Scan() (pos Pos, token Token, lit string) {
 			return s.scanNumber()
 		} else if ch == 'x' || ch == 'X' {
 			return s.scanBlob()
-		} else if isAlpha(ch) || ch == '_' {
+		} else if isAlpha(ch) || ch == '_' || isNonASCII(ch) {
 			return s.scanUnquotedIdent()
 		} else if ch == '"' || ch == '`' || ch == '[' {
 			return s.scanQuotedIdent()
@@ -129,6 +130,54 @@ func (s *Scanner) Scan() (pos Pos, token Token, lit string) {
 	}
 }
 
+// RawToken is a token together with its exact source text.
+type RawToken struct {
+	Pos  Pos    // position of the first byte of the token
+	End  Pos    // position immediately after the token
+	Tok  Token  // token type, SPACE or COMMENT for trivia
+	Text string // source text of the token, including quotes & comment markers
+}
+
+// IsTrivia returns true if the token is whitespace or a comment.
+func (t RawToken) IsTrivia() bool {
+	return t.Tok == SPACE || t.Tok == COMMENT
+}
+
+// ScanRaw returns the next token from the input string without skipping
+// trivia. Runs of whitespace are returned as SPACE tokens and comments as
+// COMMENT tokens; a line comment does not include its terminating newline.
+// Concatenating the Text of every token before EOF reproduces the input
+// byte for byte.
+func (s *Scanner) ScanRaw() RawToken {
+	start := s.pos
+	if isSpace(s.peek()) {
+		for isSpace(s.peek()) {
+			s.read()
+		}
+		return RawToken{Pos: start, End: s.pos, Tok: SPACE, Text: s.s[start.GetOffset():s.pos.GetOffset()]}
+	}
+
+	pos, tok, _ := s.Scan()
+	if tok == COMMENT && strings.HasPrefix(s.s[pos.GetOffset():], "--") && s.s[s.pos.GetOffset()-1] == '\n' {
+		s.unread() // leave the newline to the next SPACE token
+	}
+	return RawToken{Pos: pos, End: s.pos, Tok: tok, Text: s.s[pos.GetOffset():s.pos.GetOffset()]}
+}
+
+// Tokens returns an iterator over the raw tokens of src, including trivia and
+// excluding the final EOF. See Scanner.ScanRaw.
+func Tokens(src string) iter.Seq[RawToken] {
+	return func(yield func(RawToken) bool) {
+		s := NewScanner(src)
+		for {
+			t := s.ScanRaw()
+			if t.Tok == EOF || !yield(t) {
+				return
+			}
+		}
+	}
+}
+
 func (s *Scanner) scanUnquotedIdent() (Pos, Token, string) {
 	assert(isUnquotedIdent(s.peek()))
 
@@ -397,8 +446,14 @@ func isHex(ch byte) bool {
 	return isDigit(ch) || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
 }
 
+// isNonASCII returns true if ch is part of a multi-byte UTF-8 sequence. Like
+// SQLite, every such byte is treated as an identifier character.
+func isNonASCII(ch byte) bool {
+	return ch >= 0x80
+}
+
 func isUnquotedIdent(ch byte) bool {
-	return isAlpha(ch) || isDigit(ch) || ch == '_'
+	return isAlpha(ch) || isDigit(ch) || ch == '_' || isNonASCII(ch)
 }
 
 // IsInteger returns true if s only contains digits.
@@ -1,9 +1,13 @@
 package sql_test
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/TcMits/sql"
+	"github.com/go-test/deep"
 )
 
 func TestScanner_Scan(t *testing.T) {
@@ -25,6 +29,12 @@ func TestScanner_Scan(t *testing.T) {
 		t.Run("StartingX", func(t *testing.T) {
 			AssertScan(t, `xyz`, sql.IDENT, `xyz`)
 		})
+		t.Run("Unicode", func(t *testing.T) {
+			AssertScan(t, `prix_unitaire_€`, sql.IDENT, `prix_unitaire_€`)
+			AssertScan(t, `商品`, sql.IDENT, `商品`)
+			AssertScan(t, `xé`, sql.IDENT, `xé`)
+			AssertScan(t, `état1 `, sql.IDENT, `état1`)
+		})
 	})
 
 	t.Run("COMMENT", func(t *testing.T) {
@@ -110,6 +120,7 @@ func TestScanner_Scan(t *testing.T) {
 		AssertScan(t, `:foo_bar123'`, sql.BIND, `:foo_bar123`)
 		AssertScan(t, `@bar'`, sql.BIND, `@bar`)
 		AssertScan(t, `$baz'`, sql.BIND, `$baz`)
+		AssertScan(t, `:prénom'`, sql.BIND, `:prénom`)
 	})
 
 	t.Run("EOF", func(t *testing.T) {
@@ -196,6 +207,85 @@ func TestScanner_Scan(t *testing.T) {
 	})
 }
 
+func TestScanner_ScanRaw(t *testing.T) {
+	src := "SELECT a,  -- note\n\t\"b c\" /* x */FROM t;\n"
+
+	type token struct {
+		Tok  sql.Token
+		Text string
+	}
+	var got []token
+	for tok := range sql.Tokens(src) {
+		got = append(got, token{tok.Tok, tok.Text})
+	}
+	if diff := deep.Equal(got, []token{
+		{sql.SELECT, "SELECT"},
+		{sql.SPACE, " "},
+		{sql.IDENT, "a"},
+		{sql.COMMA, ","},
+		{sql.SPACE, "  "},
+		{sql.COMMENT, "-- note"},
+		{sql.SPACE, "\n\t"},
+		{sql.QIDENT, `"b c"`},
+		{sql.SPACE, " "},
+		{sql.COMMENT, "/* x */"},
+		{sql.FROM, "FROM"},
+		{sql.SPACE, " "},
+		{sql.IDENT, "t"},
+		{sql.SEMI, ";"},
+		{sql.SPACE, "\n"},
+	}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	t.Run("Offsets", func(t *testing.T) {
+		s := sql.NewScanner(src)
+		end := 0
+		for {
+			tok := s.ScanRaw()
+			if tok.Pos.GetOffset() != end || src[tok.Pos.GetOffset():tok.End.GetOffset()] != tok.Text {
+				t.Fatalf("unexpected offsets for %s %q: %d-%d", tok.Tok, tok.Text, tok.Pos.GetOffset(), tok.End.GetOffset())
+			}
+			end = tok.End.GetOffset()
+			if tok.Tok == sql.EOF {
+				break
+			}
+		}
+		if end != len(src) {
+			t.Fatalf("EOF at %d, want %d", end, len(src))
+		}
+	})
+
+	t.Run("Lossless", func(t *testing.T) {
+		files, err := filepath.Glob("testdata/*.sql")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range append(files, "scanner_test.go") {
+			buf, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sb strings.Builder
+			for tok := range sql.Tokens(string(buf)) {
+				sb.WriteString(tok.Text)
+			}
+			if sb.String() != string(buf) {
+				t.Fatalf("%s: tokens do not reproduce the input", file)
+			}
+		}
+		for _, src := range []string{"", "--", "-- x\r\n", "/* open", "'open", "x'0g'", "\x00\xff", "a\n\n\n"} {
+			var sb strings.Builder
+			for tok := range sql.Tokens(src) {
+				sb.WriteString(tok.Text)
+			}
+			if sb.String() != src {
+				t.Fatalf("tokens of %q reproduce %q", src, sb.String())
+			}
+		}
+	})
+}
+
 // AssertScan asserts the value of the first scan to s.
 func AssertScan(tb testing.TB, s string, expectedTok sql.Token, expectedLit string) {
 	tb.Helper()
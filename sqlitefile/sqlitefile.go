@@ -0,0 +1,399 @@
+// Package sqlitefile reads the schema of a SQLite database file without cgo
+// or the sqlite3 library.
+//
+// The schema is stored in the sqlite_schema table, a table b-tree rooted on
+// page 1 of the file. Each row holds the type, name and SQL text of a table,
+// index, view or trigger. The SQL text is parsed into statements.
+//
+// Only the main database file is read. Changes that are still in a
+// write-ahead log have not been checkpointed and are not visible.
+//
+// See: https://www.sqlite.org/fileformat.html
+package sqlitefile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"unicode/utf16"
+
+	"github.com/TcMits/sql"
+)
+
+// header is the magic string at the start of every database file.
+const header = "SQLite format 3\x00"
+
+// Object represents a row of the sqlite_schema table.
+type Object struct {
+	Type     string        // "table", "index", "view" or "trigger"
+	Name     string        // name of the object
+	TblName  string        // name of the table or view the object is associated with
+	RootPage int           // root b-tree page of tables & indexes, zero otherwise
+	SQL      string        // original SQL text, empty for automatic indexes
+	Stmt     sql.Statement // parsed SQL text, nil if SQL is empty
+}
+
+// ReadFile reads the schema of the database file at path.
+func ReadFile(path string) ([]*Object, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read reads the schema of the database file r. Objects are returned in the
+// order of the sqlite_schema table.
+func Read(r io.ReaderAt) ([]*Object, error) {
+	db, err := open(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []*Object
+	err = db.walk(1, make(map[uint32]bool), func(payload []byte) error {
+		values, err := db.record(payload)
+		if err != nil {
+			return err
+		}
+		obj, err := newObject(values)
+		if err != nil {
+			return err
+		}
+		objs = append(objs, obj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+
+// newObject returns the object of a sqlite_schema row.
+func newObject(values []any) (*Object, error) {
+	if len(values) < 5 {
+		return nil, fmt.Errorf("sqlitefile: sqlite_schema row has %d columns, want 5", len(values))
+	}
+
+	var obj Object
+	obj.Type, _ = values[0].(string)
+	obj.Name, _ = values[1].(string)
+	obj.TblName, _ = values[2].(string)
+	if rootPage, ok := values[3].(int64); ok {
+		obj.RootPage = int(rootPage)
+	}
+	obj.SQL, _ = values[4].(string)
+
+	if obj.SQL != "" {
+		stmt, err := sql.ParseStmtString(obj.SQL)
+		if err != nil {
+			return nil, fmt.Errorf("sqlitefile: %s %s: %w", obj.Type, obj.Name, err)
+		}
+		obj.Stmt = stmt
+	}
+	return &obj, nil
+}
+
+// database is an open database file.
+type database struct {
+	r        io.ReaderAt
+	pageSize int
+	usable   int    // usable size of a page, excluding the reserved space
+	pages    uint32 // number of pages, zero if unknown
+	encoding uint32 // text encoding: 1 UTF-8, 2 UTF-16le, 3 UTF-16be
+}
+
+// open reads the header of the database file r.
+func open(r io.ReaderAt) (*database, error) {
+	buf := make([]byte, 100)
+	if _, err := r.ReadAt(buf, 0); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("sqlitefile: file is not a database")
+		}
+		return nil, err
+	}
+	if string(buf[:16]) != header {
+		return nil, errors.New("sqlitefile: file is not a database")
+	}
+
+	db := &database{r: r, pageSize: int(binary.BigEndian.Uint16(buf[16:]))}
+	if db.pageSize == 1 {
+		db.pageSize = 65536
+	}
+	if db.pageSize < 512 || db.pageSize&(db.pageSize-1) != 0 {
+		return nil, fmt.Errorf("sqlitefile: invalid page size %d", db.pageSize)
+	}
+	db.usable = db.pageSize - int(buf[20])
+	if db.usable < 480 {
+		return nil, fmt.Errorf("sqlitefile: invalid reserved space %d", buf[20])
+	}
+
+	// The page count is only valid if the change counter matches.
+	if binary.BigEndian.Uint32(buf[24:]) == binary.BigEndian.Uint32(buf[92:]) {
+		db.pages = binary.BigEndian.Uint32(buf[28:])
+	}
+
+	db.encoding = binary.BigEndian.Uint32(buf[56:])
+	switch db.encoding {
+	case 0:
+		db.encoding = 1
+	case 1, 2, 3:
+	default:
+		return nil, fmt.Errorf("sqlitefile: invalid text encoding %d", db.encoding)
+	}
+	return db, nil
+}
+
+// page returns the contents of page number n, starting at 1.
+func (db *database) page(n uint32) ([]byte, error) {
+	if n == 0 || (db.pages != 0 && n > db.pages) {
+		return nil, fmt.Errorf("sqlitefile: page %d out of range", n)
+	}
+	buf := make([]byte, db.pageSize)
+	if _, err := db.r.ReadAt(buf, int64(n-1)*int64(db.pageSize)); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("sqlitefile: page %d out of range", n)
+		}
+		return nil, err
+	}
+	return buf, nil
+}
+
+// B-tree page types.
+const (
+	interiorTable = 0x05
+	leafTable     = 0x0d
+)
+
+// walk calls fn with the payload of each cell of the table b-tree rooted at
+// page n, in rowid order. Pages in seen are corrupt references and fail.
+func (db *database) walk(n uint32, seen map[uint32]bool, fn func(payload []byte) error) error {
+	if seen[n] {
+		return fmt.Errorf("sqlitefile: page %d referenced more than once", n)
+	}
+	seen[n] = true
+
+	buf, err := db.page(n)
+	if err != nil {
+		return err
+	}
+
+	// The first 100 bytes of page 1 hold the database header.
+	hdr := buf
+	if n == 1 {
+		hdr = buf[100:]
+	}
+
+	typ := hdr[0]
+	if typ != interiorTable && typ != leafTable {
+		return fmt.Errorf("sqlitefile: page %d: unexpected page type %#x", n, typ)
+	}
+	cells := int(binary.BigEndian.Uint16(hdr[3:]))
+	ptrs := hdr[8:]
+	if typ == interiorTable {
+		ptrs = hdr[12:]
+	}
+	if len(ptrs) < 2*cells {
+		return fmt.Errorf("sqlitefile: page %d: too many cells", n)
+	}
+
+	for i := 0; i < cells; i++ {
+		off := int(binary.BigEndian.Uint16(ptrs[2*i:]))
+		if off >= len(buf) {
+			return fmt.Errorf("sqlitefile: page %d: cell offset out of range", n)
+		}
+		cell := buf[off:]
+
+		switch typ {
+		case interiorTable:
+			if len(cell) < 4 {
+				return fmt.Errorf("sqlitefile: page %d: truncated cell", n)
+			}
+			if err := db.walk(binary.BigEndian.Uint32(cell), seen, fn); err != nil {
+				return err
+			}
+		case leafTable:
+			payload, err := db.payload(cell)
+			if err != nil {
+				return fmt.Errorf("sqlitefile: page %d: %w", n, err)
+			}
+			if err := fn(payload); err != nil {
+				return err
+			}
+		}
+	}
+
+	if typ == interiorTable {
+		return db.walk(binary.BigEndian.Uint32(hdr[8:]), seen, fn)
+	}
+	return nil
+}
+
+// payload returns the payload of a table leaf cell, following overflow pages.
+func (db *database) payload(cell []byte) ([]byte, error) {
+	size, n := varint(cell)
+	if n == 0 {
+		return nil, errors.New("truncated cell")
+	}
+	cell = cell[n:]
+	if _, n = varint(cell); n == 0 { // rowid
+		return nil, errors.New("truncated cell")
+	}
+	cell = cell[n:]
+
+	// The payload cannot be larger than the file, if its size is known.
+	if db.pages != 0 && size > uint64(db.pages)*uint64(db.usable) {
+		return nil, fmt.Errorf("invalid payload size %d", size)
+	}
+
+	// Compute the number of bytes stored on the page itself.
+	u := uint64(db.usable)
+	local := size
+	if maxLocal := u - 35; size > maxLocal {
+		minLocal := (u-12)*32/255 - 23
+		local = minLocal + (size-minLocal)%(u-4)
+		if local > maxLocal {
+			local = minLocal
+		}
+	}
+	if uint64(len(cell)) < local || (local < size && uint64(len(cell)) < local+4) {
+		return nil, errors.New("truncated cell")
+	}
+
+	// The payload grows as overflow pages are read, so a corrupt size does
+	// not allocate more than the file holds.
+	payload := append([]byte(nil), cell[:local]...)
+	if local == size {
+		return payload, nil
+	}
+
+	seen := make(map[uint32]bool)
+	for next := binary.BigEndian.Uint32(cell[local:]); uint64(len(payload)) < size; {
+		if next == 0 || seen[next] {
+			return nil, errors.New("invalid overflow chain")
+		}
+		seen[next] = true
+
+		buf, err := db.page(next)
+		if err != nil {
+			return nil, err
+		}
+		next = binary.BigEndian.Uint32(buf)
+		chunk := buf[4:db.usable]
+		if remaining := size - uint64(len(payload)); uint64(len(chunk)) > remaining {
+			chunk = chunk[:remaining]
+		}
+		payload = append(payload, chunk...)
+	}
+	return payload, nil
+}
+
+// record decodes the values of a record. Values are nil, int64, float64,
+// string or []byte.
+func (db *database) record(payload []byte) ([]any, error) {
+	hdrSize, n := varint(payload)
+	if n == 0 || hdrSize < uint64(n) || hdrSize > uint64(len(payload)) {
+		return nil, errors.New("sqlitefile: invalid record header")
+	}
+
+	hdr, body := payload[n:hdrSize], payload[hdrSize:]
+	var values []any
+	for len(hdr) > 0 {
+		typ, n := varint(hdr)
+		if n == 0 {
+			return nil, errors.New("sqlitefile: invalid record header")
+		}
+		hdr = hdr[n:]
+
+		size := serialSize(typ)
+		if uint64(len(body)) < size {
+			return nil, errors.New("sqlitefile: truncated record")
+		}
+		values = append(values, db.value(typ, body[:size]))
+		body = body[size:]
+	}
+	return values, nil
+}
+
+// serialSize returns the size of a value of serial type typ.
+func serialSize(typ uint64) uint64 {
+	switch {
+	case typ <= 4:
+		return [...]uint64{0, 1, 2, 3, 4}[typ]
+	case typ == 5:
+		return 6
+	case typ == 6, typ == 7:
+		return 8
+	case typ < 12:
+		return 0
+	default:
+		return (typ - 12) / 2
+	}
+}
+
+// value decodes a value of serial type typ.
+func (db *database) value(typ uint64, buf []byte) any {
+	switch {
+	case typ == 0:
+		return nil
+	case typ <= 6:
+		// Big-endian two's complement integer.
+		v := int64(int8(buf[0]))
+		for _, b := range buf[1:] {
+			v = v<<8 | int64(b)
+		}
+		return v
+	case typ == 7:
+		return math.Float64frombits(binary.BigEndian.Uint64(buf))
+	case typ == 8:
+		return int64(0)
+	case typ == 9:
+		return int64(1)
+	case typ < 12:
+		return nil
+	case typ%2 == 0:
+		return append([]byte(nil), buf...)
+	default:
+		return db.text(buf)
+	}
+}
+
+// text decodes text in the encoding of the database.
+func (db *database) text(buf []byte) string {
+	if db.encoding == 1 {
+		return string(buf)
+	}
+
+	order := binary.ByteOrder(binary.LittleEndian)
+	if db.encoding == 3 {
+		order = binary.BigEndian
+	}
+	u := make([]uint16, len(buf)/2)
+	for i := range u {
+		u[i] = order.Uint16(buf[2*i:])
+	}
+	return string(utf16.Decode(u))
+}
+
+// varint decodes a SQLite variable-length integer from buf. It returns the
+// value and the number of bytes read, or zero bytes if buf is too short.
+func varint(buf []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		if i >= len(buf) {
+			return 0, 0
+		}
+		if i == 8 {
+			return v<<8 | uint64(buf[i]), 9
+		}
+		v = v<<7 | uint64(buf[i]&0x7f)
+		if buf[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v, 9
+}
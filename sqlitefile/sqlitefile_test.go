@@ -0,0 +1,158 @@
+package sqlitefile_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/TcMits/sql"
+	"github.com/TcMits/sql/catalog"
+	"github.com/TcMits/sql/sqlitefile"
+	"github.com/go-test/deep"
+)
+
+// MustReadFile returns the schema of the database file at path. Fail on error.
+func MustReadFile(tb testing.TB, path string) []*sqlitefile.Object {
+	tb.Helper()
+	objs, err := sqlitefile.ReadFile(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return objs
+}
+
+func TestReadFile(t *testing.T) {
+	objs := MustReadFile(t, "testdata/schema.db")
+
+	type object struct {
+		Type, Name, TblName string
+		RootPage            int
+		SQL                 string
+	}
+	var got []object
+	for _, obj := range objs {
+		got = append(got, object{obj.Type, obj.Name, obj.TblName, obj.RootPage, obj.SQL})
+	}
+	if diff := deep.Equal(got, []object{
+		{"table", "users", "users", 2, "CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT UNIQUE NOT NULL, name TEXT)"},
+		{"index", "sqlite_autoindex_users_1", "users", 3, ""},
+		{"index", "users_name", "users", 4, "CREATE INDEX users_name ON users (name)"},
+		{"view", "user_names", "user_names", 0, "CREATE VIEW user_names AS SELECT name FROM users"},
+		{"trigger", "users_ai", "users", 0, "CREATE TRIGGER users_ai AFTER INSERT ON users BEGIN SELECT 1; END"},
+	}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	for _, obj := range objs {
+		if obj.SQL == "" {
+			if obj.Stmt != nil {
+				t.Errorf("%s: unexpected statement", obj.Name)
+			}
+			continue
+		}
+		want, err := sql.ParseStmtString(obj.SQL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sql.Equal(obj.Stmt, want) {
+			t.Errorf("%s: statement mismatch: %s", obj.Name, obj.Stmt)
+		}
+	}
+
+	t.Run("Catalog", func(t *testing.T) {
+		c := catalog.New()
+		for _, obj := range objs {
+			if obj.Stmt == nil {
+				continue
+			}
+			if err := c.Exec(obj.Stmt); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if c.Table("users") == nil || c.Index("users_name") == nil || c.View("user_names") == nil || c.Trigger("users_ai") == nil {
+			t.Fatal("expected schema objects in catalog")
+		}
+	})
+}
+
+// Ensure schemas spanning interior pages and overflow pages are read in order.
+func TestReadFile_Large(t *testing.T) {
+	objs := MustReadFile(t, "testdata/large.db")
+	if len(objs) != 40 {
+		t.Fatalf("len=%d, want 40", len(objs))
+	}
+
+	var overflow bool
+	for i, obj := range objs {
+		if want := fmt.Sprintf("t%d", i); obj.Name != want {
+			t.Fatalf("objs[%d].Name=%q, want %q", i, obj.Name, want)
+		}
+		stmt, ok := obj.Stmt.(*sql.CreateTableStatement)
+		if !ok {
+			t.Fatalf("%s: unexpected statement %T", obj.Name, obj.Stmt)
+		}
+		if got, want := len(stmt.Columns), i%7*10+1; got != want {
+			t.Fatalf("%s: len(Columns)=%d, want %d", obj.Name, got, want)
+		}
+		if last := stmt.Columns[len(stmt.Columns)-1]; last.Name.Name != fmt.Sprintf("column_%d_%d", i, i%7*10) {
+			t.Fatalf("%s: unexpected last column %s", obj.Name, last.Name.Name)
+		}
+		overflow = overflow || len(obj.SQL) > 512
+	}
+	if !overflow {
+		t.Fatal("expected schema entries larger than a page")
+	}
+}
+
+func TestReadFile_UTF16(t *testing.T) {
+	objs := MustReadFile(t, "testdata/utf16.db")
+	if len(objs) != 1 {
+		t.Fatalf("len=%d, want 1", len(objs))
+	}
+	if got, want := objs[0].Name, "héllo"; got != want {
+		t.Fatalf("Name=%q, want %q", got, want)
+	}
+	if got, want := objs[0].SQL, `CREATE TABLE "héllo" ("wörld" TEXT)`; got != want {
+		t.Fatalf("SQL=%q, want %q", got, want)
+	}
+}
+
+func TestRead_Error(t *testing.T) {
+	buf, err := os.ReadFile("testdata/schema.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		buf  []byte
+		want string
+	}{
+		{"Empty", nil, "sqlitefile: file is not a database"},
+		{"NotDatabase", []byte(strings.Repeat("x", 100)), "sqlitefile: file is not a database"},
+		{"PageSize", patch(buf, 16, 0x03, 0x00), "sqlitefile: invalid page size 768"},
+		{"Encoding", patch(buf, 56, 0, 0, 0, 4), "sqlitefile: invalid text encoding 4"},
+		{"PageType", patch(buf, 100, 0x0a), "sqlitefile: page 1: unexpected page type 0xa"},
+		{"Truncated", buf[:512], "sqlitefile: page 1 out of range"},
+		{"EmptyPageType", patch(buf, 100, 0x0a, 0x00, 0x00, 0x00, 0x00), "sqlitefile: page 1: unexpected page type 0xa"},
+		{"PayloadSize", patch(patch(buf, 108, 0x00, 0xc8), 200, 0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00, 0x01), "sqlitefile: page 1: invalid payload size 1125899906842624"},
+		{"PayloadSizeUnknownPages", patch(patch(patch(buf, 92, 0xff), 108, 0x00, 0xc8), 200, 0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00, 0x01), "sqlitefile: page 1: invalid overflow chain"},
+		{"RecordHeader", patch(buf, 3940, 0x00), "sqlitefile: invalid record header"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sqlitefile.Read(bytes.NewReader(tt.buf))
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("Read()=%v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// patch returns a copy of buf with b written at offset off.
+func patch(buf []byte, off int, b ...byte) []byte {
+	buf = bytes.Clone(buf)
+	copy(buf[off:], b)
+	return buf
+}
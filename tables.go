@@ -0,0 +1,142 @@
+package sql
+
+import (
+	"slices"
+	"strings"
+)
+
+// TableName is the name of a table referenced by a statement.
+type TableName struct {
+	Schema string // schema name, empty if unqualified
+	Name   string // table or view name
+}
+
+// String returns the name as "schema.name", or "name" if unqualified.
+func (n TableName) String() string {
+	if n.Schema != "" {
+		return n.Schema + "." + n.Name
+	}
+	return n.Name
+}
+
+// Tables returns the tables read and written by stmt, each once and in order
+// of appearance. Names are compared case-insensitively.
+//
+// Tables are read by FROM clauses, "IN table" expressions and subqueries
+// anywhere in the statement, including the bodies of common table
+// expressions. References to common table expressions and table-valued
+// functions are excluded. Tables are written by INSERT, UPDATE and DELETE, and
+// by statements that create, alter or drop them. Views are included as written.
+//
+// EXPLAIN and CREATE TRIGGER statements do not execute their statements and
+// therefore neither read nor write tables.
+func Tables(stmt Statement) (read, written []TableName) {
+	var c tableCollector
+	c.visit(stmt, nil)
+	return c.read, c.written
+}
+
+type tableCollector struct {
+	read    []TableName
+	written []TableName
+}
+
+// visit collects the tables referenced within n. ctes holds the names of the
+// common table expressions in scope.
+func (c *tableCollector) visit(n Node, ctes []string) {
+	if w := withClause(n); w != nil {
+		// Each CTE is visible to itself and the CTEs & statement after it.
+		ctes = slices.Clip(ctes)
+		for _, cte := range w.CTEs {
+			ctes = append(ctes, cte.TableName.Name)
+			c.visit(cte.Select, ctes)
+		}
+	}
+
+	Apply(n, func(cur *Cursor) bool {
+		node := cur.Node()
+		if node != n {
+			if _, ok := node.(*WithClause); ok {
+				return false
+			} else if withClause(node) != nil {
+				c.visit(node, ctes)
+				return false
+			}
+		}
+
+		switch node := node.(type) {
+		case *ExplainStatement, *CreateTriggerStatement:
+			return false
+		case *CreateViewStatement:
+			c.written = appendTable(c.written, node.Name.Schema, node.Name.Name)
+			return false
+		case *AlterTableStatement:
+			c.written = appendTable(c.written, node.Name.Schema, node.Name.Name)
+			if node.NewName != nil {
+				c.written = appendTable(c.written, node.Name.Schema, node.NewName)
+			}
+			return false
+		case *QualifiedName:
+			c.qualifiedName(node, cur, ctes)
+		}
+		return true
+	}, nil)
+}
+
+// qualifiedName collects name if it is a table referenced by its parent.
+func (c *tableCollector) qualifiedName(name *QualifiedName, cur *Cursor, ctes []string) {
+	switch cur.Parent().(type) {
+	case *InsertStatement, *UpdateStatement, *DeleteStatement:
+		if cur.Name() == "Table" {
+			c.written = appendTable(c.written, name.Schema, name.Name)
+			return
+		}
+	case *CreateTableStatement, *CreateVirtualTableStatement, *DropTableStatement, *DropViewStatement:
+		c.written = appendTable(c.written, name.Schema, name.Name)
+		return
+	case *InExpr:
+	default:
+		if _, ok := cur.Parent().(Source); !ok && cur.Name() != "Source" {
+			return
+		}
+	}
+
+	// Remaining names are sources of a FROM clause or IN expression.
+	if name.FunctionCall {
+		return
+	} else if name.Schema == nil && slices.ContainsFunc(ctes, func(cte string) bool { return strings.EqualFold(cte, name.Name.Name) }) {
+		return
+	}
+	c.read = appendTable(c.read, name.Schema, name.Name)
+}
+
+// withClause returns the WITH clause of the statement n, if any.
+func withClause(n Node) *WithClause {
+	switch n := n.(type) {
+	case *SelectStatement:
+		return n.WithClause
+	case *InsertStatement:
+		return n.WithClause
+	case *UpdateStatement:
+		return n.WithClause
+	case *DeleteStatement:
+		return n.WithClause
+	}
+	return nil
+}
+
+// appendTable appends the table schema.name to a unless already present.
+func appendTable(a []TableName, schema, name *Ident) []TableName {
+	var tbl TableName
+	if schema != nil {
+		tbl.Schema = schema.Name
+	}
+	tbl.Name = name.Name
+
+	if slices.ContainsFunc(a, func(t TableName) bool {
+		return strings.EqualFold(t.Schema, tbl.Schema) && strings.EqualFold(t.Name, tbl.Name)
+	}) {
+		return a
+	}
+	return append(a, tbl)
+}
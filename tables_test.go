@@ -0,0 +1,85 @@
+package sql_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TcMits/sql"
+	"github.com/go-test/deep"
+)
+
+func Test_Tables(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		s       string
+		read    []string
+		written []string
+	}{
+		{"Select", `SELECT * FROM a, main.b AS x JOIN (SELECT * FROM c) USING (id)`, []string{"a", "main.b", "c"}, nil},
+		{"Subquery", `SELECT (SELECT 1 FROM a) FROM b WHERE EXISTS (SELECT 1 FROM c) AND x IN (SELECT y FROM d)`, []string{"a", "b", "c", "d"}, nil},
+		{"InTable", `SELECT 1 WHERE x IN a AND y IN temp.b AND z NOT IN json_each('[]')`, []string{"a", "temp.b"}, nil},
+		{"TableFunction", `SELECT * FROM a, json_each(a.x, (SELECT y FROM b))`, []string{"a", "b"}, nil},
+		{"CTE", `WITH x AS (SELECT * FROM a), y AS (SELECT * FROM x, b) SELECT * FROM y, main.x`, []string{"a", "b", "main.x"}, nil},
+		{"CTEOrder", `WITH x AS (SELECT * FROM y), y AS (SELECT 1) SELECT * FROM y`, []string{"y"}, nil},
+		{"CTEScope", `SELECT (WITH x AS (SELECT 1) SELECT * FROM x) FROM x`, []string{"x"}, nil},
+		{"RecursiveCTE", `WITH RECURSIVE n (i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n) SELECT * FROM n`, nil, nil},
+		{"Compound", `SELECT * FROM a UNION SELECT * FROM b ORDER BY (SELECT 1 FROM c)`, []string{"a", "b", "c"}, nil},
+		{"Duplicates", `SELECT * FROM a, A, "a", main.a`, []string{"a", "main.a"}, nil},
+		{"Insert", `WITH x AS (SELECT * FROM a) INSERT INTO x SELECT * FROM x`, []string{"a"}, []string{"x"}},
+		{"InsertUpsert", `INSERT INTO t (a) VALUES ((SELECT 1 FROM u)) ON CONFLICT (a) DO UPDATE SET b = (SELECT 2 FROM v) RETURNING (SELECT 3 FROM w)`, []string{"u", "v", "w"}, []string{"t"}},
+		{"Update", `UPDATE main.t AS x SET a = b.a FROM b WHERE x.id IN (SELECT id FROM c)`, []string{"b", "c"}, []string{"main.t"}},
+		{"Delete", `DELETE FROM t WHERE id IN (SELECT id FROM u) RETURNING *`, []string{"u"}, []string{"t"}},
+		{"CreateTable", `CREATE TABLE t AS SELECT * FROM u`, []string{"u"}, []string{"t"}},
+		{"CreateVirtualTable", `CREATE VIRTUAL TABLE temp.t USING fts5(x)`, nil, []string{"temp.t"}},
+		{"CreateView", `CREATE VIEW v AS SELECT * FROM t`, nil, []string{"v"}},
+		{"AlterTable", `ALTER TABLE main.t RENAME TO u`, nil, []string{"main.t", "main.u"}},
+		{"AlterTableColumn", `ALTER TABLE t ADD COLUMN c`, nil, []string{"t"}},
+		{"DropTable", `DROP TABLE IF EXISTS t`, nil, []string{"t"}},
+		{"DropView", `DROP VIEW v`, nil, []string{"v"}},
+		{"CreateIndex", `CREATE INDEX i ON t (a)`, nil, nil},
+		{"CreateTrigger", `CREATE TRIGGER tr AFTER INSERT ON t BEGIN DELETE FROM u; END`, nil, nil},
+		{"Explain", `EXPLAIN QUERY PLAN DELETE FROM t`, nil, nil},
+		{"Pragma", `PRAGMA table_info(t)`, nil, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := sql.ParseStmtString(tt.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			read, written := sql.Tables(stmt)
+			if diff := deep.Equal(tableNames(read), tt.read); diff != nil {
+				t.Errorf("read: %v", diff)
+			}
+			if diff := deep.Equal(tableNames(written), tt.written); diff != nil {
+				t.Errorf("written: %v", diff)
+			}
+		})
+	}
+}
+
+// Ensure Tables handles every statement of the test data.
+func Test_Tables_TestData(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		buf, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = sql.ParseMultiStmtString(string(buf), func(stmt sql.Statement) error {
+			sql.Tables(stmt)
+			return nil
+		})
+	}
+}
+
+func tableNames(a []sql.TableName) []string {
+	var names []string
+	for _, name := range a {
+		names = append(names, name.String())
+	}
+	return names
+}
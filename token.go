@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"fmt"
 	"strconv"
 )
 
@@ -516,6 +517,72 @@ const (
 	OP_BITNOT
 )
 
+var opTypes = [...]string{
+	OP_ILLEGAL:              "ILLEGAL",
+	OP_OR:                   "OR",
+	OP_AND:                  "AND",
+	OP_NOT:                  "NOT",
+	OP_ISNULL:               "ISNULL",
+	OP_NOTNULL:              "NOTNULL",
+	OP_IN:                   "IN",
+	OP_NOT_IN:               "NOT_IN",
+	OP_MATCH:                "MATCH",
+	OP_NOT_MATCH:            "NOT_MATCH",
+	OP_LIKE:                 "LIKE",
+	OP_NOT_LIKE:             "NOT_LIKE",
+	OP_REGEXP:               "REGEXP",
+	OP_NOT_REGEXP:           "NOT_REGEXP",
+	OP_GLOB:                 "GLOB",
+	OP_NOT_GLOB:             "NOT_GLOB",
+	OP_BETWEEN:              "BETWEEN",
+	OP_NOT_BETWEEN:          "NOT_BETWEEN",
+	OP_IS_DISTINCT_FROM:     "IS_DISTINCT_FROM",
+	OP_IS_NOT_DISTINCT_FROM: "IS_NOT_DISTINCT_FROM",
+	OP_EQ:                   "EQ",
+	OP_NE:                   "NE",
+	OP_IS:                   "IS",
+	OP_IS_NOT:               "IS_NOT",
+	OP_LT:                   "LT",
+	OP_LE:                   "LE",
+	OP_GT:                   "GT",
+	OP_GE:                   "GE",
+	OP_ESCAPE:               "ESCAPE",
+	OP_BITAND:               "BITAND",
+	OP_BITOR:                "BITOR",
+	OP_LSHIFT:               "LSHIFT",
+	OP_RSHIFT:               "RSHIFT",
+	OP_PLUS:                 "PLUS",
+	OP_MINUS:                "MINUS",
+	OP_MULTIPLY:             "MULTIPLY",
+	OP_DIVIDE:               "DIVIDE",
+	OP_MODULO:               "MODULO",
+	OP_CONCAT:               "CONCAT",
+	OP_JSON_EXTRACT_JSON:    "JSON_EXTRACT_JSON",
+	OP_JSON_EXTRACT_SQL:     "JSON_EXTRACT_SQL",
+	OP_COLLATE:              "COLLATE",
+	OP_BITNOT:               "BITNOT",
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. The operator is
+// encoded as its constant name without the OP_ prefix, e.g. "NOT_IN".
+func (op OpType) MarshalText() ([]byte, error) {
+	if op < 0 || int(op) >= len(opTypes) {
+		return nil, fmt.Errorf("invalid operator type %d", int(op))
+	}
+	return []byte(opTypes[op]), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (op *OpType) UnmarshalText(text []byte) error {
+	for i, name := range opTypes {
+		if name == string(text) {
+			*op = OpType(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid operator type %q", text)
+}
+
 func (op OpType) Precedence() int {
 	switch {
 	case op < OP_OR || op > OP_BITNOT: